@@ -0,0 +1,153 @@
+package clock
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// Times of day, as fractions of a day starting at sunrise
+const (
+	Sunrise  = 0.0
+	Noon     = 0.25
+	Sunset   = 0.5
+	Midnight = 0.75
+)
+
+// MoonPhases is the number of moon phases; the moon moves one phase each night
+const MoonPhases = 8
+
+// Phase is a part of the day
+type Phase int
+
+const (
+	Dawn Phase = iota
+	Day
+	Dusk
+	Night
+)
+
+func (p Phase) String() string {
+	switch p {
+	case Dawn:
+		return "Dawn"
+	case Day:
+		return "Day"
+	case Dusk:
+		return "Dusk"
+	case Night:
+		return "Night"
+	}
+	return "Unknown"
+}
+
+// Clock is a world's time of day. It only moves when Advance is called, once per
+// simulation tick, so it stays in step with the rest of the world.
+type Clock struct {
+	Ticks     uint64 // Ticks since the world was created
+	DayLength uint64 // Ticks in one day
+}
+
+// New creates a clock for a new world, starting at settings.StartTimeOfDay.
+// A dayLength of 0 uses settings.DayLength.
+func New(dayLength uint64) *Clock {
+	if dayLength == 0 {
+		dayLength = settings.DayLength
+	}
+	c := &Clock{DayLength: dayLength}
+	c.SetTimeOfDay(settings.StartTimeOfDay)
+	return c
+}
+
+// Advance moves the clock forward one tick
+func (c *Clock) Advance() {
+	c.Ticks++
+}
+
+// Day returns the number of whole days since the world was created
+func (c *Clock) Day() uint64 {
+	return c.Ticks / c.DayLength
+}
+
+// TimeOfDay returns how far through the current day the clock is, from 0 (sunrise)
+// up to but not including 1
+func (c *Clock) TimeOfDay() float64 {
+	return float64(c.Ticks%c.DayLength) / float64(c.DayLength)
+}
+
+// SetTimeOfDay jumps to a time of day (e.g. clock.Sunset) on the current day
+func (c *Clock) SetTimeOfDay(t float64) {
+	t -= math.Floor(t)
+	c.Ticks = c.Day()*c.DayLength + uint64(t*float64(c.DayLength))
+}
+
+// Phase returns the part of the day the clock is in. Dawn and dusk each last
+// settings.TwilightLength of a day, centred on sunrise and sunset.
+func (c *Clock) Phase() Phase {
+	t := c.TimeOfDay()
+	half := settings.TwilightLength / 2
+	switch {
+	case t < Sunrise+half || t >= 1-half:
+		return Dawn
+	case t < Sunset-half:
+		return Day
+	case t < Sunset+half:
+		return Dusk
+	}
+	return Night
+}
+
+// IsNight reports whether the sun is down, which is when hostile creatures spawn
+func (c *Clock) IsNight() bool {
+	return c.Phase() == Night
+}
+
+// SunHeight returns how far the sun is up: 1 during the day, 0 at night, and
+// rising or falling through dawn and dusk
+func (c *Clock) SunHeight() float64 {
+	t := c.TimeOfDay()
+	tw := settings.TwilightLength
+	switch {
+	case t < tw/2:
+		return 0.5 + t/tw
+	case t < Sunset-tw/2:
+		return 1
+	case t < Sunset+tw/2:
+		return 0.5 - (t-Sunset)/tw
+	case t < 1-tw/2:
+		return 0
+	}
+	return (t - (1 - tw/2)) / tw
+}
+
+// Twilight returns how strongly dawn or dusk colours the sky, peaking at 1 at
+// sunrise and sunset
+func (c *Clock) Twilight() float64 {
+	return 1 - math.Abs(2*c.SunHeight()-1)
+}
+
+// MoonPhase returns the phase of tonight's moon (or last night's, before noon):
+// 0 is a new moon and MoonPhases/2 a full moon
+func (c *Clock) MoonPhase() int {
+	return int((c.Ticks+c.DayLength/2)/c.DayLength) % MoonPhases
+}
+
+// Moonlight returns how much light the moon gives at night, brightest at full moon
+func (c *Clock) Moonlight() float64 {
+	fullness := 1 - math.Abs(float64(c.MoonPhase())-MoonPhases/2)/(MoonPhases/2)
+	return settings.NightLightNewMoon + (settings.NightLightFullMoon-settings.NightLightNewMoon)*fullness
+}
+
+// Daylight returns the strength of sunlight, from the moonlight at night up to 1 at
+// midday. The lighting engine scales sunlit blocks by it.
+func (c *Clock) Daylight() float64 {
+	night := c.Moonlight()
+	return night + (1-night)*c.SunHeight()
+}
+
+// String formats the clock for debug output, e.g. "Day 3 14:30 (Day, moon 2/8)"
+func (c *Clock) String() string {
+	minutes := int(c.TimeOfDay()*24*60) + 6*60 // Sunrise is at 06:00
+	return fmt.Sprintf("Day %d %02d:%02d (%s, moon %d/%d)", c.Day(), minutes/60%24, minutes%60, c.Phase(), c.MoonPhase(), MoonPhases)
+}
@@ -1,59 +1,183 @@
 package coretypes
 
-var blockNames = [...]string{
-	"Air",
-	"Grass",
-	"Dirt",
-	"Clay",
-	"Stone",
-	"Granite",
-	"Andesite",
-	"Diorite",
-	"Slate",
-	"Copper Ore",
-	"Iron Ore",
-	"Gold Ore",
-	"Ash",
-	"Wood",
-	"Leaves",
-	"Water",
-	"Hellstone",
-}
-
-func (b BlockType) String() string {
-	if b >= 0 && int(b) < len(blockNames) {
-		return blockNames[b]
-	}
-	return "Unknown"
-}
-
 type BlockType int
 
-const (
-	Air BlockType = iota
+// Air is always block 0 so freshly allocated chunks start empty
+const Air BlockType = 0
+
+// Built-in blocks that game code refers to directly. Their IDs come from the block
+// registry, which binds them by name when it is installed.
+var (
 	// Surface blocks
-	Grass
-	Dirt
-	Clay
+	Grass       BlockType
+	Dirt        BlockType
+	Clay        BlockType
+	Sand        BlockType // Desert surface
+	Sandstone   BlockType // Under desert sand
+	Snow        BlockType // Snow biome surface
+	Mud         BlockType // Under jungle grass
+	JungleGrass BlockType
+	SpookyGrass BlockType // Spooky biome surface
 	// Stone variants
-	Stone
-	Granite  // Underground stone variant: gray-pink
-	Andesite // Underground stone variant: bluish-gray
-	Diorite  // Underground stone variant: white-gray
-	Slate    // Underground stone variant: dark gray
+	Stone    BlockType
+	Granite  BlockType // Underground stone variant: gray-pink
+	Andesite BlockType // Underground stone variant: bluish-gray
+	Diorite  BlockType // Underground stone variant: white-gray
+	Slate    BlockType // Underground stone variant: dark gray
 	// Ore blocks
-	CopperOre
-	IronOre
-	GoldOre
+	CopperOre BlockType
+	IronOre   BlockType
+	GoldOre   BlockType
 	// Underground blocks
-	Ash
+	Ash BlockType
 	// Tree blocks
-	Wood
-	Leaves
+	Wood   BlockType
+	Leaves BlockType
 	// Liquids
-	Water
+	Water BlockType
+	Lava  BlockType
 	// Hell/Underworld blocks
-	Hellstone
+	Hellstone BlockType
+	Obsidian  BlockType // Left behind where lava meets water
+	// Light sources
+	Torch BlockType
 )
 
-const NumBlockTypes = int(Hellstone) + 1
+// builtinBlocks maps the registry names of the built-in blocks to their variables
+var builtinBlocks = map[string]*BlockType{
+	"Grass":        &Grass,
+	"Dirt":         &Dirt,
+	"Clay":         &Clay,
+	"Sand":         &Sand,
+	"Sandstone":    &Sandstone,
+	"Snow":         &Snow,
+	"Mud":          &Mud,
+	"Jungle Grass": &JungleGrass,
+	"Spooky Grass": &SpookyGrass,
+	"Stone":        &Stone,
+	"Granite":      &Granite,
+	"Andesite":     &Andesite,
+	"Diorite":      &Diorite,
+	"Slate":        &Slate,
+	"Copper Ore":   &CopperOre,
+	"Iron Ore":     &IronOre,
+	"Gold Ore":     &GoldOre,
+	"Ash":          &Ash,
+	"Wood":         &Wood,
+	"Leaves":       &Leaves,
+	"Water":        &Water,
+	"Lava":         &Lava,
+	"Hellstone":    &Hellstone,
+	"Obsidian":     &Obsidian,
+	"Torch":        &Torch,
+}
+
+func (b BlockType) String() string {
+	if def := blocks.Def(b); def != nil {
+		return def.Name
+	}
+	return "Unknown"
+}
+
+// BlockTypeByName returns the block type with the given display name
+func BlockTypeByName(name string) (BlockType, bool) {
+	return blocks.ByName(name)
+}
+
+// NumBlockTypes returns the number of blocks in the registry
+func NumBlockTypes() int {
+	return blocks.Len()
+}
+
+// Def returns the block's registry definition, or nil if it is not registered
+func (b BlockType) Def() *BlockDef {
+	return blocks.Def(b)
+}
+
+// IsSolid reports whether entities collide with the block
+func (b BlockType) IsSolid() bool {
+	def := blocks.Def(b)
+	return def != nil && def.Solid
+}
+
+// IsLiquid reports whether the block is a liquid
+func (b BlockType) IsLiquid() bool {
+	def := blocks.Def(b)
+	return def != nil && def.Liquid
+}
+
+// Burns reports whether entities touching the block catch fire
+func (b BlockType) Burns() bool {
+	def := blocks.Def(b)
+	return def != nil && def.Burns
+}
+
+// IsPlaceable reports whether players can place the block
+func (b BlockType) IsPlaceable() bool {
+	def := blocks.Def(b)
+	return def != nil && def.Placeable
+}
+
+// IsBreakable reports whether players can break the block
+func (b BlockType) IsBreakable() bool {
+	def := blocks.Def(b)
+	return b != Air && def != nil && def.Hardness >= 0
+}
+
+// Hardness returns how long the block takes to break; negative means unbreakable
+func (b BlockType) Hardness() float64 {
+	if def := blocks.Def(b); def != nil {
+		return def.Hardness
+	}
+	return -1
+}
+
+// HarvestTier returns the pickaxe tier needed for the block to drop anything when broken
+func (b BlockType) HarvestTier() ToolTier {
+	if def := blocks.Def(b); def != nil {
+		return def.Tier
+	}
+	return TierHand
+}
+
+// Drop returns the block given to the player when this block is broken (Air for nothing)
+func (b BlockType) Drop() BlockType {
+	if def := blocks.Def(b); def != nil {
+		return def.drop
+	}
+	return Air
+}
+
+// LightEmission returns the light level the block gives off, 0 to MaxLight
+func (b BlockType) LightEmission() int {
+	if def := blocks.Def(b); def != nil {
+		return def.Light
+	}
+	return 0
+}
+
+// FlowInterval returns how many ticks a liquid waits between flow updates
+func (b BlockType) FlowInterval() int {
+	if def := blocks.Def(b); def != nil && def.Flow > 0 {
+		return def.Flow
+	}
+	return 1
+}
+
+// Drag returns the fraction of velocity an entity loses each tick while submerged in the block
+func (b BlockType) Drag() float64 {
+	if def := blocks.Def(b); def != nil {
+		return def.Drag
+	}
+	return 0
+}
+
+// Reaction returns the block this liquid turns into when it touches other
+func (b BlockType) Reaction(other BlockType) (BlockType, bool) {
+	def := blocks.Def(b)
+	if def == nil {
+		return Air, false
+	}
+	result, ok := def.reactions[other]
+	return result, ok
+}
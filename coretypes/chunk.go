@@ -1,18 +1,80 @@
 package coretypes
 
+import (
+	"math"
+
+	"github.com/KdntNinja/webcraft/settings"
+)
+
 type ChunkCoord struct {
 	X int
 	Y int
 }
 
+// ChunkCoordOfBlock returns the chunk holding a block
+func ChunkCoordOfBlock(blockX, blockY int) ChunkCoord {
+	return ChunkCoord{X: FloorDiv(blockX, settings.ChunkWidth), Y: FloorDiv(blockY, settings.ChunkHeight)}
+}
+
+// ChunkCoordOfPixel returns the chunk holding a world pixel position
+func ChunkCoordOfPixel(x, y float64) ChunkCoord {
+	return ChunkCoordOfBlock(int(math.Floor(x/settings.TileSize)), int(math.Floor(y/settings.TileSize)))
+}
+
+// LocateBlock returns the chunk holding a block and the block's position inside it
+func LocateBlock(blockX, blockY int) (ChunkCoord, int, int) {
+	coord := ChunkCoordOfBlock(blockX, blockY)
+	return coord, blockX - coord.X*settings.ChunkWidth, blockY - coord.Y*settings.ChunkHeight
+}
+
+// FloorDiv divides rounding towards negative infinity, so negative block and chunk
+// coordinates land in the right chunk or region
+func FloorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
 type ChunkGenerationJob struct {
 	Coord    ChunkCoord
 	Priority int // Higher values = higher priority
 }
 
+// MaxLiquidLevel is the fill level of a full liquid cell
+const MaxLiquidLevel = 8
+
 type Chunk struct {
 	// Define the chunk data structure here, e.g. 2D slice of BlockType
 	Blocks [][]BlockType
+	Levels [][]uint8 // Liquid fill levels, allocated on first partial cell; 0 means full
+}
+
+// LiquidLevel returns the fill level of the cell, 1 to MaxLiquidLevel. Only
+// meaningful for liquid blocks.
+func (c *Chunk) LiquidLevel(x, y int) int {
+	if c.Levels == nil || c.Levels[y][x] == 0 {
+		return MaxLiquidLevel
+	}
+	return int(c.Levels[y][x])
+}
+
+// SetLiquidLevel stores the fill level of a cell; full (or out of range) levels are stored as 0
+func (c *Chunk) SetLiquidLevel(x, y, level int) {
+	if level <= 0 || level >= MaxLiquidLevel {
+		if c.Levels != nil {
+			c.Levels[y][x] = 0
+		}
+		return
+	}
+	if c.Levels == nil {
+		c.Levels = make([][]uint8, len(c.Blocks))
+		for row := range c.Levels {
+			c.Levels[row] = make([]uint8, len(c.Blocks[row]))
+		}
+	}
+	c.Levels[y][x] = uint8(level)
 }
 
 type ChunkManager interface {
@@ -20,8 +82,27 @@ type ChunkManager interface {
 	UpdatePlayerPosition(playerX, playerY float64)
 	GetAllChunks() map[ChunkCoord]*Chunk
 	SetBlock(x, y int, blockType BlockType) bool
+	SetLiquid(x, y int, blockType BlockType, level int) bool
 	GetBlock(x, y int) BlockType
 	InitialLoadWithProgress(playerX, playerY float64)
 	GetLoadedChunkCount() int
+	SetChunkStore(store ChunkStore)
+	SetChunkListener(listener ChunkListener)
+	SaveDirtyChunks() error
 	Shutdown()
 }
+
+// ChunkListener is told when chunks are loaded and unloaded. Chunk managers may call
+// it from their worker goroutines.
+type ChunkListener interface {
+	ChunkLoaded(coord ChunkCoord, chunk *Chunk)
+	ChunkUnloaded(coord ChunkCoord)
+}
+
+// ChunkStore persists modified chunks so they survive being unloaded
+type ChunkStore interface {
+	// LoadChunk returns the saved chunk at coord, or false if it was never saved
+	LoadChunk(coord ChunkCoord) (*Chunk, bool, error)
+	// SaveChunk writes the chunk at coord, replacing any previous copy
+	SaveChunk(coord ChunkCoord, chunk *Chunk) error
+}
@@ -0,0 +1,33 @@
+package coretypes
+
+import (
+	"testing"
+
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+func TestLocateBlock(t *testing.T) {
+	w, h := settings.ChunkWidth, settings.ChunkHeight
+	tests := []struct {
+		x, y     int
+		chunk    ChunkCoord
+		inX, inY int
+	}{
+		{0, 0, ChunkCoord{0, 0}, 0, 0},
+		{w - 1, h - 1, ChunkCoord{0, 0}, w - 1, h - 1},
+		{w, h, ChunkCoord{1, 1}, 0, 0},
+		{-1, -1, ChunkCoord{-1, -1}, w - 1, h - 1},
+		{-w, -h, ChunkCoord{-1, -1}, 0, 0},
+		{-w - 1, 2*h + 3, ChunkCoord{-2, 2}, w - 1, 3},
+	}
+	for _, tt := range tests {
+		chunk, inX, inY := LocateBlock(tt.x, tt.y)
+		if chunk != tt.chunk || inX != tt.inX || inY != tt.inY {
+			t.Errorf("LocateBlock(%d, %d) = %v, %d, %d; want %v, %d, %d", tt.x, tt.y, chunk, inX, inY, tt.chunk, tt.inX, tt.inY)
+		}
+		px, py := float64(tt.x*settings.TileSize)+0.5, float64(tt.y*settings.TileSize)+0.5
+		if got := ChunkCoordOfPixel(px, py); got != tt.chunk {
+			t.Errorf("ChunkCoordOfPixel(%g, %g) = %v, want %v", px, py, got, tt.chunk)
+		}
+	}
+}
@@ -10,3 +10,34 @@ type Entity interface {
 
 // Entities is a slice of all entities in the world
 type Entities []Entity
+
+// KindPlayer is the entity kind of players, local or remote
+const KindPlayer = "player"
+
+// Living is an entity with a kind, a collider and health, such as a player or a mob
+type Living interface {
+	Entity
+	EntityKind() string                    // KindPlayer or a mob kind name
+	ColliderSize() (width, height float64) // Collider size in pixels; GetPosition is its top-left corner
+	GetHealth() int
+	TakeDamage(amount int)
+	// Hurt deals damage with a knockback impulse in pixels per tick, unless the
+	// entity is briefly invulnerable from an earlier hit. It reports whether it hit.
+	Hurt(amount int, knockbackX, knockbackY float64) bool
+}
+
+// Sprite tells the renderer how to draw an entity
+type Sprite struct {
+	Name   string   // Identifies the sprite sheet, e.g. a mob kind name
+	Color  [3]uint8 // Base colour of the sheet
+	Width  int      // Frame size in pixels
+	Height int
+	Frames int  // Animation frames in the sheet
+	Frame  int  // Frame to draw
+	FlipX  bool // Draw mirrored (facing left)
+}
+
+// Drawable entities choose their own sprite; other entities are drawn as players
+type Drawable interface {
+	Sprite() Sprite
+}
@@ -0,0 +1,46 @@
+package coretypes
+
+// InputFrame is everything a player asked for during one simulation tick.
+// It is produced by the engine (or a test) and consumed by the simulation, so
+// gameplay code never reads devices directly.
+type InputFrame struct {
+	MoveX      float64 // Horizontal movement intent, -1 (left) to 1 (right)
+	Jump       bool    // Jump held
+	Sneak      bool    // Sneak held
+	Sprint     bool    // Sprint held
+	Break      bool    // Break block held
+	Place      bool    // Place block held
+	Attack     bool    // Melee attack held
+	HotbarSlot int     // Hotbar slot selected this tick, or -1 for no change
+	AimX       float64 // Aim position in world pixel coordinates
+	AimY       float64
+	Inventory  InventoryOp // Inventory screen action this tick, if any
+}
+
+// InventoryAction is something done to an inventory slot on the inventory screen
+type InventoryAction uint8
+
+const (
+	NoInventoryAction  InventoryAction = iota
+	InventoryClick                     // Pick up, put down or swap the whole stack
+	InventorySplit                     // Pick up half a stack, or put down one item
+	InventoryQuickMove                 // Move a stack between the hotbar and the main inventory
+	InventorySort                      // Sort the main inventory
+	InventoryReturn                    // Put the stack being carried back into the inventory
+	InventoryCraft                     // Craft the recipe whose ID is in Slot
+)
+
+// InventoryOp is one inventory screen action on a slot. Sort and Return ignore the
+// slot; Craft uses it for the recipe ID.
+type InventoryOp struct {
+	Action InventoryAction
+	Slot   int
+}
+
+// NoHotbarChange is the HotbarSlot value for frames that don't change selection
+const NoHotbarChange = -1
+
+// EmptyInputFrame returns a frame with no buttons held and no hotbar change
+func EmptyInputFrame() InputFrame {
+	return InputFrame{HotbarSlot: NoHotbarChange}
+}
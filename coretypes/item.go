@@ -0,0 +1,222 @@
+package coretypes
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// ItemID identifies an item. Every block has an item with the same ID that places
+// it; items that aren't blocks (tools, materials) come after the blocks.
+type ItemID int
+
+// NoItem is the item of Air, and what an empty slot holds
+const NoItem ItemID = 0
+
+// Built-in items that game code refers to directly, bound by name like the built-in blocks
+var (
+	CopperPickaxe ItemID
+	IronPickaxe   ItemID
+	GoldPickaxe   ItemID
+)
+
+// builtinItems maps the names of the built-in items to their variables
+var builtinItems = map[string]*ItemID{
+	"Copper Pickaxe": &CopperPickaxe,
+	"Iron Pickaxe":   &IronPickaxe,
+	"Gold Pickaxe":   &GoldPickaxe,
+}
+
+//go:embed items.json
+var defaultItemsJSON []byte
+
+// items is the item registry in use, rebuilt whenever the block registry changes
+var items *ItemRegistry
+
+// ItemDef describes one item
+type ItemDef struct {
+	ID         ItemID    `json:"-"`
+	Name       string    `json:"name"`                 // Display name, also used in save files
+	MaxStack   int       `json:"maxStack,omitempty"`   // Most items in one slot; 0 uses settings.MaxStackSize
+	Durability int       `json:"durability,omitempty"` // Uses before the item breaks; 0 never wears out
+	Tool       ToolTier  `json:"tool,omitempty"`       // Pickaxe tier; 0 for items that aren't pickaxes
+	Icon       string    `json:"icon,omitempty"`       // Shape of the generated icon, for items that aren't blocks
+	Color      [3]uint8  `json:"color"`                // Colour of the generated icon
+	Block      BlockType `json:"-"`                    // Block the item places; Air for items that aren't blocks
+}
+
+// ItemRegistry holds every item, indexed by ItemID
+type ItemRegistry struct {
+	defs   []ItemDef
+	byName map[string]ItemID
+}
+
+// buildItemRegistry makes an item for every block, followed by the items in the
+// item definitions
+func buildItemRegistry(blockRegistry *BlockRegistry, data []byte) (*ItemRegistry, error) {
+	var parsed struct {
+		Items []ItemDef `json:"items"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+
+	r := &ItemRegistry{byName: make(map[string]ItemID)}
+	for _, block := range blockRegistry.Defs() {
+		r.defs = append(r.defs, ItemDef{Name: block.Name, Block: block.ID})
+	}
+	r.defs = append(r.defs, parsed.Items...)
+
+	for i := range r.defs {
+		def := &r.defs[i]
+		def.ID = ItemID(i)
+		if def.Name == "" {
+			return nil, fmt.Errorf("item %d has no name", i)
+		}
+		if _, dup := r.byName[def.Name]; dup {
+			return nil, fmt.Errorf("item %q is defined twice (items can't share a block's name)", def.Name)
+		}
+		if def.Tool < TierHand || def.Tool > MaxToolTier {
+			return nil, fmt.Errorf("item %q: tool tier must be between %d and %d", def.Name, TierHand, MaxToolTier)
+		}
+		if def.MaxStack <= 0 {
+			def.MaxStack = settings.MaxStackSize
+		}
+		if def.Durability > 0 {
+			def.MaxStack = 1 // Worn tools don't stack
+		}
+		r.byName[def.Name] = def.ID
+	}
+
+	for name := range builtinItems {
+		if _, ok := r.byName[name]; !ok {
+			return nil, fmt.Errorf("built-in item %q is not defined", name)
+		}
+	}
+	return r, nil
+}
+
+// setItemRegistry installs an item registry and rebinds the built-in item variables
+func setItemRegistry(r *ItemRegistry) {
+	items = r
+	for name, item := range builtinItems {
+		*item = r.byName[name]
+	}
+}
+
+// Items returns the item registry in use
+func Items() *ItemRegistry {
+	return items
+}
+
+// Len returns the number of registered items
+func (r *ItemRegistry) Len() int {
+	return len(r.defs)
+}
+
+// Def returns an item's definition, or nil if it is not registered
+func (r *ItemRegistry) Def(item ItemID) *ItemDef {
+	if item < 0 || int(item) >= len(r.defs) {
+		return nil
+	}
+	return &r.defs[item]
+}
+
+// ByName returns the item with the given name
+func (r *ItemRegistry) ByName(name string) (ItemID, bool) {
+	item, ok := r.byName[name]
+	return item, ok
+}
+
+// Defs returns every item definition in ID order
+func (r *ItemRegistry) Defs() []ItemDef {
+	return r.defs
+}
+
+// ItemByName returns the item with the given registry name
+func ItemByName(name string) (ItemID, bool) {
+	return items.ByName(name)
+}
+
+// Item returns the item that places the block
+func (b BlockType) Item() ItemID {
+	return ItemID(b)
+}
+
+// String returns the item's registry name
+func (i ItemID) String() string {
+	if def := items.Def(i); def != nil {
+		return def.Name
+	}
+	return fmt.Sprintf("Item(%d)", int(i))
+}
+
+// Def returns the item's definition from the registry in use
+func (i ItemID) Def() *ItemDef {
+	return items.Def(i)
+}
+
+// MaxStack returns the most of the item one slot holds
+func (i ItemID) MaxStack() int {
+	if def := items.Def(i); def != nil {
+		return def.MaxStack
+	}
+	return settings.MaxStackSize
+}
+
+// Block returns the block the item places, or Air if it isn't a block
+func (i ItemID) Block() BlockType {
+	if def := items.Def(i); def != nil {
+		return def.Block
+	}
+	return Air
+}
+
+// Durability returns how many uses the item lasts, or 0 if it never wears out
+func (i ItemID) Durability() int {
+	if def := items.Def(i); def != nil {
+		return def.Durability
+	}
+	return 0
+}
+
+// ToolTier returns the item's pickaxe tier, TierHand for items that aren't pickaxes
+func (i ItemID) ToolTier() ToolTier {
+	if def := items.Def(i); def != nil {
+		return def.Tool
+	}
+	return TierHand
+}
+
+// ItemStack is some number of one item in an inventory slot. The zero value is an
+// empty slot.
+type ItemStack struct {
+	Item   ItemID
+	Count  int
+	Damage int               // Uses worn off a tool
+	Meta   map[string]string // Optional extra data; stacks only merge when it matches
+}
+
+// NewStack returns a stack of count items
+func NewStack(item ItemID, count int) ItemStack {
+	return ItemStack{Item: item, Count: count}
+}
+
+// IsEmpty reports whether the stack holds nothing
+func (s ItemStack) IsEmpty() bool {
+	return s.Item == NoItem || s.Count <= 0
+}
+
+// StacksWith reports whether two stacks can share a slot: the same item, wear and metadata
+func (s ItemStack) StacksWith(other ItemStack) bool {
+	return s.Item == other.Item && s.Damage == other.Damage && maps.Equal(s.Meta, other.Meta)
+}
+
+// Clone returns a copy of the stack that shares no metadata with it
+func (s ItemStack) Clone() ItemStack {
+	s.Meta = maps.Clone(s.Meta)
+	return s
+}
@@ -0,0 +1,203 @@
+package coretypes
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"os"
+)
+
+// MaxLight is the brightest light level a block can emit
+const MaxLight = 15
+
+//go:embed blocks.json
+var defaultBlocksJSON []byte
+
+// blocks is the registry in use. It is installed at startup, before any world exists,
+// and never changes while the game runs.
+var blocks *BlockRegistry
+
+func init() {
+	registry, err := ParseBlockRegistry(defaultBlocksJSON)
+	if err != nil {
+		panic(fmt.Sprintf("built-in block registry: %v", err))
+	}
+	SetBlockRegistry(registry)
+}
+
+// BlockDef describes one block. The block's ID is its position in the definition file.
+type BlockDef struct {
+	ID        BlockType  `json:"-"`
+	Name      string     `json:"name"`                // Display name, also used in save files
+	Texture   string     `json:"texture,omitempty"`   // Texture file under rendering/assets
+	Atlas     [2]float64 `json:"atlas"`               // Tile within the texture file (fractions allowed)
+	Tint      []float64  `json:"tint,omitempty"`      // RGB multiplier applied to the texture
+	Solid     bool       `json:"solid"`               // Entities collide with the block
+	Hardness  float64    `json:"hardness"`            // Time to break; negative means unbreakable
+	Drop      string     `json:"drop,omitempty"`      // Block given when broken without a loot table; empty drops itself, "Air" nothing
+	Light     int        `json:"light,omitempty"`     // Light emitted, 0 to MaxLight
+	Liquid    bool       `json:"liquid,omitempty"`    // The block is a liquid
+	Placeable bool       `json:"placeable,omitempty"` // Players can place the block
+	Burns     bool       `json:"burns,omitempty"`     // Entities touching the block catch fire
+	Tier      ToolTier   `json:"tier,omitempty"`      // Pickaxe tier needed for the block to drop anything
+
+	// Liquid behaviour
+	Flow      int               `json:"flow,omitempty"`      // Ticks between flow updates; higher is more viscous
+	Drag      float64           `json:"drag,omitempty"`      // Fraction of velocity entities lose per tick while submerged
+	Reactions map[string]string `json:"reactions,omitempty"` // Liquid touched -> block this liquid turns into
+
+	drop      BlockType
+	reactions map[BlockType]BlockType
+}
+
+// BlockRegistry holds every block the game knows about, indexed by BlockType
+type BlockRegistry struct {
+	defs     []BlockDef
+	byName   map[string]BlockType
+	checksum uint32
+}
+
+// ParseBlockRegistry reads block definitions from JSON. The first block must be Air
+// and every built-in block must be defined.
+func ParseBlockRegistry(data []byte) (*BlockRegistry, error) {
+	var parsed struct {
+		Blocks []BlockDef `json:"blocks"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+	if len(parsed.Blocks) == 0 || parsed.Blocks[0].Name != "Air" {
+		return nil, fmt.Errorf("the first block must be Air")
+	}
+	if parsed.Blocks[0].Solid || parsed.Blocks[0].Placeable {
+		return nil, fmt.Errorf("Air must be neither solid nor placeable")
+	}
+
+	r := &BlockRegistry{
+		defs:   parsed.Blocks,
+		byName: make(map[string]BlockType, len(parsed.Blocks)),
+	}
+	hash := crc32.NewIEEE()
+	for i := range r.defs {
+		def := &r.defs[i]
+		def.ID = BlockType(i)
+		if def.Name == "" {
+			return nil, fmt.Errorf("block %d has no name", i)
+		}
+		if _, dup := r.byName[def.Name]; dup {
+			return nil, fmt.Errorf("block %q is defined twice", def.Name)
+		}
+		if len(def.Tint) != 0 && len(def.Tint) != 3 {
+			return nil, fmt.Errorf("block %q: tint needs 3 components", def.Name)
+		}
+		if def.Light < 0 || def.Light > MaxLight {
+			return nil, fmt.Errorf("block %q: light must be between 0 and %d", def.Name, MaxLight)
+		}
+		if def.Tier < TierHand || def.Tier > MaxToolTier {
+			return nil, fmt.Errorf("block %q: tier must be between %d and %d", def.Name, TierHand, MaxToolTier)
+		}
+		r.byName[def.Name] = def.ID
+		fmt.Fprintf(hash, "%s\x00", def.Name)
+	}
+	r.checksum = hash.Sum32()
+
+	for i := range r.defs {
+		def := &r.defs[i]
+		def.drop = def.ID
+		if def.Drop != "" {
+			drop, ok := r.byName[def.Drop]
+			if !ok {
+				return nil, fmt.Errorf("block %q drops unknown block %q", def.Name, def.Drop)
+			}
+			def.drop = drop
+		}
+		if len(def.Reactions) > 0 && !def.Liquid {
+			return nil, fmt.Errorf("block %q has reactions but is not a liquid", def.Name)
+		}
+		def.reactions = make(map[BlockType]BlockType, len(def.Reactions))
+		for other, result := range def.Reactions {
+			otherID, ok := r.byName[other]
+			if !ok {
+				return nil, fmt.Errorf("block %q reacts with unknown block %q", def.Name, other)
+			}
+			resultID, ok := r.byName[result]
+			if !ok {
+				return nil, fmt.Errorf("block %q reacts into unknown block %q", def.Name, result)
+			}
+			def.reactions[otherID] = resultID
+		}
+	}
+
+	for name := range builtinBlocks {
+		if _, ok := r.byName[name]; !ok {
+			return nil, fmt.Errorf("built-in block %q is not defined", name)
+		}
+	}
+
+	// Blocks share names with their items, so they must not clash with the other items
+	if _, err := buildItemRegistry(r, defaultItemsJSON); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LoadBlockRegistryFile reads block definitions from a JSON file on disk
+func LoadBlockRegistryFile(path string) (*BlockRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBlockRegistry(data)
+}
+
+// SetBlockRegistry installs a registry, rebinds the built-in block variables and
+// rebuilds the item registry, which has an item for every block. It must be called
+// before any world is created.
+func SetBlockRegistry(r *BlockRegistry) {
+	blocks = r
+	for name, block := range builtinBlocks {
+		*block = r.byName[name]
+	}
+
+	itemRegistry, err := buildItemRegistry(r, defaultItemsJSON)
+	if err != nil {
+		panic(fmt.Sprintf("built-in item registry: %v", err))
+	}
+	setItemRegistry(itemRegistry)
+}
+
+// Blocks returns the registry in use
+func Blocks() *BlockRegistry {
+	return blocks
+}
+
+// Len returns the number of registered blocks
+func (r *BlockRegistry) Len() int {
+	return len(r.defs)
+}
+
+// Def returns a block's definition, or nil if it is not registered
+func (r *BlockRegistry) Def(b BlockType) *BlockDef {
+	if b < 0 || int(b) >= len(r.defs) {
+		return nil
+	}
+	return &r.defs[b]
+}
+
+// ByName returns the block with the given name
+func (r *BlockRegistry) ByName(name string) (BlockType, bool) {
+	b, ok := r.byName[name]
+	return b, ok
+}
+
+// Defs returns every block definition in ID order
+func (r *BlockRegistry) Defs() []BlockDef {
+	return r.defs
+}
+
+// Checksum identifies the registry's block IDs; peers must agree on it to exchange
+// block data
+func (r *BlockRegistry) Checksum() uint32 {
+	return r.checksum
+}
@@ -0,0 +1,44 @@
+package coretypes
+
+import "github.com/KdntNinja/webcraft/settings"
+
+// ToolTier is the grade of pickaxe a block is mined with. Better pickaxes mine
+// faster, and some blocks only drop anything when mined with a high enough tier.
+type ToolTier int
+
+const (
+	TierHand   ToolTier = iota // No pickaxe
+	TierCopper                 // Copper pickaxe
+	TierIron                   // Iron pickaxe
+	TierGold                   // Gold pickaxe
+
+	MaxToolTier = TierGold
+)
+
+// String returns the tier's display name
+func (t ToolTier) String() string {
+	switch t {
+	case TierCopper:
+		return "Copper"
+	case TierIron:
+		return "Iron"
+	case TierGold:
+		return "Gold"
+	default:
+		return "Hand"
+	}
+}
+
+// MiningSpeed returns how many times faster than bare hands the tier mines
+func (t ToolTier) MiningSpeed() float64 {
+	switch t {
+	case TierCopper:
+		return settings.MiningSpeedCopper
+	case TierIron:
+		return settings.MiningSpeedIron
+	case TierGold:
+		return settings.MiningSpeedGold
+	default:
+		return 1
+	}
+}
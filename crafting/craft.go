@@ -0,0 +1,53 @@
+package crafting
+
+import (
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/inventory"
+)
+
+// StationCheck reports whether a crafting station block is close enough to use
+type StationCheck func(station coretypes.BlockType) bool
+
+// CanCraft reports whether the container holds the recipe's ingredients and the
+// station, if the recipe needs one, is nearby. It doesn't check the result fits.
+func CanCraft(c *inventory.Container, r *Recipe, near StationCheck) bool {
+	if r.Station != coretypes.Air && (near == nil || !near(r.Station)) {
+		return false
+	}
+	for _, need := range r.Needs {
+		if c.Count(need.Item) < need.Count {
+			return false
+		}
+	}
+	return true
+}
+
+// Available returns the recipes that can be crafted from the container right now,
+// in ID order
+func (r *Registry) Available(c *inventory.Container, near StationCheck) []*Recipe {
+	var available []*Recipe
+	for _, recipe := range r.recipes {
+		if recipe != nil && CanCraft(c, recipe, near) {
+			available = append(available, recipe)
+		}
+	}
+	return available
+}
+
+// Craft uses up a recipe's ingredients from the container and adds the result.
+// Nothing changes, and it returns false, if the ingredients or station are
+// missing or the result doesn't fit once the ingredients are gone.
+func Craft(c *inventory.Container, r *Recipe, near StationCheck) bool {
+	if !CanCraft(c, r, near) {
+		return false
+	}
+	after := c.Clone()
+	for _, need := range r.Needs {
+		after.Remove(need.Item, need.Count)
+	}
+	if !after.Add(coretypes.NewStack(r.Result, r.Count)).IsEmpty() {
+		return false
+	}
+	copy(c.Slots, after.Slots)
+	return true
+}
@@ -0,0 +1,139 @@
+package crafting
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/inventory"
+)
+
+const testRecipes = `{"recipes": [
+	{"result": "Workbench", "pattern": ["WW", "WW"], "key": {"W": "Wood"}},
+	{"result": "Stick", "count": 4, "pattern": ["W", "W"], "key": {"W": "Wood"}},
+	{"result": "Torch", "count": 4, "ingredients": ["Stick", "Wood"]},
+	{"result": "Iron Pickaxe", "station": "Workbench", "pattern": ["BBB", " S ", " S "], "key": {"B": "Iron Bar", "S": "Stick"}},
+	{"result": "Nonsense", "ingredients": ["Wood"]}
+]}`
+
+// item looks an item up by name, failing the test if it doesn't exist
+func item(t *testing.T, name string) coretypes.ItemID {
+	t.Helper()
+	id, ok := coretypes.ItemByName(name)
+	if !ok {
+		t.Fatalf("no item %q", name)
+	}
+	return id
+}
+
+func testRegistry(t *testing.T) *Registry {
+	t.Helper()
+	r, err := Parse([]byte(testRecipes))
+	if err == nil {
+		t.Fatal("recipe with an unknown result loaded without an error")
+	}
+	if r.Len() != 5 || r.Get(4) != nil || r.Get(3) == nil {
+		t.Fatalf("bad recipe should be skipped in place: %d recipes, last %v", r.Len(), r.Get(4))
+	}
+	return r
+}
+
+// slots names the stacks a test container starts with, one per slot
+type slots []struct {
+	item  string
+	count int
+}
+
+func (s slots) container(t *testing.T, size int) *inventory.Container {
+	t.Helper()
+	c := inventory.NewContainer(size)
+	for i, stack := range s {
+		c.Set(i, coretypes.NewStack(item(t, stack.item), stack.count))
+	}
+	return c
+}
+
+func TestCanCraft(t *testing.T) {
+	r := testRegistry(t)
+	workbench, _ := coretypes.BlockTypeByName("Workbench")
+	nearWorkbench := func(station coretypes.BlockType) bool { return station == workbench }
+	nearOthers := func(station coretypes.BlockType) bool { return station != workbench }
+	bench, torch, pickaxe := 0, 2, 3
+
+	tests := []struct {
+		name   string
+		have   slots
+		recipe int
+		near   StationCheck
+		want   bool
+	}{
+		{"empty", nil, bench, nil, false},
+		{"exact count", slots{{"Wood", 4}}, bench, nil, true},
+		{"one short", slots{{"Wood", 3}}, bench, nil, false},
+		{"more than enough", slots{{"Wood", 40}}, bench, nil, true},
+		{"split across slots", slots{{"Wood", 1}, {"Stick", 1}, {"Wood", 3}}, bench, nil, true},
+		{"shapeless exact", slots{{"Stick", 1}, {"Wood", 1}}, torch, nil, true},
+		{"shapeless short", slots{{"Stick", 1}}, torch, nil, false},
+		{"station missing", slots{{"Iron Bar", 3}, {"Stick", 2}}, pickaxe, nil, false},
+		{"other stations near", slots{{"Iron Bar", 3}, {"Stick", 2}}, pickaxe, nearOthers, false},
+		{"station near, exact count", slots{{"Iron Bar", 3}, {"Stick", 2}}, pickaxe, nearWorkbench, true},
+		{"station near, one short", slots{{"Iron Bar", 3}, {"Stick", 1}}, pickaxe, nearWorkbench, false},
+		{"station near, nothing held", nil, pickaxe, nearWorkbench, false},
+	}
+	for _, tt := range tests {
+		c := tt.have.container(t, 4)
+		recipe := r.Get(tt.recipe)
+		if got := CanCraft(c, recipe, tt.near); got != tt.want {
+			t.Errorf("%s: CanCraft = %v, want %v", tt.name, got, tt.want)
+		}
+		if got := slices.Contains(r.Available(c, tt.near), recipe); got != tt.want {
+			t.Errorf("%s: listed as available = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCraft(t *testing.T) {
+	r := testRegistry(t)
+	workbench, _ := coretypes.BlockTypeByName("Workbench")
+	nearWorkbench := func(station coretypes.BlockType) bool { return station == workbench }
+	bench, sticks, pickaxe := 0, 1, 3
+	fullSticks := item(t, "Stick").MaxStack()
+
+	tests := []struct {
+		name   string
+		size   int
+		have   slots
+		recipe int
+		near   StationCheck
+		want   bool
+		after  map[string]int // Count of each named item afterwards
+	}{
+		{"exact count", 2, slots{{"Wood", 4}}, bench, nil, true, map[string]int{"Wood": 0, "Workbench": 1}},
+		{"leftovers stay", 2, slots{{"Wood", 5}}, bench, nil, true, map[string]int{"Wood": 1, "Workbench": 1}},
+		{"one short", 2, slots{{"Wood", 3}}, bench, nil, false, map[string]int{"Wood": 3, "Workbench": 0}},
+		{"station missing", 3, slots{{"Iron Bar", 3}, {"Stick", 3}}, pickaxe, nil, false,
+			map[string]int{"Iron Bar": 3, "Stick": 3, "Iron Pickaxe": 0}},
+		{"station near", 3, slots{{"Iron Bar", 3}, {"Stick", 3}}, pickaxe, nearWorkbench, true,
+			map[string]int{"Iron Bar": 0, "Stick": 1, "Iron Pickaxe": 1}},
+		// The sticks need a slot of their own, and there is none
+		{"full inventory", 2, slots{{"Stone", 1}, {"Wood", 3}}, sticks, nil, false, map[string]int{"Wood": 3, "Stick": 0}},
+		// With the wood used up its slot is free for the result
+		{"full inventory, ingredients free a slot", 2, slots{{"Stone", 1}, {"Wood", 2}}, sticks, nil, true,
+			map[string]int{"Wood": 0, "Stick": 4}},
+		{"result tops up a stack", 2, slots{{"Stick", fullSticks - 4}, {"Wood", 3}}, sticks, nil, true,
+			map[string]int{"Wood": 1, "Stick": fullSticks}},
+		{"result overflows a full stack", 2, slots{{"Stick", fullSticks - 2}, {"Wood", 3}}, sticks, nil, false,
+			map[string]int{"Wood": 3, "Stick": fullSticks - 2}},
+	}
+	for _, tt := range tests {
+		c := tt.have.container(t, tt.size)
+		if got := Craft(c, r.Get(tt.recipe), tt.near); got != tt.want {
+			t.Errorf("%s: Craft = %v, want %v", tt.name, got, tt.want)
+		}
+		for name, want := range tt.after {
+			if got := c.Count(item(t, name)); got != want {
+				t.Errorf("%s: %d %s afterwards, want %d", tt.name, got, name, want)
+			}
+		}
+	}
+}
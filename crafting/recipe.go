@@ -0,0 +1,216 @@
+package crafting
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+//go:embed recipes.json
+var defaultRecipesJSON []byte
+
+// recipes is the recipe registry in use, and the item registry it was built against
+var (
+	recipes      *Registry
+	recipesItems *coretypes.ItemRegistry
+)
+
+// Recipe makes Count of an item from ingredients, optionally only next to a
+// crafting station. Shaped recipes keep the layout from the recipe file, but
+// crafting only checks the totals in Needs; there is no crafting grid.
+type Recipe struct {
+	ID          int                   // Position in the recipe file
+	Result      coretypes.ItemID      // Item made
+	Count       int                   // How many are made
+	Station     coretypes.BlockType   // Block the player must be near; Air for recipes made by hand
+	Pattern     [][]coretypes.ItemID  // Shaped recipes: rows of ingredients, NoItem for empty cells
+	Ingredients []coretypes.ItemID    // Shapeless recipes: one entry per item used
+	Needs       []coretypes.ItemStack // Total of each ingredient used, in item order
+}
+
+// Shaped reports whether the recipe has a pattern
+func (r *Recipe) Shaped() bool {
+	return len(r.Pattern) > 0
+}
+
+// String describes the recipe, e.g. "4 Stick from 2 Wood"
+func (r *Recipe) String() string {
+	parts := make([]string, len(r.Needs))
+	for i, need := range r.Needs {
+		parts[i] = fmt.Sprintf("%d %s", need.Count, need.Item)
+	}
+	return fmt.Sprintf("%d %s from %s", r.Count, r.Result, strings.Join(parts, ", "))
+}
+
+// recipeJSON is a recipe as written in the recipe file, with items named
+type recipeJSON struct {
+	Result      string            `json:"result"`
+	Count       int               `json:"count,omitempty"`       // Defaults to 1
+	Station     string            `json:"station,omitempty"`     // Block name; empty for recipes made by hand
+	Pattern     []string          `json:"pattern,omitempty"`     // Rows of key characters; space is an empty cell
+	Key         map[string]string `json:"key,omitempty"`         // Pattern character -> item name
+	Ingredients []string          `json:"ingredients,omitempty"` // Shapeless recipes
+}
+
+// Registry holds every recipe, indexed by ID
+type Registry struct {
+	recipes []*Recipe // Nil for recipes that failed to load, so IDs keep their place
+}
+
+// Parse reads recipes from JSON. Recipes naming items or stations that don't exist
+// are left out and reported in the error; the rest are still usable.
+func Parse(data []byte) (*Registry, error) {
+	var parsed struct {
+		Recipes []recipeJSON `json:"recipes"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+
+	r := &Registry{recipes: make([]*Recipe, len(parsed.Recipes))}
+	var errs []error
+	for i, raw := range parsed.Recipes {
+		recipe, err := parseRecipe(i, raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("recipe %d (%s): %w", i, raw.Result, err))
+			continue
+		}
+		r.recipes[i] = recipe
+	}
+	return r, errors.Join(errs...)
+}
+
+// parseRecipe resolves a recipe's item and station names
+func parseRecipe(id int, raw recipeJSON) (*Recipe, error) {
+	result, ok := coretypes.ItemByName(raw.Result)
+	if !ok {
+		return nil, fmt.Errorf("unknown item %q", raw.Result)
+	}
+	recipe := &Recipe{ID: id, Result: result, Count: max(raw.Count, 1)}
+	if raw.Station != "" {
+		station, ok := coretypes.BlockTypeByName(raw.Station)
+		if !ok {
+			return nil, fmt.Errorf("unknown station %q", raw.Station)
+		}
+		recipe.Station = station
+	}
+	if (len(raw.Pattern) > 0) == (len(raw.Ingredients) > 0) {
+		return nil, errors.New("needs either a pattern or ingredients")
+	}
+
+	totals := make(map[coretypes.ItemID]int)
+	for _, name := range raw.Ingredients {
+		item, ok := coretypes.ItemByName(name)
+		if !ok || item == coretypes.NoItem {
+			return nil, fmt.Errorf("unknown ingredient %q", name)
+		}
+		recipe.Ingredients = append(recipe.Ingredients, item)
+		totals[item]++
+	}
+	slices.Sort(recipe.Ingredients)
+
+	for _, line := range raw.Pattern {
+		row := make([]coretypes.ItemID, 0, len(line))
+		for _, key := range line {
+			if key == ' ' {
+				row = append(row, coretypes.NoItem)
+				continue
+			}
+			item, ok := coretypes.ItemByName(raw.Key[string(key)])
+			if !ok || item == coretypes.NoItem {
+				return nil, fmt.Errorf("pattern character %q has no item", key)
+			}
+			row = append(row, item)
+			totals[item]++
+		}
+		recipe.Pattern = append(recipe.Pattern, row)
+	}
+	if recipe.Shaped() {
+		recipe.Pattern = trim(recipe.Pattern)
+		if len(recipe.Pattern) == 0 {
+			return nil, errors.New("pattern is empty")
+		}
+	}
+
+	for item, count := range totals {
+		recipe.Needs = append(recipe.Needs, coretypes.NewStack(item, count))
+	}
+	slices.SortFunc(recipe.Needs, func(a, b coretypes.ItemStack) int { return int(a.Item) - int(b.Item) })
+	return recipe, nil
+}
+
+// Recipes returns the built-in recipe registry, rebuilding it if the item registry
+// has changed since
+func Recipes() *Registry {
+	if recipes == nil || recipesItems != coretypes.Items() {
+		registry, err := Parse(defaultRecipesJSON)
+		if err != nil {
+			fmt.Printf("CRAFTING: Skipping recipes: %v\n", err)
+		}
+		if registry == nil {
+			registry = &Registry{}
+		}
+		recipes, recipesItems = registry, coretypes.Items()
+	}
+	return recipes
+}
+
+// Len returns the number of recipe IDs, including recipes that failed to load
+func (r *Registry) Len() int {
+	return len(r.recipes)
+}
+
+// Get returns the recipe with an ID, or nil if there is none
+func (r *Registry) Get(id int) *Recipe {
+	if id < 0 || id >= len(r.recipes) {
+		return nil
+	}
+	return r.recipes[id]
+}
+
+// All returns every recipe that loaded, in ID order
+func (r *Registry) All() []*Recipe {
+	all := make([]*Recipe, 0, len(r.recipes))
+	for _, recipe := range r.recipes {
+		if recipe != nil {
+			all = append(all, recipe)
+		}
+	}
+	return all
+}
+
+// trim cuts a grid down to the smallest rectangle holding all its items. Rows
+// shorter than the widest row count as empty past their end.
+func trim(grid [][]coretypes.ItemID) [][]coretypes.ItemID {
+	top, bottom, left, right := len(grid), -1, -1, -1
+	for y, row := range grid {
+		for x, item := range row {
+			if item == coretypes.NoItem {
+				continue
+			}
+			top, bottom = min(top, y), max(bottom, y)
+			if left < 0 || x < left {
+				left = x
+			}
+			right = max(right, x)
+		}
+	}
+	if bottom < 0 {
+		return nil
+	}
+
+	trimmed := make([][]coretypes.ItemID, 0, bottom-top+1)
+	for _, row := range grid[top : bottom+1] {
+		cut := make([]coretypes.ItemID, right-left+1)
+		if left < len(row) {
+			copy(cut, row[left:min(right+1, len(row))])
+		}
+		trimmed = append(trimmed, cut)
+	}
+	return trimmed
+}
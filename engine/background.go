@@ -3,13 +3,22 @@ package engine
 import (
 	"image/color"
 
+	"github.com/KdntNinja/webcraft/clock"
 	"github.com/KdntNinja/webcraft/settings"
 )
 
-// GetBackgroundColor returns the background color based on the player's Y position.
-func GetBackgroundColor(playerY float64) color.RGBA {
-	skyColor := color.RGBA{135, 206, 235, 255}      // Terraria-like sky blue
-	undergroundColor := color.RGBA{10, 10, 30, 255} // Deep blue/black
+var (
+	daySky           = color.RGBA{135, 206, 235, 255} // Terraria-like sky blue
+	twilightSky      = color.RGBA{250, 140, 80, 255}  // Orange glow at sunrise and sunset
+	nightSky         = color.RGBA{12, 16, 40, 255}    // Dark navy
+	undergroundColor = color.RGBA{10, 10, 30, 255}    // Deep blue/black
+)
+
+// GetBackgroundColor returns the background color for the time of day, fading to the
+// underground color with the player's depth.
+func GetBackgroundColor(playerY float64, worldClock *clock.Clock) color.RGBA {
+	skyColor := lerpColor(nightSky, daySky, worldClock.SunHeight())
+	skyColor = lerpColor(skyColor, twilightSky, worldClock.Twilight()*0.7)
 
 	if playerY <= settings.SkyTransitionStartY {
 		return skyColor
@@ -18,8 +27,15 @@ func GetBackgroundColor(playerY float64) color.RGBA {
 		return undergroundColor
 	}
 	t := (playerY - settings.SkyTransitionStartY) / (settings.SkyTransitionEndY - settings.SkyTransitionStartY)
-	bgR := uint8(float64(skyColor.R)*(1-t) + float64(undergroundColor.R)*t)
-	bgG := uint8(float64(skyColor.G)*(1-t) + float64(undergroundColor.G)*t)
-	bgB := uint8(float64(skyColor.B)*(1-t) + float64(undergroundColor.B)*t)
-	return color.RGBA{bgR, bgG, bgB, 255}
+	return lerpColor(skyColor, undergroundColor, t)
+}
+
+// lerpColor blends from a to b by t (0 to 1)
+func lerpColor(a, b color.RGBA, t float64) color.RGBA {
+	return color.RGBA{
+		R: uint8(float64(a.R)*(1-t) + float64(b.R)*t),
+		G: uint8(float64(a.G)*(1-t) + float64(b.G)*t),
+		B: uint8(float64(a.B)*(1-t) + float64(b.B)*t),
+		A: 255,
+	}
 }
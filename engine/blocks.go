@@ -0,0 +1,24 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// LoadBlockDefinitions installs the block definitions file if there is one, keeping
+// the built-in registry when it is missing or invalid. Call it before creating a game.
+func LoadBlockDefinitions() {
+	registry, err := coretypes.LoadBlockRegistryFile(settings.BlocksFile)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			fmt.Printf("GAME: Ignoring %s: %v\n", settings.BlocksFile, err)
+		}
+		return
+	}
+	coretypes.SetBlockRegistry(registry)
+	fmt.Printf("GAME: Loaded %d block definitions from %s\n", registry.Len(), settings.BlocksFile)
+}
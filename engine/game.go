@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"image/color"
 	"runtime"
-	"sync"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -13,11 +12,17 @@ import (
 	"github.com/KdntNinja/webcraft/gameplay"
 	"github.com/KdntNinja/webcraft/gameplay/world"
 	"github.com/KdntNinja/webcraft/generation"
-	"github.com/KdntNinja/webcraft/physics"
+	"github.com/KdntNinja/webcraft/input"
+	"github.com/KdntNinja/webcraft/netclient"
 	"github.com/KdntNinja/webcraft/progress"
 	"github.com/KdntNinja/webcraft/rendering"
 	"github.com/KdntNinja/webcraft/rendering/debug"
+	"github.com/KdntNinja/webcraft/rendering/inventoryui"
+	"github.com/KdntNinja/webcraft/replay"
+	"github.com/KdntNinja/webcraft/rollback"
 	"github.com/KdntNinja/webcraft/settings"
+	"github.com/KdntNinja/webcraft/sim"
+	"github.com/KdntNinja/webcraft/storage"
 	"github.com/KdntNinja/webcraft/worldgen"
 )
 
@@ -29,6 +34,22 @@ type Game struct {
 	CameraY     float64 // Camera Y position
 	Seed        int64   // World seed for deterministic generation
 
+	// Saving
+	SavePath      string              // Save slot this game is written to ("" when not saving)
+	saveBackend   storage.Backend     // Backend holding the manifest and region files
+	spawn         worldgen.SpawnPoint // World spawn point recorded in the manifest
+	prevF5Pressed bool                // Track previous F5 key state for quicksave
+
+	// Replays
+	recorder       *replay.Recorder // Records new worlds so sessions can be replayed
+	replayPlayer   *replay.Player   // Set when playing back a replay instead of live input
+	replayDiverged bool             // Only report the first divergence
+	prevF9Pressed  bool             // Track previous F9 key state for writing the replay
+
+	// Multiplayer
+	network       *netclient.Client // Set when playing on a server
+	networkClosed bool              // Only report a lost connection once
+
 	// Pre-allocated images to reduce memory allocation
 	playerImage *ebiten.Image
 	frameCount  int // For frame rate limiting
@@ -38,14 +59,16 @@ type Game struct {
 	lastFPSUpdate time.Time // Last time FPS was calculated
 	currentFPS    float64   // Current FPS value to display
 
-	// --- Physics cache ---
-	physicsWorld   *physics.PhysicsWorld // Cached physics world for collisions
-	physicsGrid    [][]int               // Cached grid used to build physicsWorld
-	physicsOffsetX int
-	physicsOffsetY int
+	// Deterministic simulation driving the world each tick
+	Sim *sim.Simulation
+
+	// Input
+	Input            input.InputSource // Produces one input frame per tick
+	cursorX, cursorY int               // Screen position of the aim point, for the crosshair
 
-	// Async physics system
-	asyncPhysics *physics.AsyncPhysicsSystem
+	// Inventory screen
+	inventoryScreen *inventoryui.Screen // Nil if it failed to load
+	prevEPressed    bool                // Track previous E key state for toggle
 
 	// Debug
 	ShowDebug     bool // Show debug screen when F3 is pressed
@@ -65,10 +88,17 @@ type Game struct {
 	frameStartTime time.Time
 	updateTime     time.Duration
 	renderTime     time.Duration
-	parallelTasks  sync.WaitGroup
 }
 
+// NewGame creates a fresh world with a random seed in the default save slot. Whatever
+// was saved there, even a save that failed to load, is moved aside rather than deleted.
 func NewGame() *Game {
+	return newGame(globalSeed, DefaultSavePath(), nil, nil)
+}
+
+// newGame builds a game for the given seed, restoring player state from manifest when it is
+// non-nil. With a client, chunks come from the server instead of being generated locally.
+func newGame(seed int64, savePath string, manifest *storage.WorldManifest, client *netclient.Client) *Game {
 	// Use view distance from settings
 	viewDistance := settings.ChunkViewDistance
 	totalChunks := (viewDistance*2 + 1) * (viewDistance*2 + 1)
@@ -82,29 +112,31 @@ func NewGame() *Game {
 	}
 	progress.InitializeProgress(steps)
 
-	seed := globalSeed
-	progress.UpdateCurrentStepProgress(1, fmt.Sprintf("Using random world seed: %d", seed))
+	if manifest != nil {
+		progress.UpdateCurrentStepProgress(1, fmt.Sprintf("Loading saved world seed: %d", seed))
+	} else {
+		progress.UpdateCurrentStepProgress(1, fmt.Sprintf("Using random world seed: %d", seed))
+	}
 
 	g := &Game{
 		LastScreenW:    800, // Default screen width
 		LastScreenH:    600, // Default screen height
 		Seed:           seed,
+		SavePath:       savePath,
 		lastFPSUpdate:  time.Now(), // Initialize FPS tracking
 		currentFPS:     60.0,       // Default FPS value
 		frameStartTime: time.Now(),
 	}
 
-	// Initialize async systems
-	progress.UpdateCurrentStepProgress(2, "Initializing async physics system...")
-	g.asyncPhysics = physics.GetAsyncPhysicsSystem()
+	progress.UpdateCurrentStepProgress(2, "Preparing simulation...")
 
 	// Hide the cursor for better gameplay experience and use custom crosshair
 	ebiten.SetCursorMode(ebiten.CursorModeHidden)
 	progress.UpdateCurrentStepProgress(4, "Set up game configuration")
 
-	// Always reset world generation with the new seed
-	generation.ResetWorldGeneration(seed)
-	progress.UpdateCurrentStepProgress(5, "Reset generation systems")
+	// Multiplayer clients keep a generator too, for the surface heights and biomes of the world
+	generator := generation.NewGenerator(seed, generation.DefaultConfig())
+	progress.UpdateCurrentStepProgress(5, "Set up terrain generator")
 
 	// Pre-allocate player image to avoid recreating it every frame
 	g.playerImage = ebiten.NewImage(settings.PlayerSpriteWidth, settings.PlayerSpriteHeight)
@@ -117,11 +149,60 @@ func NewGame() *Game {
 	// Create a simple world with fixed size, passing the seed
 	// This will use the new progress system for world generation
 	// Find a spawn point and create a chunk manager
-	spawn := worldgen.FindSpawnPoint()
-	chunkManager := generation.NewChunkManager(settings.ChunkViewDistance)
+	var spawn worldgen.SpawnPoint
+	var chunkManager coretypes.ChunkManager
+	if client != nil {
+		spawn.X, spawn.Y = client.Welcome.SpawnX, client.Welcome.SpawnY
+		chunkManager = client.Chunks
+		g.network = client
+	} else {
+		spawn = worldgen.FindSpawnPoint(generator)
+		// Chunks are generated inline so the simulation stays deterministic
+		chunkManager = generation.NewSyncChunkManager(generator, settings.ChunkViewDistance)
+	}
+	g.spawn = spawn
+	if savePath != "" && manifest == nil {
+		// A new world must not pick up chunks left in the slot by an older one, nor
+		// overwrite it, so whatever is there is moved aside first
+		backup, err := storage.SetAsideWorld(savePath)
+		if err != nil {
+			fmt.Printf("GAME: Could not move the old world out of %s, this world won't be saved: %v\n", savePath, err)
+			savePath, g.SavePath = "", ""
+		} else if backup != "" {
+			fmt.Printf("GAME: Moved the old world in %s to %s\n", savePath, backup)
+		}
+	}
+	if savePath != "" {
+		g.saveBackend = storage.NewDefaultBackend(savePath)
+		if manifest == nil {
+			recorder, err := replay.NewFileRecorder(seed, replayBackend(), settings.ReplayFileName)
+			if err != nil {
+				fmt.Printf("GAME: Not recording this session: %v\n", err)
+			}
+			g.recorder = recorder
+		}
+		// Modified chunks live next to the manifest in the save slot
+		chunkManager.SetChunkStore(storage.NewRegionStore(g.saveBackend, seed))
+	}
 	// Center chunk manager on spawn location before world creation
 	chunkManager.UpdatePlayerPosition(spawn.X, spawn.Y)
-	g.World = world.NewWorld(seed, chunkManager, spawn)
+	g.World = world.NewWorld(generator, chunkManager, spawn)
+	g.Sim = sim.New(g.World)
+	if client != nil {
+		client.Predictor = rollback.NewPredictor(g.Sim.Player(), g.Sim.MovePlayer)
+		g.Sim.Fluids = nil      // The server flows liquids and sends the results
+		g.Sim.DropItems = false // The server hands out block drops
+		if client.Welcome.DayLength > 0 {
+			g.World.Clock.Ticks, g.World.Clock.DayLength = client.Welcome.Time, client.Welcome.DayLength
+		}
+	}
+	g.Input = newInputSource()
+
+	// Put saved players back where they left off
+	if manifest != nil {
+		g.restoreClock(manifest)
+		g.restorePlayers(manifest)
+	}
 
 	// Initialize camera position to follow the player's spawn location with tighter centering
 	if len(g.World.Entities) > 0 {
@@ -136,6 +217,11 @@ func NewGame() *Game {
 	if err := debug.InitDebugUI(); err != nil {
 		fmt.Printf("WARNING: Failed to initialize debug UI: %v\n", err)
 	}
+	if screen, err := inventoryui.New(); err != nil {
+		fmt.Printf("WARNING: Failed to initialize inventory screen: %v\n", err)
+	} else {
+		g.inventoryScreen = screen
+	}
 
 	runtime.GC() // Force garbage collection after initialization
 	fmt.Printf("GAME: Initialized with %d CPU cores available\n", runtime.NumCPU())
@@ -156,16 +242,49 @@ func (g *Game) Update() error {
 	}
 	g.prevF3Pressed = f3Pressed
 
+	// --- E inventory screen toggle (edge-triggered) ---
+	if g.inventoryScreen != nil {
+		ePressed := ebiten.IsKeyPressed(ebiten.KeyE)
+		if ePressed && !g.prevEPressed {
+			g.inventoryScreen.Toggle()
+			if g.inventoryScreen.IsOpen() {
+				ebiten.SetCursorMode(ebiten.CursorModeVisible)
+			} else {
+				ebiten.SetCursorMode(ebiten.CursorModeHidden)
+			}
+		}
+		g.prevEPressed = ePressed
+		g.inventoryScreen.Update(g.Sim.Player())
+	}
+
+	// --- F5 quicksave (edge-triggered) and periodic autosave ---
+	f5Pressed := ebiten.IsKeyPressed(ebiten.KeyF5)
+	autosave := g.frameCount > 0 && g.frameCount%settings.AutosaveInterval == 0
+	if g.saveBackend != nil && ((f5Pressed && !g.prevF5Pressed) || autosave) {
+		if err := g.Save(g.SavePath); err != nil {
+			fmt.Printf("GAME: Save failed: %v\n", err)
+		}
+	}
+	g.prevF5Pressed = f5Pressed
+
+	// --- F9 writes the replay of this session (edge-triggered) ---
+	f9Pressed := ebiten.IsKeyPressed(ebiten.KeyF9)
+	if f9Pressed && !g.prevF9Pressed && g.recorder != nil {
+		if err := g.SaveReplay(); err != nil {
+			fmt.Printf("GAME: Replay save failed: %v\n", err)
+		}
+	}
+	g.prevF9Pressed = f9Pressed
+
 	g.frameCount++
 	g.fpsCounter++
 
-	// Start parallel tasks
-	g.parallelTasks.Add(1)
-	go func() {
-		defer g.parallelTasks.Done()
-		// Update world (handles dynamic chunk loading)
-		g.World.Update()
-	}()
+	// Advance the simulation one tick with this frame's input
+	g.stepSimulation()
+	if g.network != nil {
+		g.syncNetwork()
+	}
+	g.World.Light.Sync()
 
 	// Update FPS calculation every second
 	now := time.Now()
@@ -175,39 +294,18 @@ func (g *Game) Update() error {
 		g.lastFPSUpdate = now
 	}
 
-	// Update entities using async physics system
-	g.parallelTasks.Add(1)
-	go func() {
-		defer g.parallelTasks.Done()
-		g.UpdateEntitiesNearCameraAsync()
-	}()
-
 	// Update camera to follow player more responsively for zoomed-in feel
-	if len(g.World.Entities) > 0 {
-		if player, ok := g.World.Entities[0].(*gameplay.Player); ok {
-			// Tighter camera following with offset for better view ahead
-			targetCameraX := player.X + float64(settings.PlayerColliderWidth)/2 - float64(g.LastScreenW)/2
-			targetCameraY := player.Y + float64(settings.PlayerColliderHeight)/2 - float64(g.LastScreenH)/2 - float64(settings.TileSize*2)
-
-			// More responsive camera movement for zoomed-in feel
-			lerpFactor := 0.12 // Increased from 0.05 for more responsive following
-			g.CameraX += (targetCameraX - g.CameraX) * lerpFactor
-			g.CameraY += (targetCameraY - g.CameraY) * lerpFactor
-		}
-	}
-
-	// Only regenerate collision grid and physics world when necessary
-	if g.frameCount%60 == 0 || g.World.IsGridDirty() || g.physicsWorld == nil {
-		g.parallelTasks.Add(1)
-		go func() {
-			defer g.parallelTasks.Done()
-			g.updatePhysicsWorldAsync()
-		}()
+	if player := g.Sim.Player(); player != nil {
+		// Tighter camera following with offset for better view ahead
+		targetCameraX := player.X + float64(settings.PlayerColliderWidth)/2 - float64(g.LastScreenW)/2
+		targetCameraY := player.Y + float64(settings.PlayerColliderHeight)/2 - float64(g.LastScreenH)/2 - float64(settings.TileSize*2)
+
+		// More responsive camera movement for zoomed-in feel
+		lerpFactor := 0.12 // Increased from 0.05 for more responsive following
+		g.CameraX += (targetCameraX - g.CameraX) * lerpFactor
+		g.CameraY += (targetCameraY - g.CameraY) * lerpFactor
 	}
 
-	// Wait for all parallel tasks to complete
-	g.parallelTasks.Wait()
-
 	// Track update performance
 	g.updateTime = time.Since(g.frameStartTime)
 
@@ -229,27 +327,41 @@ func (g *Game) Draw(screen *ebiten.Image) {
 			playerY = player.Y
 		}
 	}
-	bgColor := GetBackgroundColor(playerY)
+	bgColor := GetBackgroundColor(playerY, g.World.Clock)
 	screen.Fill(bgColor)
 
 	// World rendering using DrawWithCamera from renderer.go
 	chunks, ok := g.World.GetChunksForRendering().(map[coretypes.ChunkCoord]*coretypes.Chunk)
 	if ok {
-		rendering.Draw(chunks, screen, g.CameraX, g.CameraY, g.physicsOffsetX, g.physicsOffsetY)
+		gridOffsetX, gridOffsetY := g.Sim.GridOffset()
+		rendering.Draw(chunks, g.World.Light, g.World.Clock.Daylight(), screen, g.CameraX, g.CameraY, gridOffsetX, gridOffsetY)
+	}
+
+	// Cracks on the block being mined
+	if len(g.World.Entities) > 0 {
+		if player, ok := g.World.Entities[0].(*gameplay.Player); ok {
+			rendering.DrawBreakingOverlay(screen, player, g.CameraX, g.CameraY)
+		}
 	}
 
 	// Entity rendering
 	rendering.DrawEntities(g.World.Entities, screen, g.CameraX, g.CameraY, g.LastScreenW, g.LastScreenH, g.playerImage)
 
-	// Crosshair
-	rendering.DrawCrosshair(screen, g.World, g.CameraX, g.CameraY)
+	// Crosshair, unless the mouse is on the inventory screen
+	if g.inventoryScreen == nil || !g.inventoryScreen.IsOpen() {
+		rendering.DrawCrosshair(screen, g.World, g.CameraX, g.CameraY, g.cursorX, g.cursorY)
+	}
 
 	// Always draw normal UI (hotbar)
 	if len(g.World.Entities) > 0 {
 		if player, ok := g.World.Entities[0].(*gameplay.Player); ok {
 			rendering.DrawHotbarUI(screen, player)
+			rendering.DrawHealthUI(screen, player)
 		}
 	}
+	if g.inventoryScreen != nil {
+		g.inventoryScreen.Draw(screen)
+	}
 
 	// Draw debug overlay if enabled (on top of normal UI)
 	if g.ShowDebug {
@@ -262,7 +374,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		playerStats := "Stats: N/A"
 		camInfo := fmt.Sprintf("Camera: (%.1f, %.1f)", g.CameraX, g.CameraY)
 		seedInfo := fmt.Sprintf("Seed: %d", g.Seed)
-		worldInfo := "World: N/A"
+		worldInfo := "Time: " + g.World.Clock.String()
 		gcPercent := float64(memStats.GCCPUFraction) * 100
 		renderedBlocksHistory := []int{}
 		generatedBlocksHistory := []int{}
@@ -290,86 +402,6 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	g.updatePerformanceMetrics()
 }
 
-// UpdateEntitiesNearCameraAsync updates entities using async physics system
-func (g *Game) UpdateEntitiesNearCameraAsync() {
-	// Pre-calculate camera bounds once
-	camLeft := g.CameraX - float64(settings.TileSize*2)
-	camRight := g.CameraX + float64(g.LastScreenW) + float64(settings.TileSize*2)
-	camTop := g.CameraY - float64(settings.TileSize*2)
-	camBottom := g.CameraY + float64(g.LastScreenH) + float64(settings.TileSize*2)
-
-	// Filter entities within camera bounds
-	var nearbyEntities []coretypes.Entity
-	for _, e := range g.World.Entities {
-		if p, ok := e.(*gameplay.Player); ok {
-			// Frustum culling for entities
-			if p.X+float64(settings.PlayerColliderWidth) < camLeft || p.X > camRight ||
-				p.Y+float64(settings.PlayerColliderHeight) < camTop || p.Y > camBottom {
-				continue // Skip entities far from view
-			}
-			nearbyEntities = append(nearbyEntities, e)
-		}
-	}
-
-	// Process entities using async physics system
-	g.asyncPhysics.ProcessEntitiesAsync(nearbyEntities, g.physicsWorld, func(ent coretypes.Entity) {
-		if p, ok := ent.(*gameplay.Player); ok {
-			// Set the offset for the player's collision system
-			p.AABB.GridOffsetX = g.physicsOffsetX
-			p.AABB.GridOffsetY = g.physicsOffsetY
-
-			// Handle block interactions separately
-			blockInteraction := p.HandleBlockInteractions(g.CameraX, g.CameraY)
-			if blockInteraction != nil {
-				switch blockInteraction.Type {
-				case gameplay.BreakBlock:
-					// Get the block type before breaking
-					blockType := g.World.GetBlockAt(blockInteraction.BlockX, blockInteraction.BlockY)
-					if blockType != coretypes.Air {
-						// Add block to inventory
-						p.AddToInventory(blockType, 1)
-						g.World.BreakBlock(blockInteraction.BlockX, blockInteraction.BlockY)
-					}
-				case gameplay.PlaceBlock:
-					// Only place if player has block in inventory
-					if p.Inventory[p.SelectedBlock] > 0 {
-						placed := g.World.PlaceBlock(blockInteraction.BlockX, blockInteraction.BlockY, p.SelectedBlock)
-						if placed {
-							p.RemoveFromInventory(p.SelectedBlock, 1)
-						}
-					}
-				}
-			}
-		}
-	})
-}
-
-// updatePhysicsWorldAsync updates the physics world asynchronously
-func (g *Game) updatePhysicsWorldAsync() {
-	g.physicsGrid, g.physicsOffsetX, g.physicsOffsetY = g.World.ToIntGrid()
-	g.physicsWorld = physics.NewPhysicsWorld(g.physicsGrid)
-
-	// Update spatial grid for physics system
-	g.asyncPhysics.UpdateSpatialGrid(g.World.Entities)
-
-	// Sanity check: if grid is all air, log a warning (for debugging)
-	allAir := true
-	for _, row := range g.physicsGrid {
-		for _, v := range row {
-			if v != 0 {
-				allAir = false
-				break
-			}
-		}
-		if !allAir {
-			break
-		}
-	}
-	if allAir {
-		fmt.Println("[WARN] Physics grid is all air! Player will float.")
-	}
-}
-
 // updatePerformanceMetrics updates performance tracking metrics
 func (g *Game) updatePerformanceMetrics() {
 	// Update tick times for debug overlay
@@ -411,12 +443,9 @@ func (g *Game) updatePerformanceMetrics() {
 
 // Shutdown cleanly shuts down all async systems
 func (g *Game) Shutdown() {
-	fmt.Println("GAME: Shutting down async systems...")
+	fmt.Println("GAME: Shutting down...")
 	if g.World != nil {
 		g.World.Stop()
 	}
-	if g.asyncPhysics != nil {
-		g.asyncPhysics.Shutdown()
-	}
 	fmt.Println("GAME: Shutdown complete")
 }
@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/input"
+	"github.com/KdntNinja/webcraft/input/ebiteninput"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// newInputSource builds the keyboard/mouse/gamepad source from the bindings file,
+// falling back to the default controls if it is missing or invalid
+func newInputSource() input.InputSource {
+	bindings, err := input.LoadBindingsFile(settings.BindingsFile)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			fmt.Printf("GAME: Ignoring %s: %v\n", settings.BindingsFile, err)
+		}
+		bindings = input.DefaultBindings()
+	}
+
+	source, err := ebiteninput.New(bindings)
+	if err != nil {
+		fmt.Printf("GAME: Invalid bindings, using defaults: %v\n", err)
+		source, _ = ebiteninput.New(input.DefaultBindings())
+	}
+	return source
+}
+
+// pollInput reads this tick's input frame and remembers where to draw the crosshair
+func (g *Game) pollInput() coretypes.InputFrame {
+	view := input.View{
+		CameraX: g.CameraX,
+		CameraY: g.CameraY,
+		ScreenW: g.LastScreenW,
+		ScreenH: g.LastScreenH,
+	}
+	if player := g.Sim.Player(); player != nil {
+		view.SelectedSlot = player.SelectedSlot
+	}
+	frame := g.Input.Poll(view)
+	g.cursorX = int(frame.AimX - g.CameraX)
+	g.cursorY = int(frame.AimY - g.CameraY)
+	return frame
+}
@@ -0,0 +1,30 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KdntNinja/webcraft/netclient"
+)
+
+// NewNetworkGame joins the game server at url. The world comes from the server,
+// so nothing is saved or recorded locally.
+func NewNetworkGame(url string) (*Game, error) {
+	client, err := netclient.Dial(context.Background(), url, "player")
+	if err != nil {
+		return nil, err
+	}
+	return newGame(client.Welcome.Seed, "", nil, client), nil
+}
+
+// syncNetwork exchanges this tick's state with the server
+func (g *Game) syncNetwork() {
+	if err := g.network.Err(); err != nil {
+		if !g.networkClosed {
+			g.networkClosed = true
+			fmt.Printf("GAME: Lost connection to server: %v\n", err)
+		}
+		return
+	}
+	g.network.Sync(g.World, g.Sim.Tick)
+}
@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/KdntNinja/webcraft/replay"
+	"github.com/KdntNinja/webcraft/settings"
+	"github.com/KdntNinja/webcraft/storage"
+)
+
+// replayBackend is where replay files are read from and written to
+func replayBackend() storage.Backend {
+	return storage.NewDefaultBackend(settings.ReplayDirectory)
+}
+
+// NewReplayGame creates a game that plays back a replay file from the replay
+// directory instead of reading live input. Nothing is saved while it runs.
+func NewReplayGame(name string) (*Game, error) {
+	data, err := replayBackend().ReadFile(path.Base(name))
+	if err != nil {
+		return nil, err
+	}
+	r, err := replay.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("reading replay %s: %w", name, err)
+	}
+	for _, diff := range r.SettingsDiff() {
+		fmt.Printf("REPLAY: Setting differs from recording, playback may diverge: %s\n", diff)
+	}
+
+	g := newGame(r.Seed, "", nil, nil)
+	g.replayPlayer = replay.NewPlayer(r)
+	g.Input = g.replayPlayer
+	fmt.Printf("REPLAY: Playing %d ticks recorded with seed %d\n", len(r.Frames), r.Seed)
+	return g, nil
+}
+
+// stepSimulation advances the simulation one tick, recording or verifying a replay as needed
+func (g *Game) stepSimulation() {
+	frame := g.pollInput()
+	if g.inventoryScreen != nil && g.replayPlayer == nil {
+		frame = g.inventoryScreen.Input(frame)
+	}
+	if g.recorder != nil {
+		frame = g.recorder.Record(frame)
+	}
+
+	g.Sim.Step(frame)
+
+	if g.recorder != nil {
+		g.recorder.Checkpoint(g.Sim)
+	}
+	if g.replayPlayer != nil && !g.replayDiverged {
+		if d := g.replayPlayer.Verify(g.Sim); d != nil {
+			g.replayDiverged = true
+			fmt.Printf("REPLAY: %v\n", d)
+		} else if g.replayPlayer.Done() && g.Sim.Tick == uint64(len(g.replayPlayer.Replay.Frames)) {
+			fmt.Printf("REPLAY: Finished %d ticks with no divergence\n", g.Sim.Tick)
+		}
+	}
+}
+
+// SaveReplay writes the input recorded since the last flush to the replay file
+func (g *Game) SaveReplay() error {
+	if g.recorder == nil {
+		return fmt.Errorf("this session is not being recorded")
+	}
+	if err := g.recorder.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("GAME: Wrote replay of %d ticks to %s/%s\n", g.recorder.Ticks, settings.ReplayDirectory, settings.ReplayFileName)
+	return nil
+}
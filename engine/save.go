@@ -0,0 +1,215 @@
+package engine
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/gameplay"
+	"github.com/KdntNinja/webcraft/generation"
+	"github.com/KdntNinja/webcraft/inventory"
+	"github.com/KdntNinja/webcraft/settings"
+	"github.com/KdntNinja/webcraft/storage"
+)
+
+// DefaultSavePath returns the save slot used when no path is given
+func DefaultSavePath() string {
+	return filepath.Join(settings.SaveDirectory, settings.DefaultWorldName)
+}
+
+// LoadGame restores a world saved at path. The error wraps fs.ErrNotExist when
+// nothing has been saved there yet.
+func LoadGame(path string) (*Game, error) {
+	manifest, err := storage.LoadManifest(storage.NewDefaultBackend(path))
+	if err != nil {
+		return nil, err
+	}
+	if manifest.GeneratorVersion != generation.GeneratorVersion {
+		fmt.Printf("GAME: World was generated with generator v%d, current is v%d; unsaved chunks may not line up\n",
+			manifest.GeneratorVersion, generation.GeneratorVersion)
+	}
+	return newGame(manifest.Seed, path, manifest, nil), nil
+}
+
+// Save writes the world manifest and all modified chunks to path. Saving to a new
+// path moves any world already there aside, copies the existing save there and makes
+// it the game's save slot.
+func (g *Game) Save(path string) error {
+	if g.World == nil {
+		return fmt.Errorf("no world to save")
+	}
+	if path == "" {
+		return fmt.Errorf("no save path")
+	}
+
+	if path != g.SavePath {
+		// Keep whatever world was saved there rather than writing over it
+		if backup, err := storage.SetAsideWorld(path); err != nil {
+			return fmt.Errorf("moving the old world out of %s: %w", path, err)
+		} else if backup != "" {
+			fmt.Printf("GAME: Moved the old world in %s to %s\n", path, backup)
+		}
+		backend := storage.NewDefaultBackend(path)
+		if g.saveBackend != nil {
+			if err := storage.CopyWorld(g.saveBackend, backend); err != nil {
+				return fmt.Errorf("copying save to %s: %w", path, err)
+			}
+		}
+		g.SavePath = path
+		g.saveBackend = backend
+		g.World.ChunkManager.SetChunkStore(storage.NewRegionStore(backend, g.Seed))
+	}
+
+	if err := g.World.ChunkManager.SaveDirtyChunks(); err != nil {
+		return fmt.Errorf("saving chunks: %w", err)
+	}
+
+	manifest := &storage.WorldManifest{
+		Seed:             g.Seed,
+		GeneratorVersion: generation.GeneratorVersion,
+		Spawn:            storage.Position{X: g.spawn.X, Y: g.spawn.Y},
+		Time:             g.World.Clock.Ticks,
+		DayLength:        g.World.Clock.DayLength,
+	}
+	for i, e := range g.World.Entities {
+		if p, ok := e.(*gameplay.Player); ok {
+			manifest.Players = append(manifest.Players, playerRecord(fmt.Sprintf("player%d", i), p))
+		}
+	}
+	if err := storage.SaveManifest(g.saveBackend, manifest); err != nil {
+		return fmt.Errorf("saving manifest: %w", err)
+	}
+
+	fmt.Printf("GAME: Saved world to %s\n", path)
+	return nil
+}
+
+// restoreClock sets the time of day saved with the world. Saves from before the world
+// clock keep the new world's starting time.
+func (g *Game) restoreClock(manifest *storage.WorldManifest) {
+	if manifest.DayLength > 0 {
+		g.World.Clock.Ticks, g.World.Clock.DayLength = manifest.Time, manifest.DayLength
+	}
+}
+
+// restorePlayers applies saved player records to the world's players in order
+func (g *Game) restorePlayers(manifest *storage.WorldManifest) {
+	g.spawn.X, g.spawn.Y = manifest.Spawn.X, manifest.Spawn.Y
+	g.World.SpawnPoint = g.spawn
+
+	players := 0
+	for _, e := range g.World.Entities {
+		p, ok := e.(*gameplay.Player)
+		if !ok || players >= len(manifest.Players) {
+			continue
+		}
+		applyPlayerRecord(p, manifest.Players[players])
+		players++
+
+		// Stream chunks in around the restored position rather than the spawn
+		g.World.ChunkManager.UpdatePlayerPosition(p.X, p.Y)
+	}
+}
+
+// playerRecord captures a player's persistent state
+func playerRecord(name string, p *gameplay.Player) storage.PlayerRecord {
+	rec := storage.PlayerRecord{
+		Name:         name,
+		Position:     storage.Position{X: p.X, Y: p.Y},
+		Velocity:     storage.Position{X: p.VX, Y: p.VY},
+		Health:       p.Health,
+		MaxHealth:    p.MaxHealth,
+		SelectedSlot: p.SelectedSlot,
+		Slots:        []storage.SlotRecord{},
+	}
+	for i, stack := range p.Inventory.Slots {
+		if !stack.IsEmpty() {
+			rec.Slots = append(rec.Slots, slotRecord(i, stack))
+		}
+	}
+	if !p.Cursor.IsEmpty() {
+		cursor := slotRecord(-1, p.Cursor)
+		rec.Cursor = &cursor
+	}
+	return rec
+}
+
+// slotRecord captures one inventory stack
+func slotRecord(slot int, stack coretypes.ItemStack) storage.SlotRecord {
+	return storage.SlotRecord{
+		Slot:   slot,
+		Item:   stack.Item.String(),
+		Count:  stack.Count,
+		Damage: stack.Damage,
+		Meta:   stack.Meta,
+	}
+}
+
+// itemStack restores a saved stack, reporting false if the item no longer exists
+func itemStack(rec storage.SlotRecord) (coretypes.ItemStack, bool) {
+	item, ok := coretypes.ItemByName(rec.Item)
+	if !ok {
+		return coretypes.ItemStack{}, false
+	}
+	return coretypes.ItemStack{
+		Item:   item,
+		Count:  min(rec.Count, item.MaxStack()),
+		Damage: rec.Damage,
+		Meta:   rec.Meta,
+	}, true
+}
+
+// applyPlayerRecord restores a player's persistent state; unknown item names are dropped
+func applyPlayerRecord(p *gameplay.Player, rec storage.PlayerRecord) {
+	p.X, p.Y = rec.Position.X, rec.Position.Y
+	p.VX, p.VY = rec.Velocity.X, rec.Velocity.Y
+	p.MaxHealth = rec.MaxHealth
+	p.Health = rec.Health
+
+	p.Inventory = inventory.NewContainer(settings.InventorySize)
+	if rec.Slots == nil {
+		applyLegacyInventory(p, rec)
+		return
+	}
+	if rec.SelectedSlot >= 0 && rec.SelectedSlot < settings.HotbarSize {
+		p.SelectedSlot = rec.SelectedSlot
+	}
+	for _, slot := range rec.Slots {
+		if stack, ok := itemStack(slot); ok {
+			p.Inventory.Set(slot.Slot, stack)
+		}
+	}
+	p.Cursor = coretypes.ItemStack{}
+	if rec.Cursor != nil {
+		p.Cursor, _ = itemStack(*rec.Cursor)
+	}
+}
+
+// applyLegacyInventory restores a version 1 inventory: the hotbar's blocks go back
+// into their slots and the rest of the counts fill the inventory
+func applyLegacyInventory(p *gameplay.Player, rec storage.PlayerRecord) {
+	counts := make(map[coretypes.ItemID]int)
+	for name, count := range rec.Inventory {
+		if b, ok := coretypes.BlockTypeByName(name); ok && count > 0 {
+			counts[b.Item()] = count
+		}
+	}
+	for i, name := range rec.Hotbar {
+		b, ok := coretypes.BlockTypeByName(name)
+		if !ok || i >= settings.HotbarSize {
+			continue
+		}
+		if name == rec.SelectedBlock {
+			p.SelectedSlot = i
+		}
+		if count := counts[b.Item()]; count > 0 {
+			left := p.Inventory.Insert(i, coretypes.NewStack(b.Item(), count))
+			counts[b.Item()] = left.Count
+		}
+	}
+	for item := coretypes.ItemID(0); int(item) < coretypes.Items().Len(); item++ {
+		if counts[item] > 0 {
+			p.AddToInventory(item, counts[item])
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package fluid
+
+import (
+	"sort"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// World is the block access the simulator needs
+type World interface {
+	// LoadedChunks returns the chunks currently in memory; the simulator never loads more
+	LoadedChunks() map[coretypes.ChunkCoord]*coretypes.Chunk
+	// SetLiquid writes a block and its liquid fill level
+	SetLiquid(x, y int, blockType coretypes.BlockType, level int) bool
+}
+
+// Change is a cell the simulator rewrote during a step
+type Change struct {
+	X, Y  int
+	Block coretypes.BlockType
+	Level int // Liquid fill level, coretypes.MaxLiquidLevel for full liquids and other blocks
+}
+
+type cell struct {
+	X, Y int
+}
+
+// Simulator is a cellular automaton for liquids. Liquid cells that may still move
+// wait in a queue; each step updates queued cells in chunks near the players, up to
+// settings.FluidUpdateBudget of them, and cells that stop changing drop out of the
+// queue. Steps run in a fixed order so the same world always flows the same way.
+type Simulator struct {
+	world   World
+	queue   []cell
+	queued  map[cell]bool
+	scanned map[coretypes.ChunkCoord]bool // Active chunks whose liquids have been queued
+
+	// Per-step state
+	tick    uint64
+	chunks  map[coretypes.ChunkCoord]*coretypes.Chunk
+	active  map[coretypes.ChunkCoord]bool
+	changes []Change
+}
+
+// New creates a simulator for a world
+func New(world World) *Simulator {
+	return &Simulator{
+		world:   world,
+		queued:  make(map[cell]bool),
+		scanned: make(map[coretypes.ChunkCoord]bool),
+	}
+}
+
+// Wake queues a block and its neighbours after it changed outside the simulator,
+// e.g. when a player breaks the wall holding back a lake
+func (s *Simulator) Wake(x, y int) {
+	s.enqueue(cell{x, y})
+	s.enqueueNeighbours(cell{x, y})
+}
+
+// Pending returns the number of queued cells
+func (s *Simulator) Pending() int {
+	return len(s.queue)
+}
+
+// Step advances liquids in chunks within settings.FluidChunkRadius of the given
+// chunks (usually the players' chunks) and returns the cells it changed. The
+// returned slice is reused by the next step.
+func (s *Simulator) Step(tick uint64, centers []coretypes.ChunkCoord) []Change {
+	s.tick = tick
+	s.changes = s.changes[:0]
+	s.chunks = s.world.LoadedChunks()
+	s.active = make(map[coretypes.ChunkCoord]bool)
+	for coord := range s.chunks {
+		for _, c := range centers {
+			if abs(coord.X-c.X) <= settings.FluidChunkRadius && abs(coord.Y-c.Y) <= settings.FluidChunkRadius {
+				s.active[coord] = true
+				break
+			}
+		}
+	}
+
+	// Chunks that left the active area are scanned again when they come back
+	for coord := range s.scanned {
+		if !s.active[coord] {
+			delete(s.scanned, coord)
+		}
+	}
+	var fresh []coretypes.ChunkCoord
+	for coord := range s.active {
+		if !s.scanned[coord] {
+			fresh = append(fresh, coord)
+		}
+	}
+	sort.Slice(fresh, func(i, j int) bool {
+		if fresh[i].Y != fresh[j].Y {
+			return fresh[i].Y < fresh[j].Y
+		}
+		return fresh[i].X < fresh[j].X
+	})
+	for _, coord := range fresh {
+		s.scan(coord)
+	}
+
+	work := s.queue
+	s.queue = nil
+	var carry []cell
+	budget := settings.FluidUpdateBudget
+	for i, c := range work {
+		if budget == 0 {
+			carry = append(carry, work[i:]...)
+			break
+		}
+		if !s.active[coretypes.ChunkCoordOfBlock(c.X, c.Y)] {
+			delete(s.queued, c)
+			continue
+		}
+		block, _, _ := s.at(c)
+		if !block.IsLiquid() {
+			delete(s.queued, c)
+			continue
+		}
+		if tick%uint64(block.FlowInterval()) != 0 {
+			carry = append(carry, c)
+			continue
+		}
+		delete(s.queued, c)
+		budget--
+		s.update(c, block)
+	}
+	s.queue = append(carry, s.queue...)
+
+	s.chunks, s.active = nil, nil
+	return s.changes
+}
+
+// scan queues every liquid in a newly active chunk, plus liquids on the facing edges
+// of its neighbours that were held back while it was missing
+func (s *Simulator) scan(coord coretypes.ChunkCoord) {
+	s.scanned[coord] = true
+	chunk := s.chunks[coord]
+	baseX, baseY := coord.X*settings.ChunkWidth, coord.Y*settings.ChunkHeight
+	for y := range chunk.Blocks {
+		for x, block := range chunk.Blocks[y] {
+			if block.IsLiquid() {
+				s.enqueue(cell{baseX + x, baseY + y})
+			}
+		}
+	}
+
+	for y := baseY; y < baseY+settings.ChunkHeight; y++ {
+		s.enqueueIfLiquid(cell{baseX - 1, y})
+		s.enqueueIfLiquid(cell{baseX + settings.ChunkWidth, y})
+	}
+	for x := baseX; x < baseX+settings.ChunkWidth; x++ {
+		s.enqueueIfLiquid(cell{x, baseY - 1})
+		s.enqueueIfLiquid(cell{x, baseY + settings.ChunkHeight})
+	}
+}
+
+// update moves one liquid cell: react with other liquids, fall, then spread sideways
+func (s *Simulator) update(c cell, liquid coretypes.BlockType) {
+	_, level, _ := s.at(c)
+
+	for _, n := range neighbours(c) {
+		other, _, ok := s.at(n)
+		if !ok || !other.IsLiquid() || other == liquid {
+			continue
+		}
+		if result, ok := liquid.Reaction(other); ok {
+			s.set(c, result, coretypes.MaxLiquidLevel)
+			return
+		}
+		if result, ok := other.Reaction(liquid); ok {
+			s.set(n, result, coretypes.MaxLiquidLevel)
+		}
+	}
+
+	below := cell{c.X, c.Y + 1}
+	if room := s.room(below, liquid); room > 0 {
+		move := min(room, level)
+		s.set(below, liquid, coretypes.MaxLiquidLevel-room+move)
+		level -= move
+		s.setLevel(c, liquid, level)
+		if level == 0 {
+			return
+		}
+	}
+
+	// Alternate which side goes first so liquids don't drift one way
+	sides := [2]cell{{c.X - 1, c.Y}, {c.X + 1, c.Y}}
+	if (s.tick/uint64(liquid.FlowInterval())+uint64(c.X))%2 == 1 {
+		sides[0], sides[1] = sides[1], sides[0]
+	}
+	for _, n := range sides {
+		room := s.room(n, liquid)
+		if room == 0 {
+			continue
+		}
+		neighbourLevel := coretypes.MaxLiquidLevel - room
+		if level-neighbourLevel < 2 {
+			continue
+		}
+		s.set(n, liquid, neighbourLevel+1)
+		level--
+		s.setLevel(c, liquid, level)
+	}
+}
+
+// room returns how much of a liquid a cell can take: a full cell's worth for air,
+// the missing part for the same liquid, and nothing for anything else
+func (s *Simulator) room(c cell, liquid coretypes.BlockType) int {
+	block, level, ok := s.at(c)
+	switch {
+	case !ok:
+		return 0
+	case block == coretypes.Air:
+		return coretypes.MaxLiquidLevel
+	case block == liquid:
+		return coretypes.MaxLiquidLevel - level
+	}
+	return 0
+}
+
+// setLevel writes a liquid's new level, turning the cell into air once it is empty
+func (s *Simulator) setLevel(c cell, liquid coretypes.BlockType, level int) {
+	if level <= 0 {
+		s.set(c, coretypes.Air, coretypes.MaxLiquidLevel)
+		return
+	}
+	s.set(c, liquid, level)
+}
+
+// set writes a cell, records the change and wakes the cell and its neighbours
+func (s *Simulator) set(c cell, block coretypes.BlockType, level int) {
+	if !s.world.SetLiquid(c.X, c.Y, block, level) {
+		return
+	}
+	s.changes = append(s.changes, Change{X: c.X, Y: c.Y, Block: block, Level: level})
+	s.enqueue(c)
+	s.enqueueNeighbours(c)
+}
+
+// at returns the block and liquid level of a cell, and false if its chunk isn't loaded
+func (s *Simulator) at(c cell) (coretypes.BlockType, int, bool) {
+	coord := coretypes.ChunkCoordOfBlock(c.X, c.Y)
+	chunk := s.chunks[coord]
+	if chunk == nil || len(chunk.Blocks) == 0 {
+		return coretypes.Air, 0, false
+	}
+	x, y := c.X-coord.X*settings.ChunkWidth, c.Y-coord.Y*settings.ChunkHeight
+	return chunk.Blocks[y][x], chunk.LiquidLevel(x, y), true
+}
+
+func (s *Simulator) enqueue(c cell) {
+	if !s.queued[c] {
+		s.queued[c] = true
+		s.queue = append(s.queue, c)
+	}
+}
+
+func (s *Simulator) enqueueIfLiquid(c cell) {
+	if block, _, ok := s.at(c); ok && block.IsLiquid() {
+		s.enqueue(c)
+	}
+}
+
+func (s *Simulator) enqueueNeighbours(c cell) {
+	for _, n := range neighbours(c) {
+		s.enqueue(n)
+	}
+}
+
+// neighbours lists the four adjacent cells, below first
+func neighbours(c cell) [4]cell {
+	return [4]cell{{c.X, c.Y + 1}, {c.X - 1, c.Y}, {c.X + 1, c.Y}, {c.X, c.Y - 1}}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
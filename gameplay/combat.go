@@ -0,0 +1,91 @@
+package gameplay
+
+import (
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/physics"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// tickCombatTimers counts down invulnerability and the swing cooldown
+func (p *Player) tickCombatTimers() {
+	if p.Invulnerable > 0 {
+		p.Invulnerable--
+	}
+	if p.AttackTimer > 0 {
+		p.AttackTimer--
+	}
+	if p.hurtTimer > 0 {
+		p.hurtTimer--
+	}
+}
+
+// Hurt deals damage and knockback unless the player is invulnerable, then makes
+// the player invulnerable for a moment. It reports whether the hit landed.
+func (p *Player) Hurt(amount int, knockbackX, knockbackY float64) bool {
+	if p.Invulnerable > 0 || p.Health <= 0 {
+		return false
+	}
+	p.TakeDamage(amount)
+	p.Knockback(knockbackX, knockbackY)
+	p.Invulnerable = settings.PlayerInvulnerableTicks
+	p.hurtTimer = settings.PlayerInvulnerableTicks / 2
+	return true
+}
+
+// TryAttack starts a swing if attack is held and the last swing has cooled down
+func (p *Player) TryAttack() bool {
+	if !p.Input.Attack || p.AttackTimer > 0 || p.Health <= 0 {
+		return false
+	}
+	p.AttackTimer = settings.PlayerAttackCooldown
+	return true
+}
+
+// Hurting reports whether the player was hit moments ago, for drawing
+func (p *Player) Hurting() bool {
+	return p.hurtTimer > 0
+}
+
+// SwingSide returns which side the player is swinging at, -1 (left) or 1 (right),
+// or 0 when not mid-swing, for drawing the swing
+func (p *Player) SwingSide() int {
+	if p.AttackTimer <= settings.PlayerAttackCooldown/2 {
+		return 0
+	}
+	if cx, _ := p.Center(); p.Input.AimX < cx {
+		return -1
+	}
+	return 1
+}
+
+// Center returns the middle of the player's collider
+func (p *Player) Center() (float64, float64) {
+	return p.X + float64(p.Width)/2, p.Y + float64(p.Height)/2
+}
+
+// DropInventory empties the inventory and the stack being carried, returning the
+// stacks that were held
+func (p *Player) DropInventory() []coretypes.ItemStack {
+	p.lastEmptiedHotbarSlot = -1
+	dropped := p.Inventory.Clear()
+	if !p.Cursor.IsEmpty() {
+		dropped = append(dropped, p.Cursor)
+	}
+	p.Cursor = coretypes.ItemStack{}
+	return dropped
+}
+
+// Respawn brings the player back to life at a spawn point (sprite coordinates, as
+// for NewPlayer) with full health and a moment of invulnerability
+func (p *Player) Respawn(x, y float64) {
+	p.X = x + float64(settings.PlayerSpriteWidth-settings.PlayerColliderWidth)/2
+	p.Y = y + float64(settings.PlayerSpriteHeight-settings.PlayerColliderHeight)
+	p.VX, p.VY = 0, 0
+	p.OnGround = false
+	p.Health = p.MaxHealth
+	p.Invulnerable = settings.RespawnInvulnerableTicks
+	p.AttackTimer = 0
+	p.hurtTimer = 0
+	p.Hazards = physics.HazardState{}
+	p.StopMining()
+}
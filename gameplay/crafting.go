@@ -0,0 +1,40 @@
+package gameplay
+
+import (
+	"math"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/crafting"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// NearBlock reports whether a block is within settings.CraftingStationRange blocks
+// of the middle of the player, so it can be used as a crafting station
+func (p *Player) NearBlock(block coretypes.BlockType) bool {
+	if p.World == nil {
+		return false
+	}
+	tileSize := float64(settings.TileSize)
+	cx, cy := p.Center()
+	bx, by := int(math.Floor(cx/tileSize)), int(math.Floor(cy/tileSize))
+	r := settings.CraftingStationRange
+	for y := by - r; y <= by+r; y++ {
+		for x := bx - r; x <= bx+r; x++ {
+			if p.World.GetBlockAt(x, y) == block {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CraftableRecipes returns the recipes the player can craft right now
+func (p *Player) CraftableRecipes() []*crafting.Recipe {
+	return crafting.Recipes().Available(p.Inventory, p.NearBlock)
+}
+
+// Craft makes one batch of a recipe from the player's inventory and reports whether it did
+func (p *Player) Craft(recipeID int) bool {
+	recipe := crafting.Recipes().Get(recipeID)
+	return recipe != nil && crafting.Craft(p.Inventory, recipe, p.NearBlock)
+}
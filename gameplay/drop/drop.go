@@ -0,0 +1,151 @@
+package drop
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/gameplay"
+	"github.com/KdntNinja/webcraft/physics"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// Item is a stack lying in the world. Its AABB falls and slides through the same
+// physics as other entities; players close by pull it in and pick it up.
+type Item struct {
+	physics.AABB
+	Stack       coretypes.ItemStack
+	Age         int // Ticks since the item was dropped; it despawns at settings.ItemDespawnTicks
+	PickupDelay int // Ticks left before a player can pick the item up
+}
+
+// New creates an item centred on x, y moving at vx, vy
+func New(stack coretypes.ItemStack, x, y, vx, vy float64) *Item {
+	size := settings.ItemEntitySize
+	return &Item{
+		AABB: physics.AABB{
+			X:      x - float64(size)/2,
+			Y:      y - float64(size)/2,
+			Width:  size,
+			Height: size,
+			VX:     vx,
+			VY:     vy,
+		},
+		Stack:       stack,
+		PickupDelay: settings.ItemPickupDelay,
+	}
+}
+
+// Scatter creates an item for each stack centred on x, y, flung up and out in a
+// random direction
+func Scatter(stacks []coretypes.ItemStack, x, y float64, rng *rand.Rand) []*Item {
+	items := make([]*Item, 0, len(stacks))
+	for _, stack := range stacks {
+		if stack.IsEmpty() {
+			continue
+		}
+		vx := (rng.Float64()*2 - 1) * settings.ItemScatterSpeed
+		items = append(items, New(stack, x, y, vx, -settings.ItemScatterLift))
+	}
+	return items
+}
+
+// Update ages the item and applies friction, gravity and buoyancy. Collisions and
+// liquids are applied afterwards by the simulation.
+func (i *Item) Update() {
+	i.Age++
+	if i.PickupDelay > 0 {
+		i.PickupDelay--
+	}
+	i.ApplyHorizontalMovement(0, settings.ItemGroundFriction, settings.ItemAirResistance, false)
+	i.ApplyVerticalMovement(settings.PlayerGravity, settings.PlayerMaxFallSpeed)
+	if i.Submerged > 0 {
+		i.VY += settings.LiquidBuoyancy * i.Submerged
+	}
+}
+
+// Center returns the middle of the item's collider
+func (i *Item) Center() (float64, float64) {
+	return i.X + float64(i.Width)/2, i.Y + float64(i.Height)/2
+}
+
+// Dead reports whether the item has been picked up or has lain around too long;
+// the simulation removes dead items
+func (i *Item) Dead() bool {
+	return i.Stack.IsEmpty() || i.Age >= settings.ItemDespawnTicks
+}
+
+// DroppedStack returns the stack the item holds, for drawing
+func (i *Item) DroppedStack() coretypes.ItemStack {
+	return i.Stack
+}
+
+// Merge combines matching items that lie within settings.ItemMergeRadius of each
+// other. Later items are poured into earlier ones, up to the item's stack size.
+func Merge(items []*Item) {
+	for a, into := range items {
+		for _, from := range items[a+1:] {
+			if into.Dead() || from.Dead() || !into.Stack.StacksWith(from.Stack) || distance(into, from) > settings.ItemMergeRadius {
+				continue
+			}
+			moved := min(from.Stack.Count, into.Stack.Item.MaxStack()-into.Stack.Count)
+			if moved <= 0 {
+				continue
+			}
+			into.Stack.Count += moved
+			from.Stack.Count -= moved
+			into.Age = min(into.Age, from.Age)
+			into.PickupDelay = max(into.PickupDelay, from.PickupDelay)
+		}
+	}
+}
+
+// Collect pulls each item towards the nearest living player within
+// settings.ItemMagnetRadius, and gives it to them once it is within
+// settings.ItemPickupRadius. Whatever doesn't fit in their inventory stays put.
+func Collect(items []*Item, players []*gameplay.Player) {
+	for _, item := range items {
+		if item.Dead() || item.PickupDelay > 0 {
+			continue
+		}
+		ix, iy := item.Center()
+		var nearest *gameplay.Player
+		best := math.Inf(1)
+		for _, p := range players {
+			if p.Health <= 0 {
+				continue
+			}
+			px, py := p.Center()
+			if d := math.Hypot(px-ix, py-iy); d <= settings.ItemMagnetRadius && d < best {
+				nearest, best = p, d
+			}
+		}
+		if nearest == nil {
+			continue
+		}
+
+		if best <= settings.ItemPickupRadius {
+			item.Stack = nearest.AddStack(item.Stack)
+			continue
+		}
+		if !hasRoom(nearest, item.Stack) {
+			continue // Don't keep tugging at items the player can't take
+		}
+		px, py := nearest.Center()
+		item.VX = (px - ix) / best * settings.ItemMagnetSpeed
+		item.VY = (py - iy) / best * settings.ItemMagnetSpeed
+		item.OnGround = false
+	}
+}
+
+// hasRoom reports whether any of a stack would fit in the player's inventory
+func hasRoom(p *gameplay.Player, stack coretypes.ItemStack) bool {
+	return p.Inventory.Clone().Add(stack).Count < stack.Count
+}
+
+// distance returns how far apart the centres of two items are
+func distance(a, b *Item) float64 {
+	ax, ay := a.Center()
+	bx, by := b.Center()
+	return math.Hypot(bx-ax, by-ay)
+}
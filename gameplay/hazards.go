@@ -0,0 +1,28 @@
+package gameplay
+
+import (
+	"github.com/KdntNinja/webcraft/physics"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// ApplyHazards hurts the player for a hard landing, drowning or burning this tick.
+// Environmental damage ignores invulnerability; each hazard paces its own hits.
+func (p *Player) ApplyHazards(world *physics.PhysicsWorld) {
+	if p.Health <= 0 {
+		return
+	}
+	if damage := p.AABB.ApplyHazards(world, &p.Hazards, p.HazardConfig); damage > 0 {
+		p.TakeDamage(damage)
+		p.hurtTimer = settings.PlayerInvulnerableTicks / 2
+	}
+}
+
+// OnFire reports whether the player is burning, for drawing
+func (p *Player) OnFire() bool {
+	return p.Hazards.Burning > 0
+}
+
+// Breath returns the player's breath left and their full breath, in ticks
+func (p *Player) Breath() (int, int) {
+	return p.Hazards.Breath(p.HazardConfig), p.HazardConfig.MaxBreath
+}
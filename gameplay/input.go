@@ -1,19 +1,14 @@
 package gameplay
 
 import (
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
-
 	"github.com/KdntNinja/webcraft/settings"
 )
 
 // BlockInteraction represents a block interaction event
 type BlockInteraction struct {
-	Type    BlockInteractionType
-	BlockX  int // Block coordinate
-	BlockY  int // Block coordinate
-	ScreenX int // Screen pixel coordinate
-	ScreenY int // Screen pixel coordinate
+	Type   BlockInteractionType
+	BlockX int // Block coordinate
+	BlockY int // Block coordinate
 }
 
 type BlockInteractionType int
@@ -23,84 +18,89 @@ const (
 	PlaceBlock
 )
 
-// HandleInput processes keyboard and mouse input and returns movement intentions and block interactions
-func (p *Player) HandleInput(cameraX, cameraY float64) (isMoving bool, targetVX float64, jumpKeyPressed bool, blockInteraction *BlockInteraction) {
-	// Sneak (hold Shift)
-	p.InputState.SneakPressed = ebiten.IsKeyPressed(ebiten.KeyShift) || ebiten.IsKeyPressed(ebiten.KeyShiftLeft) || ebiten.IsKeyPressed(ebiten.KeyShiftRight)
+// HandleInput turns the player's current input frame into movement intentions
+func (p *Player) HandleInput() (isMoving bool, targetVX float64, jumpKeyPressed bool) {
+	frame := p.Input
+
+	p.InputState.SneakPressed = frame.Sneak
+	p.IsSprinting = frame.Sprint && !frame.Sneak
 	isMoving = false
 	targetVX = 0.0
 
-	// Check horizontal movement keys (WASD + arrows)
-	if ebiten.IsKeyPressed(ebiten.KeyLeft) || ebiten.IsKeyPressed(ebiten.KeyA) {
+	// Horizontal movement intent
+	if frame.MoveX < 0 {
 		targetVX = -settings.PlayerMoveSpeed
 		isMoving = true
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyRight) || ebiten.IsKeyPressed(ebiten.KeyD) {
+	if frame.MoveX > 0 {
 		targetVX = settings.PlayerMoveSpeed
 		isMoving = true
 	}
 
-	// Check jump keys (multiple options for accessibility)
-	jumpKeyPressed = ebiten.IsKeyPressed(ebiten.KeyUp) || ebiten.IsKeyPressed(ebiten.KeyW) || ebiten.IsKeyPressed(ebiten.KeySpace)
+	jumpKeyPressed = frame.Jump
 
-	// Handle block selection with number keys
+	// Handle block selection from the hotbar
 	p.handleBlockSelection()
 
-	// Handle mouse input for block interaction (instant response)
-	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) || ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight) {
-		mouseX, mouseY := ebiten.CursorPosition()
-		// Use the original mapping: mouseX/mouseY are relative to the top-left of the screen.
-		worldX := float64(mouseX) + cameraX
-		worldY := float64(mouseY) + cameraY
-		blockX := int(worldX / float64(settings.TileSize))
-		blockY := int(worldY / float64(settings.TileSize))
-		if worldX < 0 {
-			blockX = int(worldX/float64(settings.TileSize)) - 1
-		}
-		if worldY < 0 {
-			blockY = int(worldY/float64(settings.TileSize)) - 1
-		}
-
-		// Check if block is within interaction range (from player center to block center, matching crosshair)
-		playerCenterX := p.AABB.X + float64(p.AABB.Width)/2
-		playerCenterY := p.AABB.Y + float64(p.AABB.Height)/2
-		blockCenterX := float64(blockX)*float64(settings.TileSize) + float64(settings.TileSize)/2
-		blockCenterY := float64(blockY)*float64(settings.TileSize) + float64(settings.TileSize)/2
-		dx := blockCenterX - playerCenterX
-		dy := blockCenterY - playerCenterY
-		distance := dx*dx + dy*dy
-
-		if distance <= p.InteractionRange*p.InteractionRange {
-			// Determine interaction type
-			var interactionType BlockInteractionType
-			if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-				interactionType = BreakBlock
-			} else {
-				interactionType = PlaceBlock
-			}
-
-			// Reset cooldown timer
-			p.LastInteractionTime = 0
-
-			blockInteraction = &BlockInteraction{
-				Type:    interactionType,
-				BlockX:  blockX,
-				BlockY:  blockY,
-				ScreenX: mouseX,
-				ScreenY: mouseY,
-			}
-		}
+	return isMoving, targetVX, jumpKeyPressed
+}
+
+// HandleBlockInteractions returns the break/place request for the current input frame, if any
+func (p *Player) HandleBlockInteractions() *BlockInteraction {
+	frame := p.Input
+	if !frame.Break {
+		p.StopMining()
+	}
+	if !frame.Break && !frame.Place {
+		return nil
+	}
+
+	blockX := int(frame.AimX / float64(settings.TileSize))
+	blockY := int(frame.AimY / float64(settings.TileSize))
+	if frame.AimX < 0 {
+		blockX = int(frame.AimX/float64(settings.TileSize)) - 1
+	}
+	if frame.AimY < 0 {
+		blockY = int(frame.AimY/float64(settings.TileSize)) - 1
+	}
+
+	if !p.InReach(blockX, blockY) {
+		p.StopMining()
+		return nil
+	}
+
+	// Breaking wins when both buttons are held
+	interactionType := PlaceBlock
+	if frame.Break {
+		interactionType = BreakBlock
 	}
 
-	return isMoving, targetVX, jumpKeyPressed, blockInteraction
+	// Reset cooldown timer
+	p.LastInteractionTime = 0
+
+	return &BlockInteraction{
+		Type:   interactionType,
+		BlockX: blockX,
+		BlockY: blockY,
+	}
+}
+
+// InReach reports whether a block is within interaction range, measured from the
+// player's center to the block's center (matching the crosshair)
+func (p *Player) InReach(blockX, blockY int) bool {
+	playerCenterX := p.AABB.X + float64(p.AABB.Width)/2
+	playerCenterY := p.AABB.Y + float64(p.AABB.Height)/2
+	blockCenterX := float64(blockX)*float64(settings.TileSize) + float64(settings.TileSize)/2
+	blockCenterY := float64(blockY)*float64(settings.TileSize) + float64(settings.TileSize)/2
+	dx := blockCenterX - playerCenterX
+	dy := blockCenterY - playerCenterY
+	return dx*dx+dy*dy <= p.InteractionRange*p.InteractionRange
 }
 
-// handleBlockSelection processes number key input to change selected block type
+// handleBlockSelection applies a hotbar slot change from the input frame
 func (p *Player) handleBlockSelection() {
-	for i := 0; i < len(p.Hotbar) && i < 9; i++ {
-		key := ebiten.Key(int(ebiten.Key1) + i)
-		if inpututil.IsKeyJustPressed(key) {
-			p.SelectedBlock = p.Hotbar[i]
-		}
+	slot := p.Input.HotbarSlot
+	if slot >= 0 && slot < settings.HotbarSize {
+		p.SelectedSlot = slot
 	}
 }
@@ -0,0 +1,32 @@
+package gameplay
+
+import (
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// ApplyInventoryOp performs an inventory screen action. Clicks move stacks between
+// the slots and the stack the player is carrying (Cursor); quick moves send a stack
+// between the hotbar and the main inventory; sorting only touches the main inventory;
+// crafting makes the recipe in the op's slot field.
+func (p *Player) ApplyInventoryOp(op coretypes.InventoryOp) {
+	inv := p.Inventory
+	switch op.Action {
+	case coretypes.InventoryClick:
+		p.Cursor = inv.Click(op.Slot, p.Cursor)
+	case coretypes.InventorySplit:
+		p.Cursor = inv.Split(op.Slot, p.Cursor)
+	case coretypes.InventoryQuickMove:
+		if op.Slot < settings.HotbarSize {
+			inv.MoveTo(op.Slot, settings.HotbarSize, inv.Len())
+		} else {
+			inv.MoveTo(op.Slot, 0, settings.HotbarSize)
+		}
+	case coretypes.InventorySort:
+		inv.Sort(settings.HotbarSize, inv.Len())
+	case coretypes.InventoryReturn:
+		p.Cursor = inv.Add(p.Cursor)
+	case coretypes.InventoryCraft:
+		p.Craft(op.Slot)
+	}
+}
@@ -0,0 +1,95 @@
+package gameplay
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/loot"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// MiningState is the block a player is breaking and how far along they are
+type MiningState struct {
+	X, Y     int
+	Block    coretypes.BlockType
+	Progress int // Ticks spent breaking the block; 0 when not mining
+}
+
+// Mine spends one tick breaking a block and reports whether it broke. Progress
+// starts over whenever the target block changes. Breaking a block wears the
+// pickaxe in hand.
+func (p *Player) Mine(blockX, blockY int, block coretypes.BlockType) bool {
+	if !block.IsBreakable() {
+		p.StopMining()
+		return false
+	}
+	m := &p.Mining
+	if m.Progress == 0 || m.X != blockX || m.Y != blockY || m.Block != block {
+		*m = MiningState{X: blockX, Y: blockY, Block: block}
+	}
+	m.Progress++
+	if m.Progress < p.MiningTicks(block) {
+		return false
+	}
+	p.StopMining()
+	p.wearTool()
+	return true
+}
+
+// Tool returns the tier of the pickaxe in hand, TierHand if the player holds none
+func (p *Player) Tool() coretypes.ToolTier {
+	return p.SelectedItem().Item.ToolTier()
+}
+
+// wearTool uses up one use of the item in hand, breaking it when it runs out
+func (p *Player) wearTool() {
+	slot := &p.Inventory.Slots[p.SelectedSlot]
+	durability := slot.Item.Durability()
+	if slot.IsEmpty() || durability == 0 {
+		return
+	}
+	slot.Damage++
+	if slot.Damage >= durability {
+		p.Inventory.Set(p.SelectedSlot, coretypes.ItemStack{})
+		p.lastEmptiedHotbarSlot = p.SelectedSlot
+	}
+}
+
+// StopMining drops the progress on the block being broken
+func (p *Player) StopMining() {
+	p.Mining = MiningState{}
+}
+
+// MiningTicks returns how many ticks the player takes to break a block with their pickaxe
+func (p *Player) MiningTicks(block coretypes.BlockType) int {
+	ticks := block.Hardness() * settings.MiningTicksPerHardness / p.Tool().MiningSpeed()
+	if !p.CanHarvest(block) {
+		ticks *= settings.MiningUnharvestableSlowdown
+	}
+	return max(int(math.Ceil(ticks)), 1)
+}
+
+// CanHarvest reports whether the player's pickaxe is good enough for a block to drop anything
+func (p *Player) CanHarvest(block coretypes.BlockType) bool {
+	return p.Tool() >= block.HarvestTier()
+}
+
+// Harvest rolls what breaking a block drops from its loot table, or nothing if the
+// player's pickaxe can't harvest it
+func (p *Player) Harvest(block coretypes.BlockType, rng *rand.Rand) []coretypes.ItemStack {
+	if !p.CanHarvest(block) {
+		return nil
+	}
+	return loot.Default().Roll(block, rng)
+}
+
+// MiningProgress returns the block being broken and how far along it is, from 0 to 1.
+// It reports false when the player isn't mining.
+func (p *Player) MiningProgress() (int, int, float64, bool) {
+	m := p.Mining
+	if m.Progress == 0 {
+		return 0, 0, 0, false
+	}
+	return m.X, m.Y, float64(m.Progress) / float64(p.MiningTicks(m.Block)), true
+}
@@ -0,0 +1,210 @@
+package mob
+
+import (
+	"math"
+
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// Behaviour is a mob's AI. Think runs once per tick, before the mob moves, and
+// returns where the mob wants to go. Behaviours keep their own state, so each mob
+// needs its own instance (Kind.NewBehaviour makes one).
+type Behaviour interface {
+	Think(m *Mob) Intent
+}
+
+// Intent is the movement a behaviour asks for
+type Intent struct {
+	MoveX float64 // Horizontal direction, -1 to 1
+	MoveY float64 // Vertical direction, -1 (up) to 1; only flying and swimming mobs use it
+	Jump  bool    // Jump, or paddle upwards in a liquid
+}
+
+// Wander walks a random way for a while, stands still for a while, and hops over
+// anything in its path
+type Wander struct {
+	dir   float64
+	ticks int
+}
+
+// NewWander creates a wandering behaviour
+func NewWander() Behaviour {
+	return &Wander{}
+}
+
+func (w *Wander) Think(m *Mob) Intent {
+	if w.ticks <= 0 {
+		w.ticks = wanderTicks(m)
+		w.dir = 0
+		if m.Rand().Float64() >= settings.MobIdleChance {
+			w.dir = float64(m.Rand().Intn(2)*2 - 1)
+		}
+	}
+	w.ticks--
+	if m.Blocked() && m.Kind.JumpSpeed == 0 {
+		w.dir = -w.dir // Can't hop over it, so turn around
+	}
+	return Intent{MoveX: w.dir, Jump: w.dir != 0 && (m.Blocked() || m.InLiquid())}
+}
+
+// Chase runs at the nearest player in sight, jumping over obstacles and up to
+// players above it. With no player in sight it falls back to Idle.
+type Chase struct {
+	Idle Behaviour
+}
+
+// NewChase creates a chasing behaviour that wanders while idle
+func NewChase() Behaviour {
+	return &Chase{Idle: NewWander()}
+}
+
+func (c *Chase) Think(m *Mob) Intent {
+	target, ok := m.NearestPlayer(m.Kind.SightRange)
+	if !ok {
+		return idle(c.Idle, m)
+	}
+	cx, cy := m.Center()
+	tx, ty := livingCenter(target)
+	dir := sign(tx - cx)
+	return Intent{MoveX: dir, Jump: m.Blocked() || m.InLiquid() || ty < cy-float64(settings.TileSize)}
+}
+
+// Flee runs away from the nearest player within Range pixels, jumping over
+// obstacles. Otherwise it falls back to Idle.
+type Flee struct {
+	Range float64
+	Idle  Behaviour
+}
+
+// NewFlee creates a fleeing behaviour that wanders while idle. It flees from
+// players within the mob's sight range.
+func NewFlee() Behaviour {
+	return &Flee{Idle: NewWander()}
+}
+
+func (f *Flee) Think(m *Mob) Intent {
+	sight := f.Range
+	if sight == 0 {
+		sight = m.Kind.SightRange
+	}
+	threat, ok := m.NearestPlayer(sight)
+	if !ok {
+		return idle(f.Idle, m)
+	}
+	cx, _ := m.Center()
+	tx, _ := livingCenter(threat)
+	dir := -sign(tx - cx)
+	if dir == 0 {
+		dir = float64(m.Facing)
+	}
+	return Intent{MoveX: dir, Jump: m.Blocked() || m.InLiquid()}
+}
+
+// Fly drifts about in the air, changing course now and then, and dives at players
+// in sight if Hunt is set. Flying mobs aren't pulled down by gravity.
+type Fly struct {
+	Hunt         bool
+	dirX, dirY   float64
+	ticks        int
+	lastX, lastY float64
+}
+
+// NewFly creates a flying behaviour that ignores players
+func NewFly() Behaviour {
+	return &Fly{}
+}
+
+// NewHuntingFly creates a flying behaviour that dives at players in sight
+func NewHuntingFly() Behaviour {
+	return &Fly{Hunt: true}
+}
+
+func (f *Fly) Think(m *Mob) Intent {
+	if f.Hunt {
+		if target, ok := m.NearestPlayer(m.Kind.SightRange); ok {
+			cx, cy := m.Center()
+			tx, ty := livingCenter(target)
+			dx, dy := normalize(tx-cx, ty-cy)
+			return Intent{MoveX: dx, MoveY: dy}
+		}
+	}
+
+	// Pick a new heading when the current one runs out or the mob is stuck
+	stuck := m.Age > 1 && math.Abs(m.X-f.lastX) < 0.01 && math.Abs(m.Y-f.lastY) < 0.01
+	f.lastX, f.lastY = m.X, m.Y
+	if f.ticks <= 0 || stuck {
+		f.ticks = wanderTicks(m)
+		angle := m.Rand().Float64() * 2 * math.Pi
+		f.dirX, f.dirY = math.Cos(angle), math.Sin(angle)*0.5
+	}
+	f.ticks--
+	return Intent{MoveX: f.dirX, MoveY: f.dirY}
+}
+
+// Swim wanders about in liquids in any direction without rising or sinking, and
+// flops towards liquid when stranded on land
+type Swim struct {
+	dirX, dirY float64
+	ticks      int
+}
+
+// NewSwim creates a swimming behaviour
+func NewSwim() Behaviour {
+	return &Swim{}
+}
+
+func (s *Swim) Think(m *Mob) Intent {
+	if !m.InLiquid() {
+		// Stranded: flop about in the hope of landing in water
+		if m.OnGround && m.Rand().Intn(20) == 0 {
+			return Intent{MoveX: float64(m.Rand().Intn(3) - 1), Jump: true}
+		}
+		return Intent{}
+	}
+
+	if s.ticks <= 0 || m.Blocked() {
+		s.ticks = wanderTicks(m)
+		angle := m.Rand().Float64() * 2 * math.Pi
+		s.dirX, s.dirY = math.Cos(angle), math.Sin(angle)*0.3
+	}
+	s.ticks--
+
+	// Stay below the surface
+	cx, _ := m.Center()
+	if s.dirY < 0 && !m.blockAt(cx, m.Y-1).IsLiquid() {
+		s.dirY = -s.dirY
+	}
+	return Intent{MoveX: s.dirX, MoveY: s.dirY}
+}
+
+// idle runs a fallback behaviour, if there is one
+func idle(b Behaviour, m *Mob) Intent {
+	if b == nil {
+		return Intent{}
+	}
+	return b.Think(m)
+}
+
+// wanderTicks picks how long a mob keeps its current heading
+func wanderTicks(m *Mob) int {
+	return settings.MobWanderMinTicks + m.Rand().Intn(settings.MobWanderMaxTicks-settings.MobWanderMinTicks+1)
+}
+
+func sign(v float64) float64 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// normalize scales a vector to length 1, leaving the zero vector alone
+func normalize(x, y float64) (float64, float64) {
+	length := math.Hypot(x, y)
+	if length == 0 {
+		return 0, 0
+	}
+	return x / length, y / length
+}
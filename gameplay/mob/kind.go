@@ -0,0 +1,72 @@
+package mob
+
+import (
+	"sort"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+// Kind describes one type of mob. Every mob of a kind shares it.
+type Kind struct {
+	Name       string
+	Width      int     // Collider size in pixels
+	Height     int     //
+	MaxHealth  int     //
+	Speed      float64 // Top speed in pixels per tick
+	JumpSpeed  float64 // Upward speed of a jump, about 7 to clear a block; 0 for mobs that can't jump
+	Hostile    bool    // Attacks players
+	Damage     int     // Damage dealt to players on contact, for hostile mobs
+	SightRange float64 // Distance in pixels at which the mob notices players
+	Flying     bool    // Ignores gravity and moves freely in the air
+	Aquatic    bool    // Swims freely in liquids and flops about on land
+
+	// Sprite
+	Color      [3]uint8 // Body colour
+	Frames     int      // Animation frames
+	FrameTicks int      // Ticks each frame is shown while moving
+
+	// Where the kind spawns naturally
+	Spawn SpawnRule
+
+	// NewBehaviour creates the AI for a new mob of this kind
+	NewBehaviour func() Behaviour
+}
+
+// SpawnRule says where a kind of mob spawns as chunks load. Ground mobs spawn in
+// air above a solid block, flying mobs in open air and aquatic mobs in water.
+type SpawnRule struct {
+	Weight   int      // Relative chance of being picked; 0 never spawns naturally
+	MinDepth int      // Shallowest cell, in blocks below the surface (negative is above it)
+	MaxDepth int      // Deepest cell
+	MinLight int      // Dimmest cell, 0 to coretypes.MaxLight, ignoring time of day
+	MaxLight int      // Brightest cell
+	Biomes   []string // Biomes the kind spawns in; empty for any
+}
+
+// kinds holds every registered mob kind by name
+var kinds = make(map[string]*Kind)
+
+// Register adds a mob kind, replacing any kind with the same name. Register kinds
+// during initialisation, before any world is created.
+func Register(kind *Kind) {
+	if kind.Name == coretypes.KindPlayer {
+		panic("mob: kind name " + kind.Name + " is reserved")
+	}
+	kinds[kind.Name] = kind
+}
+
+// KindByName returns a registered mob kind
+func KindByName(name string) (*Kind, bool) {
+	kind, ok := kinds[name]
+	return kind, ok
+}
+
+// Kinds returns every registered mob kind, sorted by name
+func Kinds() []*Kind {
+	list := make([]*Kind, 0, len(kinds))
+	for _, kind := range kinds {
+		list = append(list, kind)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
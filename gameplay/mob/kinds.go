@@ -0,0 +1,107 @@
+package mob
+
+import (
+	"math"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// Built-in mob kinds
+var (
+	// Bunny is a passive surface mob that hops about and runs from players
+	Bunny = &Kind{
+		Name:         "Bunny",
+		Width:        20,
+		Height:       18,
+		MaxHealth:    10,
+		Speed:        2.5,
+		JumpSpeed:    8,
+		SightRange:   settings.TileSize * 6,
+		Color:        [3]uint8{235, 230, 220},
+		Frames:       2,
+		FrameTicks:   8,
+		NewBehaviour: NewFlee,
+		Spawn: SpawnRule{
+			Weight:   10,
+			MinDepth: -settings.ChunkHeight / 4,
+			MaxDepth: -1,
+			MinLight: 8,
+			MaxLight: coretypes.MaxLight,
+			Biomes:   []string{"Forest", "Snow", "Jungle"},
+		},
+	}
+
+	// CaveCrawler is a hostile cave mob that chases players it can see
+	CaveCrawler = &Kind{
+		Name:         "Cave Crawler",
+		Width:        28,
+		Height:       20,
+		MaxHealth:    30,
+		Speed:        2.2,
+		JumpSpeed:    8.5,
+		Hostile:      true,
+		Damage:       8,
+		SightRange:   settings.TileSize * 10,
+		Color:        [3]uint8{90, 120, 60},
+		Frames:       2,
+		FrameTicks:   6,
+		NewBehaviour: NewChase,
+		Spawn: SpawnRule{
+			Weight:   8,
+			MinDepth: settings.CaveShallowDepth,
+			MaxDepth: math.MaxInt32,
+			MaxLight: 4,
+		},
+	}
+
+	// Bat is a hostile cave flyer that swoops at players
+	Bat = &Kind{
+		Name:         "Bat",
+		Width:        20,
+		Height:       14,
+		MaxHealth:    8,
+		Speed:        2.8,
+		Hostile:      true,
+		Damage:       4,
+		SightRange:   settings.TileSize * 8,
+		Flying:       true,
+		Color:        [3]uint8{70, 50, 80},
+		Frames:       2,
+		FrameTicks:   4,
+		NewBehaviour: NewHuntingFly,
+		Spawn: SpawnRule{
+			Weight:   5,
+			MinDepth: settings.CaveMediumDepth,
+			MaxDepth: math.MaxInt32,
+			MaxLight: 4,
+		},
+	}
+
+	// Fish is a passive mob that swims in lakes
+	Fish = &Kind{
+		Name:         "Fish",
+		Width:        22,
+		Height:       12,
+		MaxHealth:    6,
+		Speed:        1.8,
+		JumpSpeed:    4,
+		Aquatic:      true,
+		Color:        [3]uint8{240, 150, 60},
+		Frames:       2,
+		FrameTicks:   10,
+		NewBehaviour: NewSwim,
+		Spawn: SpawnRule{
+			Weight:   6,
+			MinDepth: -settings.ChunkHeight / 4,
+			MaxDepth: settings.CaveShallowDepth,
+			MaxLight: coretypes.MaxLight,
+		},
+	}
+)
+
+func init() {
+	for _, kind := range []*Kind{Bunny, CaveCrawler, Bat, Fish} {
+		Register(kind)
+	}
+}
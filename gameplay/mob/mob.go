@@ -0,0 +1,266 @@
+package mob
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/physics"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// World is the world access mobs need
+type World interface {
+	GetBlockAt(x, y int) coretypes.BlockType
+}
+
+// Mob is a non-player creature. Its AABB goes through the same physics as the
+// player's; its Behaviour only decides which way it wants to move.
+type Mob struct {
+	physics.AABB
+	Kind      *Kind
+	Health    int
+	Facing    int       // 1 facing right, -1 facing left
+	Age       int       // Ticks since the mob was created
+	Behaviour Behaviour // Decides the mob's Intent each tick
+	Intent    Intent    // Movement chosen this tick
+	World     World
+
+	Invulnerable int // Ticks left during which damage is ignored
+
+	rng         *rand.Rand
+	grid        *physics.SpatialGrid // Nearby entities, set by the simulation each tick
+	blocked     bool                 // Walked into a wall last tick
+	moved       int                  // Ticks spent moving, drives the walk animation
+	attackTimer int                  // Ticks until a hostile mob can hit again
+}
+
+// New creates a mob of the given kind with its collider's top-left corner at x, y.
+// The seed drives the mob's random choices, so the same seed gives the same mob.
+func New(kind *Kind, x, y float64, world World, seed int64) *Mob {
+	m := &Mob{
+		AABB: physics.AABB{
+			X:      x,
+			Y:      y,
+			Width:  kind.Width,
+			Height: kind.Height,
+		},
+		Kind:   kind,
+		Health: kind.MaxHealth,
+		Facing: 1,
+		World:  world,
+		rng:    rand.New(rand.NewSource(seed)),
+	}
+	if kind.NewBehaviour != nil {
+		m.Behaviour = kind.NewBehaviour()
+	}
+	return m
+}
+
+// Update lets the behaviour choose an intent, then accelerates the mob towards it.
+// Collisions and liquids are applied afterwards by the simulation.
+func (m *Mob) Update() {
+	m.Age++
+	m.blocked = m.Intent.MoveX != 0 && m.VX == 0
+	if m.Invulnerable > 0 {
+		m.Invulnerable--
+	}
+	m.attack()
+
+	m.Intent = Intent{}
+	if m.Behaviour != nil && !m.Dead() {
+		m.Intent = m.Behaviour.Think(m)
+	}
+	if m.Intent.MoveX > 0 {
+		m.Facing = 1
+	} else if m.Intent.MoveX < 0 {
+		m.Facing = -1
+	}
+	if m.Intent != (Intent{}) || m.Kind.Flying {
+		m.moved++
+	}
+
+	switch {
+	case m.Kind.Flying:
+		m.steer()
+	case m.Kind.Aquatic && m.Submerged > 0:
+		m.steer()
+		m.VY += settings.LiquidBuoyancy * m.Submerged // Swimmers hold their depth
+	default:
+		m.walk()
+	}
+}
+
+// attack hits a player the mob is touching, for hostile mobs
+func (m *Mob) attack() {
+	if m.attackTimer > 0 {
+		m.attackTimer--
+		return
+	}
+	if !m.Kind.Hostile || m.Kind.Damage <= 0 || m.Dead() {
+		return
+	}
+	target, ok := m.NearestPlayer(float64(max(m.Width, m.Height)) + settings.TileSize*2)
+	if !ok || !m.touching(target) {
+		return
+	}
+	cx, _ := m.Center()
+	tx, _ := livingCenter(target)
+	dir := sign(tx - cx)
+	if dir == 0 {
+		dir = float64(m.Facing)
+	}
+	if target.Hurt(m.Kind.Damage, dir*settings.MobKnockback, -settings.KnockbackLift) {
+		m.attackTimer = settings.MobAttackCooldown
+	}
+}
+
+// touching reports whether the mob's collider overlaps another entity's
+func (m *Mob) touching(l coretypes.Living) bool {
+	x, y := l.GetPosition()
+	w, h := l.ColliderSize()
+	return m.X < x+w && x < m.X+float64(m.Width) && m.Y < y+h && y < m.Y+float64(m.Height)
+}
+
+// walk moves a ground mob: horizontal acceleration, gravity and jumps
+func (m *Mob) walk() {
+	moving := m.Intent.MoveX != 0
+	m.ApplyHorizontalMovement(m.Intent.MoveX*m.Kind.Speed, settings.MobGroundFriction, settings.MobAirResistance, moving)
+	m.ApplyVerticalMovement(settings.PlayerGravity, settings.PlayerMaxFallSpeed)
+	if m.Intent.Jump && m.Kind.JumpSpeed > 0 {
+		if m.Submerged > 0 {
+			m.VY = -m.Kind.JumpSpeed / 2 // Paddle upwards
+		} else {
+			m.Jump(-m.Kind.JumpSpeed)
+		}
+	}
+}
+
+// steer moves a flying or swimming mob freely in both directions
+func (m *Mob) steer() {
+	m.VX = m.VX*(1-settings.MobSteering) + m.Intent.MoveX*m.Kind.Speed*settings.MobSteering
+	m.VY = m.VY*(1-settings.MobSteering) + m.Intent.MoveY*m.Kind.Speed*settings.MobSteering
+}
+
+// Rand returns the mob's random source, for behaviours
+func (m *Mob) Rand() *rand.Rand {
+	return m.rng
+}
+
+// Blocked reports whether the mob walked into a wall last tick
+func (m *Mob) Blocked() bool {
+	return m.blocked
+}
+
+// Center returns the middle of the mob's collider
+func (m *Mob) Center() (float64, float64) {
+	return m.X + float64(m.Width)/2, m.Y + float64(m.Height)/2
+}
+
+// SetSpatialGrid sets the grid the mob looks for players in
+func (m *Mob) SetSpatialGrid(grid *physics.SpatialGrid) {
+	m.grid = grid
+}
+
+// NearestPlayer returns the closest living player within radius pixels, using the
+// simulation's spatial grid. Mobs without a grid see nobody.
+func (m *Mob) NearestPlayer(radius float64) (coretypes.Living, bool) {
+	if m.grid == nil {
+		return nil, false
+	}
+	cx, cy := m.Center()
+	var nearest coretypes.Living
+	best := math.Inf(1)
+	for _, e := range m.grid.GetEntitiesInRadius(cx, cy, radius) {
+		living, ok := e.(coretypes.Living)
+		if !ok || living.EntityKind() != coretypes.KindPlayer || living.GetHealth() <= 0 {
+			continue
+		}
+		px, py := livingCenter(living)
+		if d := (px-cx)*(px-cx) + (py-cy)*(py-cy); d < best {
+			nearest, best = living, d
+		}
+	}
+	return nearest, nearest != nil
+}
+
+// InLiquid reports whether the block at the mob's centre is a liquid
+func (m *Mob) InLiquid() bool {
+	cx, cy := m.Center()
+	return m.blockAt(cx, cy).IsLiquid()
+}
+
+// blockAt returns the block at a world pixel position
+func (m *Mob) blockAt(x, y float64) coretypes.BlockType {
+	if m.World == nil {
+		return coretypes.Air
+	}
+	tileSize := float64(settings.TileSize)
+	return m.World.GetBlockAt(int(math.Floor(x/tileSize)), int(math.Floor(y/tileSize)))
+}
+
+// Dead reports whether the mob has no health left; the simulation removes dead mobs
+func (m *Mob) Dead() bool {
+	return m.Health <= 0
+}
+
+// EntityKind returns the mob's kind name
+func (m *Mob) EntityKind() string {
+	return m.Kind.Name
+}
+
+// ColliderSize returns the mob's collider size in pixels
+func (m *Mob) ColliderSize() (float64, float64) {
+	return float64(m.Width), float64(m.Height)
+}
+
+// GetHealth returns the mob's current health
+func (m *Mob) GetHealth() int {
+	return m.Health
+}
+
+// TakeDamage reduces the mob's health, down to zero
+func (m *Mob) TakeDamage(amount int) {
+	m.Health = max(m.Health-amount, 0)
+}
+
+// Hurt deals damage and knockback unless the mob was hit moments ago
+func (m *Mob) Hurt(amount int, knockbackX, knockbackY float64) bool {
+	if m.Invulnerable > 0 || m.Dead() {
+		return false
+	}
+	m.TakeDamage(amount)
+	m.Knockback(knockbackX, knockbackY)
+	m.Invulnerable = settings.MobInvulnerableTicks
+	return true
+}
+
+// Hurting reports whether the mob was hit moments ago, for drawing
+func (m *Mob) Hurting() bool {
+	return m.Invulnerable > 0
+}
+
+// Sprite returns the animation frame to draw. Frames only advance while the mob
+// moves, or all the time for flying mobs.
+func (m *Mob) Sprite() coretypes.Sprite {
+	frame := 0
+	if frames := m.Kind.Frames; frames > 1 {
+		frame = m.moved / max(m.Kind.FrameTicks, 1) % frames
+	}
+	return coretypes.Sprite{
+		Name:   m.Kind.Name,
+		Color:  m.Kind.Color,
+		Width:  m.Width,
+		Height: m.Height,
+		Frames: max(m.Kind.Frames, 1),
+		Frame:  frame,
+		FlipX:  m.Facing < 0,
+	}
+}
+
+// livingCenter returns the middle of a living entity's collider
+func livingCenter(l coretypes.Living) (float64, float64) {
+	x, y := l.GetPosition()
+	w, h := l.ColliderSize()
+	return x + w/2, y + h/2
+}
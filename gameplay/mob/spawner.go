@@ -0,0 +1,273 @@
+package mob
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sync"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/generation"
+	"github.com/KdntNinja/webcraft/lighting"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// SpawnWorld is the world access the spawner needs
+type SpawnWorld interface {
+	World
+	GetEntities() []coretypes.Entity
+	AddEntity(e coretypes.Entity)
+	RemoveEntity(e coretypes.Entity) bool
+}
+
+// chunkEvent is a chunk load or unload waiting for Update
+type chunkEvent struct {
+	coord  coretypes.ChunkCoord
+	loaded bool
+}
+
+// Spawner fills chunks with mobs as they load and despawns mobs whose chunk unloads.
+// It is a coretypes.ChunkListener; chunk managers may call it from any goroutine, so
+// it only queues what happened and Update does the work on the simulation's.
+// Spawns depend only on the seed, the chunk and the world around it, so the same
+// seed and inputs give the same mobs.
+type Spawner struct {
+	World   SpawnWorld
+	Light   *lighting.Engine
+	Surface func(x int) int    // Surface height of a column, for depth bands
+	Biome   func(x int) string // Biome of a column; nil skips biome checks
+	Seed    int64
+
+	mutex   sync.Mutex
+	events  []chunkEvent
+	waiting []coretypes.ChunkCoord        // Loaded chunks waiting to be lit before spawning
+	done    map[coretypes.ChunkCoord]bool // Chunks that have spawned their mobs since they loaded
+}
+
+// NewSpawner creates a spawner for a world. Register it with the chunk manager's
+// SetChunkListener and call Update once per tick, after lighting has synced.
+func NewSpawner(world SpawnWorld, light *lighting.Engine, surface func(x int) int, seed int64) *Spawner {
+	return &Spawner{
+		World:   world,
+		Light:   light,
+		Surface: surface,
+		Seed:    seed,
+		done:    make(map[coretypes.ChunkCoord]bool),
+	}
+}
+
+// ChunkLoaded queues a newly loaded chunk for spawning
+func (s *Spawner) ChunkLoaded(coord coretypes.ChunkCoord, chunk *coretypes.Chunk) {
+	s.mutex.Lock()
+	s.events = append(s.events, chunkEvent{coord, true})
+	s.mutex.Unlock()
+}
+
+// ChunkUnloaded queues an unloaded chunk for despawning
+func (s *Spawner) ChunkUnloaded(coord coretypes.ChunkCoord) {
+	s.mutex.Lock()
+	s.events = append(s.events, chunkEvent{coord, false})
+	s.mutex.Unlock()
+}
+
+// Update handles the chunk loads and unloads since the last call, in the order they
+// happened. Loaded chunks spawn their mobs once they are lit.
+func (s *Spawner) Update() {
+	s.mutex.Lock()
+	events := s.events
+	s.events = nil
+	s.mutex.Unlock()
+
+	for _, event := range events {
+		waiting := slices.Contains(s.waiting, event.coord)
+		switch {
+		case event.loaded && !waiting && !s.done[event.coord]:
+			s.waiting = append(s.waiting, event.coord)
+		case !event.loaded:
+			if waiting {
+				s.waiting = slices.DeleteFunc(s.waiting, func(c coretypes.ChunkCoord) bool { return c == event.coord })
+			}
+			delete(s.done, event.coord)
+			s.despawn(event.coord)
+		}
+	}
+	s.despawnStrays()
+
+	stillWaiting := s.waiting[:0]
+	for _, coord := range s.waiting {
+		if !s.Light.Lit(coord.X*settings.ChunkWidth, coord.Y*settings.ChunkHeight) {
+			stillWaiting = append(stillWaiting, coord)
+			continue
+		}
+		s.done[coord] = true
+		s.populate(coord)
+	}
+	s.waiting = stillWaiting
+}
+
+// populate spawns mobs in a chunk, trying a random column for a random kind on each
+// attempt, until the chunk or global cap is reached
+func (s *Spawner) populate(coord coretypes.ChunkCoord) {
+	mobs := s.mobs()
+	inChunk := 0
+	for _, m := range mobs {
+		if coretypes.ChunkCoordOfPixel(m.Center()) == coord {
+			inChunk++
+		}
+	}
+
+	// Every chunk draws from its own stream, so spawns don't depend on load order
+	rng := rand.New(rand.NewSource(int64(generation.Hash(s.Seed, coord.X, coord.Y, generation.FeatureMobSpawns))))
+	kinds := Kinds()
+	players := s.players()
+	tileSize := float64(settings.TileSize)
+	spawned := 0
+	for attempt := 0; attempt < settings.MobSpawnAttempts; attempt++ {
+		if len(mobs)+spawned >= settings.MobCap || inChunk+spawned >= settings.MobChunkCap {
+			break
+		}
+		kind := pickKind(kinds, rng)
+		if kind == nil {
+			return // Nothing spawns naturally
+		}
+		x := coord.X*settings.ChunkWidth + rng.Intn(settings.ChunkWidth)
+		var cells []int
+		for y := coord.Y * settings.ChunkHeight; y < (coord.Y+1)*settings.ChunkHeight; y++ {
+			if s.fits(kind, x, y) {
+				cells = append(cells, y)
+			}
+		}
+		if len(cells) == 0 {
+			continue
+		}
+		y := cells[rng.Intn(len(cells))]
+		seed := rng.Int63()
+
+		cx, cy := (float64(x)+0.5)*tileSize, (float64(y)+0.5)*tileSize
+		if kind.Hostile && nearAny(cx, cy, players, settings.MobSpawnMinDistance*tileSize) {
+			continue
+		}
+		// Stand on the bottom of the cell, centred in it
+		px := float64(x)*tileSize + (tileSize-float64(kind.Width))/2
+		py := float64(y+1)*tileSize - float64(kind.Height)
+		s.World.AddEntity(New(kind, px, py, s.World, seed))
+		spawned++
+	}
+
+	if spawned > 0 {
+		fmt.Printf("SPAWNER: Spawned %d mobs in chunk (%d, %d)\n", spawned, coord.X, coord.Y)
+	}
+}
+
+// fits reports whether a mob of the given kind may spawn with its feet in a cell
+func (s *Spawner) fits(kind *Kind, x, y int) bool {
+	rule := kind.Spawn
+	if depth := y - s.Surface(x); depth < rule.MinDepth || depth > rule.MaxDepth {
+		return false
+	}
+	if !s.Light.Lit(x, y) {
+		return false
+	}
+	if light := s.Light.Level(x, y); light < rule.MinLight || light > rule.MaxLight {
+		return false
+	}
+	if len(rule.Biomes) > 0 && s.Biome != nil && !slices.Contains(rule.Biomes, s.Biome(x)) {
+		return false
+	}
+
+	block := s.World.GetBlockAt(x, y)
+	switch {
+	case kind.Aquatic:
+		if block != coretypes.Water {
+			return false
+		}
+	case block.IsSolid() || block.IsLiquid():
+		return false
+	case !kind.Flying && !s.World.GetBlockAt(x, y+1).IsSolid():
+		return false
+	}
+
+	// Room for the rest of the collider above
+	for row := 1; row*settings.TileSize < kind.Height; row++ {
+		if s.World.GetBlockAt(x, y-row).IsSolid() {
+			return false
+		}
+	}
+	return true
+}
+
+// despawn removes every mob whose centre is in a chunk
+func (s *Spawner) despawn(coord coretypes.ChunkCoord) {
+	removed := 0
+	for _, m := range s.mobs() {
+		if coretypes.ChunkCoordOfPixel(m.Center()) == coord && s.World.RemoveEntity(m) {
+			removed++
+		}
+	}
+	if removed > 0 {
+		fmt.Printf("SPAWNER: Despawned %d mobs in unloaded chunk (%d, %d)\n", removed, coord.X, coord.Y)
+	}
+}
+
+// despawnStrays removes mobs that wandered out of the loaded (lit) chunks
+func (s *Spawner) despawnStrays() {
+	tileSize := float64(settings.TileSize)
+	for _, m := range s.mobs() {
+		cx, cy := m.Center()
+		if !s.Light.Lit(int(math.Floor(cx/tileSize)), int(math.Floor(cy/tileSize))) {
+			s.World.RemoveEntity(m)
+		}
+	}
+}
+
+// mobs returns the mobs in the world
+func (s *Spawner) mobs() []*Mob {
+	var mobs []*Mob
+	for _, e := range s.World.GetEntities() {
+		if m, ok := e.(*Mob); ok {
+			mobs = append(mobs, m)
+		}
+	}
+	return mobs
+}
+
+// players returns the centres of the players in the world
+func (s *Spawner) players() [][2]float64 {
+	var players [][2]float64
+	for _, e := range s.World.GetEntities() {
+		if l, ok := e.(coretypes.Living); ok && l.EntityKind() == coretypes.KindPlayer {
+			x, y := livingCenter(l)
+			players = append(players, [2]float64{x, y})
+		}
+	}
+	return players
+}
+
+// pickKind picks a kind at random, weighted by its spawn weight
+func pickKind(kinds []*Kind, rng *rand.Rand) *Kind {
+	total := 0
+	for _, kind := range kinds {
+		total += max(kind.Spawn.Weight, 0)
+	}
+	if total == 0 {
+		return nil
+	}
+	n := rng.Intn(total)
+	for _, kind := range kinds {
+		if n -= max(kind.Spawn.Weight, 0); n < 0 {
+			return kind
+		}
+	}
+	return nil
+}
+
+// nearAny reports whether a point is within radius of any of the given points
+func nearAny(x, y float64, points [][2]float64, radius float64) bool {
+	for _, p := range points {
+		if math.Hypot(p[0]-x, p[1]-y) < radius {
+			return true
+		}
+	}
+	return false
+}
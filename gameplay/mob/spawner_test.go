@@ -0,0 +1,184 @@
+package mob
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/gameplay"
+	"github.com/KdntNinja/webcraft/lighting"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+const (
+	testSurface = 40 // First solid row of the test world
+	testTunnel  = 96 // Top row of a three-block-high tunnel running under every chunk
+)
+
+// spawnWorld is a flat world of loaded chunks with a dark tunnel underground
+type spawnWorld struct {
+	chunks   map[coretypes.ChunkCoord]*coretypes.Chunk
+	entities []coretypes.Entity
+}
+
+func newSpawnWorld() *spawnWorld {
+	return &spawnWorld{chunks: make(map[coretypes.ChunkCoord]*coretypes.Chunk)}
+}
+
+func (w *spawnWorld) load(coord coretypes.ChunkCoord) *coretypes.Chunk {
+	chunk := &coretypes.Chunk{Blocks: make([][]coretypes.BlockType, settings.ChunkHeight)}
+	for y := range chunk.Blocks {
+		chunk.Blocks[y] = make([]coretypes.BlockType, settings.ChunkWidth)
+		if y >= testSurface && (y < testTunnel || y > testTunnel+2) {
+			for x := range chunk.Blocks[y] {
+				chunk.Blocks[y][x] = coretypes.Stone
+			}
+		}
+	}
+	w.chunks[coord] = chunk
+	return chunk
+}
+
+func (w *spawnWorld) LoadedChunks() map[coretypes.ChunkCoord]*coretypes.Chunk {
+	return w.chunks
+}
+
+func (w *spawnWorld) GetBlockAt(x, y int) coretypes.BlockType {
+	coord := coretypes.ChunkCoord{
+		X: int(math.Floor(float64(x) / settings.ChunkWidth)),
+		Y: int(math.Floor(float64(y) / settings.ChunkHeight)),
+	}
+	chunk := w.chunks[coord]
+	if chunk == nil {
+		return coretypes.Air
+	}
+	return chunk.Blocks[y-coord.Y*settings.ChunkHeight][x-coord.X*settings.ChunkWidth]
+}
+
+func (w *spawnWorld) GetEntities() []coretypes.Entity { return w.entities }
+func (w *spawnWorld) AddEntity(e coretypes.Entity)    { w.entities = append(w.entities, e) }
+func (w *spawnWorld) RemoveEntity(e coretypes.Entity) bool {
+	i := slices.Index(w.entities, e)
+	if i < 0 {
+		return false
+	}
+	w.entities = slices.Delete(w.entities, i, i+1)
+	return true
+}
+
+// spawnRun loads chunks into a fresh world in the given order, one tick each, and
+// returns the spawner and world
+func spawnRun(seed int64, order []int) (*Spawner, *spawnWorld) {
+	w := newSpawnWorld()
+	light := lighting.New(w, func(int) int { return testSurface })
+	s := NewSpawner(w, light, func(int) int { return testSurface }, seed)
+	for _, x := range order {
+		coord := coretypes.ChunkCoord{X: x}
+		s.ChunkLoaded(coord, w.load(coord))
+		light.Sync()
+		s.Update()
+	}
+	return s, w
+}
+
+// mobList describes the mobs in a world, sorted, for comparing runs
+func mobList(w *spawnWorld) []string {
+	var list []string
+	for _, e := range w.entities {
+		if m, ok := e.(*Mob); ok {
+			list = append(list, fmt.Sprintf("%s@%.1f,%.1f", m.Kind.Name, m.X, m.Y))
+		}
+	}
+	slices.Sort(list)
+	return list
+}
+
+func TestSpawnsDontDependOnLoadOrder(t *testing.T) {
+	_, forward := spawnRun(3, []int{-1, 0, 1, 2})
+	_, backward := spawnRun(3, []int{2, 1, 0, -1})
+	a, b := mobList(forward), mobList(backward)
+	if len(a) == 0 {
+		t.Fatal("nothing spawned")
+	}
+	if !slices.Equal(a, b) {
+		t.Fatalf("load order changed the spawns:\n%v\n%v", a, b)
+	}
+
+	_, other := spawnRun(4, []int{-1, 0, 1, 2})
+	if slices.Equal(a, mobList(other)) {
+		t.Fatal("a different seed spawned the same mobs")
+	}
+}
+
+func TestSpawnsFollowTheirRules(t *testing.T) {
+	_, w := spawnRun(3, []int{-1, 0, 1, 2})
+	perChunk := map[coretypes.ChunkCoord]int{}
+	for _, e := range w.entities {
+		m := e.(*Mob)
+		perChunk[coretypes.ChunkCoordOfPixel(m.Center())]++
+		block := int(math.Floor((m.Y + float64(m.Height) - 1) / settings.TileSize))
+		switch m.Kind {
+		case Bunny:
+			if block >= testSurface {
+				t.Errorf("bunny spawned underground at row %d", block)
+			}
+		case CaveCrawler, Bat:
+			if block < testTunnel || block > testTunnel+2 {
+				t.Errorf("%s spawned outside the tunnel at row %d", m.Kind.Name, block)
+			}
+		}
+	}
+	for coord, n := range perChunk {
+		if n > settings.MobChunkCap {
+			t.Errorf("chunk %v has %d mobs, over the cap of %d", coord, n, settings.MobChunkCap)
+		}
+	}
+}
+
+func TestHostileMobsKeepAwayFromPlayers(t *testing.T) {
+	w := newSpawnWorld()
+	light := lighting.New(w, func(int) int { return testSurface })
+	s := NewSpawner(w, light, func(int) int { return testSurface }, 3)
+	player := gameplay.NewPlayer(float64(settings.ChunkWidth*settings.TileSize), float64(testTunnel*settings.TileSize), w)
+	w.AddEntity(player)
+	for x := -2; x <= 2; x++ {
+		coord := coretypes.ChunkCoord{X: x}
+		s.ChunkLoaded(coord, w.load(coord))
+	}
+	light.Sync()
+	s.Update()
+
+	px, py := livingCenter(player)
+	for _, e := range w.entities {
+		if m, ok := e.(*Mob); ok && m.Kind.Hostile {
+			cx, cy := m.Center()
+			if math.Hypot(cx-px, cy-py) < settings.MobSpawnMinDistance*settings.TileSize-settings.TileSize {
+				t.Errorf("%s spawned %.0f pixels from the player", m.Kind.Name, math.Hypot(cx-px, cy-py))
+			}
+		}
+	}
+}
+
+func TestUnloadedChunksRespawnTheSameMobs(t *testing.T) {
+	s, w := spawnRun(3, []int{0, 1})
+	before := mobList(w)
+
+	s.ChunkUnloaded(coretypes.ChunkCoord{X: 1})
+	delete(w.chunks, coretypes.ChunkCoord{X: 1})
+	s.Light.Sync()
+	s.Update()
+	for _, e := range w.entities {
+		if m := e.(*Mob); coretypes.ChunkCoordOfPixel(m.Center()).X == 1 {
+			t.Fatalf("%s left behind in the unloaded chunk", m.Kind.Name)
+		}
+	}
+
+	s.ChunkLoaded(coretypes.ChunkCoord{X: 1}, w.load(coretypes.ChunkCoord{X: 1}))
+	s.Light.Sync()
+	s.Update()
+	if after := mobList(w); !slices.Equal(before, after) {
+		t.Fatalf("reloading the chunk changed its mobs:\n%v\n%v", before, after)
+	}
+}
@@ -129,6 +129,11 @@ func (p *Player) HandleJump() {
 	if !p.InputState.JumpPressed {
 		p.InputState.JumpHoldTime = 0
 	}
+
+	// Holding jump while in a liquid swims upwards.
+	if p.Submerged > 0 && p.InputState.JumpPressed && p.VY > -settings.SwimSpeed {
+		p.VY = -settings.SwimSpeed
+	}
 }
 
 // ApplyGravity handles the vertical physics for the player, including gravity
@@ -2,6 +2,7 @@ package gameplay
 
 import (
 	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/inventory"
 	"github.com/KdntNinja/webcraft/physics"
 	"github.com/KdntNinja/webcraft/settings"
 )
@@ -9,18 +10,25 @@ import (
 type Player struct {
 	physics.AABB
 	physics.InputState
-	wasOnGround           bool                         // Previous frame ground state
-	SelectedBlock         coretypes.BlockType          // Currently selected block type for placing
-	InteractionRange      float64                      // Maximum range for block interaction
-	LastInteractionTime   int                          // Frame counter for interaction cooldown
-	InteractionCooldown   int                          // Cooldown frames between interactions (faster than inpututil)
-	World                 WorldBlockGetter             // Use concrete interface for better performance
-	Health                int                          // Player health
-	MaxHealth             int                          // Maximum health
-	Inventory             [coretypes.NumBlockTypes]int // Use array for fast inventory access
-	Hotbar                []coretypes.BlockType        // Dynamic hotbar (up to 9 blocks)
-	IsSprinting           bool                         // Sprinting state
-	lastEmptiedHotbarSlot int                          // -1 if none
+	wasOnGround           bool                 // Previous frame ground state
+	SelectedSlot          int                  // Hotbar slot in hand
+	InteractionRange      float64              // Maximum range for block interaction
+	LastInteractionTime   int                  // Frame counter for interaction cooldown
+	InteractionCooldown   int                  // Cooldown frames between interactions (faster than inpututil)
+	World                 WorldBlockGetter     // Use concrete interface for better performance
+	Health                int                  // Player health
+	MaxHealth             int                  // Maximum health
+	Inventory             *inventory.Container // Hotbar slots first, then the main inventory
+	Cursor                coretypes.ItemStack  // Stack picked up on the inventory screen
+	IsSprinting           bool                 // Sprinting state
+	Input                 coretypes.InputFrame // Input for the current simulation tick
+	Invulnerable          int                  // Ticks left during which damage is ignored
+	AttackTimer           int                  // Ticks until the player can swing again
+	Hazards               physics.HazardState  // Breath and burning
+	HazardConfig          physics.HazardConfig // How much falls, drowning and burning hurt
+	Mining                MiningState          // Block being broken
+	lastEmptiedHotbarSlot int                  // -1 if none
+	hurtTimer             int                  // Ticks left of the flash after being hurt
 	// ...existing code...
 }
 
@@ -40,17 +48,17 @@ func NewPlayer(x, y float64, world WorldBlockGetter) *Player {
 			Width:  settings.PlayerColliderWidth,
 			Height: settings.PlayerColliderHeight,
 		},
-		SelectedBlock:         coretypes.Grass,                // Default to grass blocks (block 1)
 		InteractionRange:      float64(settings.TileSize * 4), // 4 block radius
 		InteractionCooldown:   0,                              // 3 frames cooldown (about 0.05 seconds at 60fps)
 		World:                 world,
 		Health:                100,
 		MaxHealth:             100,
-		Hotbar:                make([]coretypes.BlockType, 9), // Always 9 slots, filled with coretypes.Air
+		Inventory:             inventory.NewContainer(settings.InventorySize),
 		lastEmptiedHotbarSlot: -1,
 		IsSprinting:           false,
+		Input:                 coretypes.EmptyInputFrame(),
+		HazardConfig:          physics.DefaultHazards(),
 	}
-	// Inventory array is zeroed by default
 	return p
 }
 
@@ -65,9 +73,10 @@ func (p *Player) Update() {
 	if p.LastInteractionTime < 1<<30 {
 		p.LastInteractionTime++
 	}
+	p.tickCombatTimers()
 
-	// Process input and update movement (without camera-dependent interactions)
-	isMoving, targetVX, jumpKeyPressed, _ := p.HandleInput(0, 0)
+	// Process input and update movement (block interactions are handled by the simulation)
+	isMoving, targetVX, jumpKeyPressed := p.HandleInput()
 
 	// Only update input state if jump key state changed
 	if jumpKeyPressed != p.InputState.JumpPressed {
@@ -95,77 +104,93 @@ func (p *Player) Heal(amount int) {
 	}
 }
 
-// AddToInventory adds a block to the player's inventory (array version, fast)
-func (p *Player) AddToInventory(blockType coretypes.BlockType, count int) {
-	if int(blockType) >= 0 && int(blockType) < len(p.Inventory) {
-		p.Inventory[blockType] += count
-		// Add to hotbar if not already present and not air
-		if blockType != coretypes.Air {
-			found := false
-			for _, b := range p.Hotbar {
-				if b == blockType {
-					found = true
-					break
-				}
-			}
-			if !found {
-				slot := -1
-				// Prefer to fill the most recently emptied slot
-				if p.lastEmptiedHotbarSlot >= 0 && p.lastEmptiedHotbarSlot < len(p.Hotbar) && p.Hotbar[p.lastEmptiedHotbarSlot] == coretypes.Air {
-					slot = p.lastEmptiedHotbarSlot
-				} else {
-					// Otherwise, find first empty slot
-					for i, b := range p.Hotbar {
-						if b == coretypes.Air {
-							slot = i
-							break
-						}
-					}
-				}
-				// If no empty slot, replace first slot
-				if slot == -1 && len(p.Hotbar) > 0 {
-					slot = 0
-				}
-				if slot != -1 {
-					p.Hotbar[slot] = blockType
-					p.lastEmptiedHotbarSlot = -1 // Reset after use
-				}
-			}
-		}
+// InventoryCount returns how many of an item the player holds
+func (p *Player) InventoryCount(item coretypes.ItemID) int {
+	return p.Inventory.Count(item)
+}
+
+// AddToInventory adds items to the player's inventory and returns how many didn't
+// fit. Items top up stacks the player already has; a new item goes into the most
+// recently emptied hotbar slot, then the first free hotbar slot, then the main
+// inventory.
+func (p *Player) AddToInventory(item coretypes.ItemID, count int) int {
+	if item == coretypes.NoItem || count <= 0 {
+		return 0
+	}
+	return p.AddStack(coretypes.NewStack(item, count)).Count
+}
+
+// AddStack adds a stack, wear and metadata included, the same way as AddToInventory
+// and returns what didn't fit
+func (p *Player) AddStack(stack coretypes.ItemStack) coretypes.ItemStack {
+	if stack.IsEmpty() {
+		return coretypes.ItemStack{}
+	}
+	left := p.Inventory.Merge(stack)
+	if left.IsEmpty() {
+		return coretypes.ItemStack{}
 	}
+
+	preferred := make([]int, 0, settings.HotbarSize+1)
+	if p.lastEmptiedHotbarSlot >= 0 {
+		preferred = append(preferred, p.lastEmptiedHotbarSlot)
+	}
+	for i := 0; i < settings.HotbarSize; i++ {
+		preferred = append(preferred, i)
+	}
+	before := left.Count
+	left = p.Inventory.Fill(left, preferred)
+	if left.Count < before {
+		p.lastEmptiedHotbarSlot = -1 // Reset after use
+	}
+	return left
 }
 
-// RemoveFromInventory removes a block from the player's inventory (array version, fast)
-func (p *Player) RemoveFromInventory(blockType coretypes.BlockType, count int) bool {
-	if int(blockType) >= 0 && int(blockType) < len(p.Inventory) && p.Inventory[blockType] >= count {
-		p.Inventory[blockType] -= count
-		// If the count is now zero, set hotbar slot to coretypes.Air (do not shift others)
-		if p.Inventory[blockType] == 0 {
-			for i, b := range p.Hotbar {
-				if b == blockType {
-					p.Hotbar[i] = coretypes.Air
-					p.lastEmptiedHotbarSlot = i
-					break
-				}
-			}
+// RemoveFromInventory removes items from the player's inventory, all or nothing.
+// Hotbar slots it empties are remembered so the next new item goes back there.
+func (p *Player) RemoveFromInventory(item coretypes.ItemID, count int) bool {
+	if p.InventoryCount(item) < count {
+		return false
+	}
+	// Take from the slot in hand first
+	if p.SelectedItem().Item == item {
+		count -= p.takeFromSlot(p.SelectedSlot, count)
+	}
+	for i := 0; i < p.Inventory.Len() && count > 0; i++ {
+		if p.Inventory.Slot(i).Item == item {
+			count -= p.takeFromSlot(i, count)
 		}
-		return true
 	}
-	return false
+	return true
 }
 
-// HandleBlockInteractions processes block interactions with camera coordinates
-func (p *Player) HandleBlockInteractions(cameraX, cameraY float64) *BlockInteraction {
-	// Process input for block interactions
-	_, _, _, blockInteraction := p.HandleInput(cameraX, cameraY)
+// takeFromSlot takes up to count items from a slot and returns how many it took
+func (p *Player) takeFromSlot(slot, count int) int {
+	taken := p.Inventory.Take(slot, count).Count
+	if taken > 0 && slot < settings.HotbarSize && p.Inventory.Slot(slot).IsEmpty() {
+		p.lastEmptiedHotbarSlot = slot
+	}
+	return taken
+}
+
+// Hotbar returns the hotbar slots, the first slots of the inventory
+func (p *Player) Hotbar() []coretypes.ItemStack {
+	return p.Inventory.Slots[:min(settings.HotbarSize, p.Inventory.Len())]
+}
 
-	// Return the interaction directly since range checking is done in HandleInput
-	return blockInteraction
+// SelectedItem returns the stack in the selected hotbar slot
+func (p *Player) SelectedItem() coretypes.ItemStack {
+	return p.Inventory.Slot(p.SelectedSlot)
 }
 
-// SetSelectedBlock changes the currently selected block type
-func (p *Player) SetSelectedBlock(blockType coretypes.BlockType) {
-	p.SelectedBlock = blockType
+// SelectedBlock returns the block the selected item places, or Air if it isn't a block
+func (p *Player) SelectedBlock() coretypes.BlockType {
+	return p.SelectedItem().Item.Block()
+}
+
+// SetInput sets the input frame the player acts on during the next Update
+func (p *Player) SetInput(frame coretypes.InputFrame) {
+	p.Input = frame
 }
 
 // CanInteract returns true if the player can interact (based on cooldown)
@@ -203,11 +228,6 @@ func (p *Player) GetColliderHeight() float64 {
 	return float64(p.AABB.Height)
 }
 
-// GetSelectedBlock returns the currently selected block index
-func (p *Player) GetSelectedBlock() int {
-	return int(p.SelectedBlock)
-}
-
 // ResetInteractionCooldown resets the cooldown timer after an interaction
 func (p *Player) ResetInteractionCooldown() {
 	p.LastInteractionTime = 0
@@ -224,3 +244,18 @@ func (p *Player) GetPosition() (float64, float64) {
 func (p *Player) SetPosition(x, y float64) {
 	p.AABB.SetPosition(x, y)
 }
+
+// EntityKind identifies the player to mobs and the renderer
+func (p *Player) EntityKind() string {
+	return coretypes.KindPlayer
+}
+
+// ColliderSize returns the player's collider size in pixels
+func (p *Player) ColliderSize() (float64, float64) {
+	return float64(p.AABB.Width), float64(p.AABB.Height)
+}
+
+// GetHealth returns the player's current health
+func (p *Player) GetHealth() int {
+	return p.Health
+}
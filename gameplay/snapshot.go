@@ -0,0 +1,131 @@
+package gameplay
+
+import (
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/inventory"
+	"github.com/KdntNinja/webcraft/physics"
+)
+
+// PlayerSnapshot is a copy of everything a simulation tick can change on a player,
+// so the player can be rewound and re-simulated
+type PlayerSnapshot struct {
+	AABB                  physics.AABB
+	InputState            physics.InputState
+	WasOnGround           bool
+	SelectedSlot          int
+	InteractionRange      float64
+	LastInteractionTime   int
+	InteractionCooldown   int
+	Health                int
+	MaxHealth             int
+	Inventory             *inventory.Container
+	Cursor                coretypes.ItemStack
+	IsSprinting           bool
+	Input                 coretypes.InputFrame
+	LastEmptiedHotbarSlot int
+	Invulnerable          int
+	AttackTimer           int
+	HurtTimer             int
+	Hazards               physics.HazardState
+	Mining                MiningState
+}
+
+// Snapshot captures the player's current state. The world reference is not part of it.
+func (p *Player) Snapshot() PlayerSnapshot {
+	return PlayerSnapshot{
+		AABB:                  p.AABB,
+		InputState:            p.InputState,
+		WasOnGround:           p.wasOnGround,
+		SelectedSlot:          p.SelectedSlot,
+		InteractionRange:      p.InteractionRange,
+		LastInteractionTime:   p.LastInteractionTime,
+		InteractionCooldown:   p.InteractionCooldown,
+		Health:                p.Health,
+		MaxHealth:             p.MaxHealth,
+		Inventory:             p.Inventory.Clone(),
+		Cursor:                p.Cursor.Clone(),
+		IsSprinting:           p.IsSprinting,
+		Input:                 p.Input,
+		LastEmptiedHotbarSlot: p.lastEmptiedHotbarSlot,
+		Invulnerable:          p.Invulnerable,
+		AttackTimer:           p.AttackTimer,
+		HurtTimer:             p.hurtTimer,
+		Hazards:               p.Hazards,
+		Mining:                p.Mining,
+	}
+}
+
+// Restore puts the player back into a captured state
+func (p *Player) Restore(s PlayerSnapshot) {
+	p.AABB = s.AABB
+	p.InputState = s.InputState
+	p.wasOnGround = s.WasOnGround
+	p.SelectedSlot = s.SelectedSlot
+	p.InteractionRange = s.InteractionRange
+	p.LastInteractionTime = s.LastInteractionTime
+	p.InteractionCooldown = s.InteractionCooldown
+	p.Health = s.Health
+	p.MaxHealth = s.MaxHealth
+	p.Inventory = s.Inventory.Clone()
+	p.Cursor = s.Cursor.Clone()
+	p.IsSprinting = s.IsSprinting
+	p.Input = s.Input
+	p.lastEmptiedHotbarSlot = s.LastEmptiedHotbarSlot
+	p.Invulnerable = s.Invulnerable
+	p.AttackTimer = s.AttackTimer
+	p.hurtTimer = s.HurtTimer
+	p.Hazards = s.Hazards
+	p.Mining = s.Mining
+}
+
+// Movement is the part of a player's state that moving changes: position,
+// velocity, jump and sprint state and the input driving them
+type Movement struct {
+	AABB        physics.AABB
+	InputState  physics.InputState
+	WasOnGround bool
+	IsSprinting bool
+	Input       coretypes.InputFrame
+}
+
+// Movement returns the movement part of a captured state
+func (s PlayerSnapshot) Movement() Movement {
+	return Movement{
+		AABB:        s.AABB,
+		InputState:  s.InputState,
+		WasOnGround: s.WasOnGround,
+		IsSprinting: s.IsSprinting,
+		Input:       s.Input,
+	}
+}
+
+// Movement returns the player's current movement state
+func (p *Player) Movement() Movement {
+	return Movement{
+		AABB:        p.AABB,
+		InputState:  p.InputState,
+		WasOnGround: p.wasOnGround,
+		IsSprinting: p.IsSprinting,
+		Input:       p.Input,
+	}
+}
+
+// SetMovement replaces the player's movement state, leaving inventory, health,
+// timers and the rest alone
+func (p *Player) SetMovement(m Movement) {
+	p.AABB = m.AABB
+	p.InputState = m.InputState
+	p.wasOnGround = m.WasOnGround
+	p.IsSprinting = m.IsSprinting
+	p.Input = m.Input
+}
+
+// WithMovement returns the captured state with its movement part replaced
+func (s PlayerSnapshot) WithMovement(m Movement) PlayerSnapshot {
+	s.AABB = m.AABB
+	s.InputState = m.InputState
+	s.WasOnGround = m.WasOnGround
+	s.IsSprinting = m.IsSprinting
+	s.Input = m.Input
+	return s
+}
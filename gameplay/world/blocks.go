@@ -16,6 +16,7 @@ func (w *World) SetBlockAt(blockX, blockY int, blockType coretypes.BlockType) bo
 	if success {
 		w.updateCachedGridBlock(blockX, blockY, blockType)
 		// Do NOT always mark gridDirty here; updateCachedGridBlock will do so only if needed
+		w.Light.BlockChanged(blockX, blockY)
 	}
 
 	return success
@@ -24,8 +25,8 @@ func (w *World) SetBlockAt(blockX, blockY int, blockType coretypes.BlockType) bo
 // BreakBlock removes a block at the given coordinates
 func (w *World) BreakBlock(blockX, blockY int) bool {
 	currentBlock := w.GetBlockAt(blockX, blockY)
-	if currentBlock == coretypes.Air {
-		return false // Cannot break air
+	if !currentBlock.IsBreakable() {
+		return false // Cannot break air, liquids or unbreakable blocks
 	}
 
 	return w.SetBlockAt(blockX, blockY, coretypes.Air)
@@ -34,12 +35,12 @@ func (w *World) BreakBlock(blockX, blockY int) bool {
 // PlaceBlock places a block at the given coordinates
 func (w *World) PlaceBlock(blockX, blockY int, blockType coretypes.BlockType) bool {
 	currentBlock := w.GetBlockAt(blockX, blockY)
-	if currentBlock != coretypes.Air {
+	if currentBlock != coretypes.Air && !currentBlock.IsLiquid() {
 		return false // Cannot place block where one already exists
 	}
 
-	// Don't allow placing air blocks
-	if blockType == coretypes.Air {
+	// Only blocks the registry marks as placeable (never air)
+	if !blockType.IsPlaceable() {
 		return false
 	}
 
@@ -1,6 +1,7 @@
 package world
 
 import (
+	"github.com/KdntNinja/webcraft/coretypes"
 	"github.com/KdntNinja/webcraft/settings"
 )
 
@@ -14,17 +15,15 @@ func (w *World) wouldBlockCollideWithEntity(blockX, blockY int) bool {
 
 	// Check collision with all entities
 	for _, entity := range w.Entities {
+		if _, ok := entity.(interface{ DroppedStack() coretypes.ItemStack }); ok {
+			continue // Dropped items don't get in the way of building
+		}
 		entityX, entityY := entity.GetPosition()
 
-		// Get entity dimensions based on interface (assume Player interface has GetAABB method)
+		// Players and mobs report their collider size
 		var entityWidth, entityHeight float64
-		// Try to get AABB if available, else use default size
-		type localAABB struct{ Width, Height int }
-		type aabbGetter interface{ GetAABB() localAABB }
-		if ag, ok := entity.(aabbGetter); ok {
-			aabb := ag.GetAABB()
-			entityWidth = float64(aabb.Width)
-			entityHeight = float64(aabb.Height)
+		if living, ok := entity.(coretypes.Living); ok {
+			entityWidth, entityHeight = living.ColliderSize()
 		} else {
 			// Default entity size for other entity types
 			entityWidth = float64(settings.TileSize)
@@ -0,0 +1,22 @@
+package world
+
+import (
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+// AddEntity adds an entity after the existing ones; it is updated from the next tick
+func (w *World) AddEntity(e coretypes.Entity) {
+	w.Entities = append(w.Entities, e)
+}
+
+// RemoveEntity removes an entity, keeping the others in order. It reports whether
+// the entity was in the world.
+func (w *World) RemoveEntity(e coretypes.Entity) bool {
+	for i, other := range w.Entities {
+		if other == e {
+			w.Entities = append(w.Entities[:i], w.Entities[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
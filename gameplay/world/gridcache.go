@@ -86,3 +86,9 @@ func (w *World) GetCachedGrid() ([][]int, int, int) {
 	}
 	return w.cachedGrid, w.cachedGridOffsetX, w.cachedGridOffsetY
 }
+
+// MarkGridDirty forces the collision grid to be regenerated, for block changes made
+// directly through the chunk manager
+func (w *World) MarkGridDirty() {
+	w.gridDirty = true
+}
@@ -8,13 +8,18 @@ import (
 
 	"github.com/KdntNinja/webcraft/worldgen"
 
+	"github.com/KdntNinja/webcraft/clock"
 	"github.com/KdntNinja/webcraft/coretypes"
 	"github.com/KdntNinja/webcraft/gameplay"
+	"github.com/KdntNinja/webcraft/generation"
+	"github.com/KdntNinja/webcraft/lighting"
 	"github.com/KdntNinja/webcraft/progress"
+	"github.com/KdntNinja/webcraft/settings"
 )
 
-// NewWorld constructs a new World instance with dynamic chunk loading and a given spawn point
-func NewWorld(seed int64, chunkManager coretypes.ChunkManager, spawnPoint worldgen.SpawnPoint) *World {
+// NewWorld constructs a new World instance with dynamic chunk loading and a given spawn point.
+// The generator should be the one that made the chunk manager's chunks.
+func NewWorld(generator *generation.Generator, chunkManager coretypes.ChunkManager, spawnPoint worldgen.SpawnPoint) *World {
 	// Step: World Setup
 	progress.UpdateCurrentStepProgress(1, "Setting up world structure...")
 	progress.UpdateCurrentStepProgress(2, "Reset world generation")
@@ -25,7 +30,12 @@ func NewWorld(seed int64, chunkManager coretypes.ChunkManager, spawnPoint worldg
 		numUpdateWorkers: runtime.NumCPU(),                // Use all available CPUs for updates
 		updateTasks:      make(chan AsyncUpdateTask, 100), // Buffered channel for update tasks
 		ChunkManager:     chunkManager,
+		Clock:            clock.New(settings.DayLength),
+		Generator:        generator,
+		Seed:             generator.Seed(),
+		SpawnPoint:       spawnPoint,
 	}
+	w.Light = lighting.New(w, generator.GetHeightAt)
 
 	// Initialize async update system
 	w.updateCtx, w.updateCancel = context.WithCancel(context.Background())
@@ -0,0 +1,30 @@
+package world
+
+import "github.com/KdntNinja/webcraft/coretypes"
+
+// LoadedChunks returns the loaded chunks without loading or generating more
+func (w *World) LoadedChunks() map[coretypes.ChunkCoord]*coretypes.Chunk {
+	return w.ChunkManager.GetAllChunks()
+}
+
+// LiquidLevel returns the liquid fill level at the given world coordinates
+// (coretypes.MaxLiquidLevel for full liquids and every other block)
+func (w *World) LiquidLevel(blockX, blockY int) int {
+	coord, inX, inY := coretypes.LocateBlock(blockX, blockY)
+	chunk := w.ChunkManager.GetChunk(coord.X, coord.Y)
+	if chunk == nil || len(chunk.Blocks) == 0 {
+		return coretypes.MaxLiquidLevel
+	}
+	return chunk.LiquidLevel(inX, inY)
+}
+
+// SetLiquid sets a block and its liquid fill level. Like SetBlockAt it patches the
+// cached collision grid in place, so flowing liquids don't force a full rebuild.
+func (w *World) SetLiquid(blockX, blockY int, blockType coretypes.BlockType, level int) bool {
+	if !w.ChunkManager.SetLiquid(blockX, blockY, blockType, level) {
+		return false
+	}
+	w.updateCachedGridBlock(blockX, blockY, blockType)
+	w.Light.BlockChanged(blockX, blockY)
+	return true
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"sync"
 
+	"github.com/KdntNinja/webcraft/clock"
 	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/generation"
+	"github.com/KdntNinja/webcraft/lighting"
+	"github.com/KdntNinja/webcraft/worldgen"
 )
 
 // AsyncUpdateTask represents a task for async world updates
@@ -17,6 +21,11 @@ type AsyncUpdateTask struct {
 type World struct {
 	ChunkManager coretypes.ChunkManager // Dynamic chunk loading system
 	Entities     coretypes.Entities     // All entities in the world
+	Light        *lighting.Engine       // Per-block light, relit as blocks change
+	Clock        *clock.Clock           // Time of day, advanced by the simulation
+	Generator    *generation.Generator  // Terrain generator, also used for surface heights and biomes
+	Seed         int64                  // World generation seed
+	SpawnPoint   worldgen.SpawnPoint    // Where players start and respawn
 
 	// Performance optimization caches
 	cachedGrid        [][]int // Cached collision grid
@@ -1,12 +1,7 @@
 package world
 
-import (
-	"sync"
-
-	"github.com/KdntNinja/webcraft/coretypes"
-)
-
-// Update method to handle dynamic chunk loading
+// Update streams chunks around the player and updates every entity in order.
+// It runs entirely on the caller's goroutine; the sim package drives the full tick.
 func (w *World) Update() {
 	// Update chunk loading based on player position
 	if len(w.Entities) > 0 {
@@ -14,14 +9,7 @@ func (w *World) Update() {
 		w.ChunkManager.UpdatePlayerPosition(posX, posY)
 	}
 
-	// Update entities directly in parallel (more efficient than task queue for this)
-	var wg sync.WaitGroup
 	for _, e := range w.Entities {
-		wg.Add(1)
-		go func(ent coretypes.Entity) {
-			defer wg.Done()
-			ent.Update()
-		}(e)
+		e.Update()
 	}
-	wg.Wait()
 }
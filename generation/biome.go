@@ -0,0 +1,154 @@
+package generation
+
+import (
+	"math"
+	"sync"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// Biome describes how terrain is generated in one kind of area
+type Biome struct {
+	Name        string
+	Temperature float64 // Climate the biome sits at; each column gets the biome nearest its own
+	Humidity    float64
+
+	Surface    coretypes.BlockType // Top block of each column
+	Subsurface coretypes.BlockType // The few blocks under the surface
+	TreeChance float64             // Chance of a tree on each surface block
+	Trees      map[TreeType]float64
+
+	HeightScale  float64 // Multiplier on the surface height variation
+	HeightOffset int     // Blocks added to the surface Y; positive is lower ground
+	CaveWater    float64 // Noise threshold for water in deep caverns; higher is drier
+}
+
+var (
+	biomes       []*Biome
+	biomesBlocks *coretypes.BlockRegistry
+	biomesMutex  sync.Mutex
+)
+
+// forestTrees uses the default tree rarities
+var forestTrees = map[TreeType]float64{
+	NormalTree: NormalTree.GetRarity(),
+	TallTree:   TallTree.GetRarity(),
+	BushyTree:  BushyTree.GetRarity(),
+	WideTree:   WideTree.GetRarity(),
+	TwinTree:   TwinTree.GetRarity(),
+	DeadTree:   DeadTree.GetRarity(),
+	GiantTree:  GiantTree.GetRarity(),
+	FlowerTree: FlowerTree.GetRarity(),
+}
+
+// newBiomes builds the biome table against the installed block registry
+func newBiomes() []*Biome {
+	return []*Biome{
+		{
+			Name: "Forest", Temperature: 0, Humidity: 0,
+			Surface: coretypes.Grass, Subsurface: coretypes.Dirt,
+			TreeChance: settings.TreeChance, Trees: forestTrees,
+			HeightScale: 1, HeightOffset: 0, CaveWater: 0.75,
+		},
+		{
+			Name: "Desert", Temperature: 0.3, Humidity: -0.25,
+			Surface: coretypes.Sand, Subsurface: coretypes.Sandstone,
+			TreeChance: 0.02, Trees: map[TreeType]float64{PalmTree: 0.8, DeadTree: 0.2},
+			HeightScale: 0.5, HeightOffset: 4, CaveWater: 0.9,
+		},
+		{
+			Name: "Snow", Temperature: -0.3, Humidity: 0,
+			Surface: coretypes.Snow, Subsurface: coretypes.Dirt,
+			TreeChance: 0.04, Trees: map[TreeType]float64{TallTree: 0.6, NormalTree: 0.3, DeadTree: 0.1},
+			HeightScale: 1.4, HeightOffset: -8, CaveWater: 0.75,
+		},
+		{
+			Name: "Jungle", Temperature: 0.25, Humidity: 0.25,
+			Surface: coretypes.JungleGrass, Subsurface: coretypes.Mud,
+			TreeChance: 0.16, Trees: map[TreeType]float64{BushyTree: 0.4, TallTree: 0.3, GiantTree: 0.15, FlowerTree: 0.15},
+			HeightScale: 0.8, HeightOffset: 0, CaveWater: 0.62,
+		},
+		{
+			Name: "Spooky", Temperature: -0.15, Humidity: -0.3,
+			Surface: coretypes.SpookyGrass, Subsurface: coretypes.Dirt,
+			TreeChance: 0.06, Trees: map[TreeType]float64{SpookyTree: 0.7, DeadTree: 0.3},
+			HeightScale: 1.2, HeightOffset: 2, CaveWater: 0.8,
+		},
+	}
+}
+
+// Biomes returns every biome, rebuilt if the block registry has changed since
+func Biomes() []*Biome {
+	biomesMutex.Lock()
+	defer biomesMutex.Unlock()
+	if biomes == nil || biomesBlocks != coretypes.Blocks() {
+		biomes, biomesBlocks = newBiomes(), coretypes.Blocks()
+	}
+	return biomes
+}
+
+// GetClimateAt returns the temperature and humidity of a column
+func (g *Generator) GetClimateAt(worldX int) (temperature, humidity float64) {
+	x := float64(worldX) / g.config.BiomeScale
+	return g.temperatureNoise.Noise2D(x, 0), g.humidityNoise.Noise2D(x, 0)
+}
+
+// GetBiomeAt returns the biome of a column: the one whose climate is nearest the column's
+func (g *Generator) GetBiomeAt(worldX int) *Biome {
+	all := Biomes()
+	g.biomesMutex.RLock()
+	biome, exists := g.biomes[worldX]
+	g.biomesMutex.RUnlock()
+	if exists && biomeIn(biome, all) {
+		return biome
+	}
+
+	temperature, humidity := g.GetClimateAt(worldX)
+	best := math.Inf(1)
+	for _, b := range all {
+		dt, dh := temperature-b.Temperature, humidity-b.Humidity
+		if d := dt*dt + dh*dh; d < best {
+			best, biome = d, b
+		}
+	}
+
+	g.biomesMutex.Lock()
+	g.biomes[worldX] = biome
+	g.biomesMutex.Unlock()
+	return biome
+}
+
+// biomeIn reports whether a cached biome belongs to the current biome table; the
+// table is rebuilt when a new block registry is installed
+func biomeIn(biome *Biome, all []*Biome) bool {
+	for _, b := range all {
+		if b == biome {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSurfaceBiomeAt returns the biome whose blocks and trees cover a column. Near a
+// border it picks a biome from up to half the blend distance away so the two mix
+// over a ragged strip instead of meeting at a straight edge.
+func (g *Generator) GetSurfaceBiomeAt(worldX int) *Biome {
+	half := g.config.BiomeBlendDistance / 2
+	offset := int(g.hash(worldX, 0, featureBiomeBorder)%uint64(2*half+1)) - half
+	return g.GetBiomeAt(worldX + offset)
+}
+
+// biomeHeight returns the height scale and offset of a column, averaged over the
+// blend distance so the ground slopes smoothly from one biome into the next
+func (g *Generator) biomeHeight(worldX int) (scale, offset float64) {
+	step := max(g.config.BiomeBlendDistance/4, 1)
+	samples := 0
+	for dx := -g.config.BiomeBlendDistance; dx <= g.config.BiomeBlendDistance; dx += step {
+		b := g.GetBiomeAt(worldX + dx)
+		scale += b.HeightScale
+		offset += float64(b.HeightOffset)
+		samples++
+	}
+	return scale / float64(samples), offset / float64(samples)
+}
@@ -3,15 +3,15 @@ package generation
 import "github.com/KdntNinja/webcraft/settings"
 
 // IsCave determines if a position should be a cave using 3D-like noise
-func IsCave(worldX, worldY int) bool {
-	surfaceHeight := GetHeightAt(worldX)
+func (g *Generator) IsCave(worldX, worldY int) bool {
+	surfaceHeight := g.GetHeightAt(worldX)
 
 	// Allow caves to generate closer to surface, including surface entrances
 	if worldY < surfaceHeight-2 {
 		return false // Above ground
 	}
 
-	caveNoise := GetCaveNoise()
+	caveNoise := g.cavesNoise
 	x := float64(worldX)
 	y := float64(worldY)
 	depth := worldY - surfaceHeight
@@ -86,8 +86,8 @@ func IsCave(worldX, worldY int) bool {
 }
 
 // IsLargeCavern determines if a position should be part of a large underground cavern
-func IsLargeCavern(worldX, worldY int) bool {
-	surfaceHeight := GetHeightAt(worldX)
+func (g *Generator) IsLargeCavern(worldX, worldY int) bool {
+	surfaceHeight := g.GetHeightAt(worldX)
 	depth := worldY - surfaceHeight
 
 	// Allow large caverns closer to surface
@@ -95,7 +95,7 @@ func IsLargeCavern(worldX, worldY int) bool {
 		return false
 	}
 
-	caveNoise := GetCaveNoise()
+	caveNoise := g.cavesNoise
 	x := float64(worldX)
 	y := float64(worldY)
 
@@ -117,8 +117,8 @@ func IsLargeCavern(worldX, worldY int) bool {
 }
 
 // GetCaveWaterLevel determines if a cave position should have water
-func GetCaveWaterLevel(worldX, worldY int) bool {
-	surfaceHeight := GetHeightAt(worldX)
+func (g *Generator) GetCaveWaterLevel(worldX, worldY int) bool {
+	surfaceHeight := g.GetHeightAt(worldX)
 	depth := worldY - surfaceHeight
 
 	// Water only appears in deeper caves
@@ -127,39 +127,40 @@ func GetCaveWaterLevel(worldX, worldY int) bool {
 	}
 
 	// Use ore noise for water placement to avoid conflicts
-	oreNoise := GetOreNoise()
+	oreNoise := g.oresNoise
 	x := float64(worldX)
 	y := float64(worldY)
 
 	waterNoise := oreNoise.Noise2D(x/30.0+6000, y/30.0+6000)
 
 	// Water pools in low-lying cave areas
-	// More common in very deep areas
-	threshold := 0.75 - float64(depth-60)*0.002
-	if threshold < 0.65 {
-		threshold = 0.65
+	// More common in very deep areas and in wetter biomes
+	base := g.GetBiomeAt(worldX).CaveWater
+	threshold := base - float64(depth-60)*0.002
+	if threshold < base-0.1 {
+		threshold = base - 0.1
 	}
 
 	return waterNoise > threshold
 }
 
 // IsSurfaceCaveEntrance specifically creates visible cave entrances at the surface
-func IsSurfaceCaveEntrance(worldX, worldY int) bool {
-	surfaceHeight := GetHeightAt(worldX)
+func (g *Generator) IsSurfaceCaveEntrance(worldX, worldY int) bool {
+	surfaceHeight := g.GetHeightAt(worldX)
 
 	// Only check positions at or just below surface
 	if worldY < surfaceHeight || worldY > surfaceHeight+3 {
 		return false
 	}
 
-	caveNoise := GetCaveNoise()
+	caveNoise := g.cavesNoise
 	x := float64(worldX)
 
 	// Use a different noise pattern for entrance placement
 	entranceNoise := caveNoise.Noise2D(x/40.0+9000, 0)
 
 	// Make entrances more likely in hilly areas
-	terrainNoise := GetTerrainNoise()
+	terrainNoise := g.terrainNoise
 	hilliness := terrainNoise.Noise2D(x/30.0, 0)
 
 	// Combine entrance noise with terrain variation
@@ -2,23 +2,14 @@ package generation
 
 import (
 	"fmt"
-	"math/rand"
 	"sync"
 
 	"github.com/KdntNinja/webcraft/coretypes"
 	"github.com/KdntNinja/webcraft/settings"
 )
 
-// GetWorldSeedFunc is set by the init function to avoid import cycles
-var GetWorldSeedFunc func() int64
-
-func init() {
-	// Set the function variable to avoid import cycle
-	GetWorldSeedFunc = GetSeed
-}
-
 // GenerateChunk creates a chunk with Minecraft-like Perlin noise terrain generation
-func GenerateChunk(chunkX, chunkY int) coretypes.Chunk {
+func (g *Generator) GenerateChunk(chunkX, chunkY int) coretypes.Chunk {
 	fmt.Printf("CHUNK_GEN: Generating chunk at (%d, %d) with Perlin noise\n", chunkX, chunkY)
 	var chunk coretypes.Chunk
 	// Initialize the Blocks 2D slice
@@ -34,85 +25,60 @@ func GenerateChunk(chunkX, chunkY int) coretypes.Chunk {
 	chunkWorldX := chunkX * settings.ChunkWidth
 	chunkWorldY := chunkY * settings.ChunkHeight
 
-	// Create random generator for this chunk (for trees, etc.)
-	chunkSeed := GetWorldSeedFunc() + int64(chunkX*1000+chunkY)
-	rng := rand.New(rand.NewSource(chunkSeed))
-
-	// Generate terrain for each column in the chunk (parallelized)
-	var wg1 sync.WaitGroup
+	// Generate terrain for each column in the chunk (parallelized). Random choices are
+	// hashed from the block position, so they don't depend on which column runs first.
+	var wg sync.WaitGroup
 	for x := 0; x < settings.ChunkWidth; x++ {
-		wg1.Add(1)
+		wg.Add(1)
 		go func(x int) {
-			defer wg1.Done()
+			defer wg.Done()
 			worldX := chunkWorldX + x
-			surfaceHeight := GetHeightAt(worldX)
+			surfaceHeight := g.GetHeightAt(worldX)
 
 			// Generate each block in this column from top to bottom (Y=0 is top)
 			for chunkLocalY := 0; chunkLocalY < settings.ChunkHeight; chunkLocalY++ {
 				worldY := chunkWorldY + chunkLocalY
-				var blockType coretypes.BlockType
 				if worldY < surfaceHeight {
 					// Above surface - air (already initialized)
 					continue
-				} else if worldY == surfaceHeight {
-					if IsSurfaceCaveEntrance(worldX, worldY) {
-						blockType = coretypes.Air
-					} else {
-						blockType = GetSurfaceBlockType(worldX)
-					}
-				} else if worldY <= surfaceHeight+4 {
-					if IsSurfaceCaveEntrance(worldX, worldY) {
-						blockType = coretypes.Air
-					} else {
-						blockType = GetShallowUndergroundBlock(worldX, worldY)
-					}
-				} else {
-					if IsCave(worldX, worldY) {
-						if IsLargeCavern(worldX, worldY) {
-							if GetCaveWaterLevel(worldX, worldY) {
-								blockType = coretypes.Water
-							} else {
-								blockType = coretypes.Air
-							}
-						} else {
-							liquidType := IsLiquid(worldX, worldY)
-							if liquidType > 0 {
-								blockType = coretypes.Water
-							} else {
-								blockType = coretypes.Air
-							}
-						}
-					} else {
-						blockType = GetUndergroundBlock(worldX, worldY, surfaceHeight, rng)
-					}
 				}
-				chunk.Blocks[chunkLocalY][x] = blockType
+				chunk.Blocks[chunkLocalY][x] = g.terrainBlockAt(worldX, worldY, surfaceHeight)
 			}
 		}(x)
 	}
-	wg1.Wait()
+	wg.Wait()
 
-	// Generate trees in a separate pass to avoid coordinate confusion (parallelized)
-	var wg2 sync.WaitGroup
-	for x := 0; x < settings.ChunkWidth; x++ {
-		wg2.Add(1)
-		go func(x int) {
-			defer wg2.Done()
-			worldX := chunkWorldX + x
-			surfaceHeight := GetHeightAt(worldX)
-			surfaceChunkY := surfaceHeight - chunkWorldY
-
-			// Check if surface is in this chunk and is grass
-			if surfaceChunkY >= 0 && surfaceChunkY < settings.ChunkHeight &&
-				chunk.Blocks[surfaceChunkY][x] == coretypes.Grass &&
-				rng.Float64() < settings.TreeChance &&
-				x > 0 && x < settings.ChunkWidth-1 {
-				GenerateTreeAtPosition(&chunk, x, surfaceChunkY, rng)
-			}
-		}(x)
-	}
-	wg2.Wait()
+	// Trees and other structures, including the parts of neighbouring chunks' trees
+	// that reach into this one
+	g.placeStructures(&chunk, chunkX, chunkY)
 
 	fmt.Printf("CHUNK_GEN: Completed chunk (%d, %d) with Perlin noise terrain\n", chunkX, chunkY)
 	return chunk
 }
+
+// terrainBlockAt returns the block the terrain pass puts at a world position, before
+// any structures. surfaceHeight is the surface of the column.
+func (g *Generator) terrainBlockAt(worldX, worldY, surfaceHeight int) coretypes.BlockType {
+	if worldY < surfaceHeight {
+		return coretypes.Air
+	} else if worldY == surfaceHeight {
+		if g.IsSurfaceCaveEntrance(worldX, worldY) {
+			return coretypes.Air
+		}
+		return g.GetSurfaceBlockType(worldX)
+	} else if worldY <= surfaceHeight+4 {
+		if g.IsSurfaceCaveEntrance(worldX, worldY) {
+			return coretypes.Air
+		}
+		return g.GetShallowUndergroundBlock(worldX, worldY)
+	} else if g.IsCave(worldX, worldY) {
+		if g.IsLargeCavern(worldX, worldY) {
+			if g.GetCaveWaterLevel(worldX, worldY) {
+				return coretypes.Water
+			}
+			return coretypes.Air
+		}
+		return g.IsLiquid(worldX, worldY)
+	}
+	return g.GetUndergroundBlock(worldX, worldY, surfaceHeight)
+}
@@ -0,0 +1,84 @@
+package generation
+
+import (
+	"sync"
+
+	"github.com/KdntNinja/webcraft/settings"
+	"github.com/aquilax/go-perlin"
+)
+
+// GeneratorVersion identifies the terrain generator; bump it whenever the same seed
+// would produce different chunks so saved worlds can detect the mismatch
+const GeneratorVersion = 6
+
+// Config holds the parameters a generator shapes its terrain with
+type Config struct {
+	PerlinAlpha   float64 // Noise smoothness
+	PerlinBeta    float64 // Noise detail
+	PerlinOctaves int32   // Noise layers
+
+	SurfaceBaseHeight  int     // Average surface Y
+	SurfaceHeightVar   int     // Surface height variation
+	BiomeScale         float64 // Blocks across which temperature and humidity change
+	BiomeBlendDistance int     // Biome blend width in blocks
+}
+
+// DefaultConfig returns the config the game generates worlds with
+func DefaultConfig() Config {
+	return Config{
+		PerlinAlpha:        settings.PerlinAlpha,
+		PerlinBeta:         settings.PerlinBeta,
+		PerlinOctaves:      settings.PerlinOctaves,
+		SurfaceBaseHeight:  settings.SurfaceBaseHeight,
+		SurfaceHeightVar:   settings.SurfaceHeightVar,
+		BiomeScale:         settings.BiomeScale,
+		BiomeBlendDistance: settings.BiomeBlendDistance,
+	}
+}
+
+// Generator produces the terrain of one world. It owns its noise sources and caches,
+// so generators for different seeds can be used side by side. Its methods are safe
+// for concurrent use.
+type Generator struct {
+	seed   int64
+	config Config
+
+	terrainNoise     *perlin.Perlin
+	cavesNoise       *perlin.Perlin
+	oresNoise        *perlin.Perlin
+	temperatureNoise *perlin.Perlin
+	humidityNoise    *perlin.Perlin
+
+	heights      map[int]int // Surface height of each column queried so far
+	heightsMutex sync.RWMutex
+	biomes       map[int]*Biome // Biome of each column queried so far
+	biomesMutex  sync.RWMutex
+}
+
+// NewGenerator creates a generator for a world seed
+func NewGenerator(seed int64, config Config) *Generator {
+	newNoise := func(offset int64) *perlin.Perlin {
+		return perlin.NewPerlin(config.PerlinAlpha, config.PerlinBeta, config.PerlinOctaves, seed+offset)
+	}
+	return &Generator{
+		seed:             seed,
+		config:           config,
+		terrainNoise:     newNoise(0),
+		cavesNoise:       newNoise(1000),
+		oresNoise:        newNoise(2000),
+		temperatureNoise: newNoise(3000),
+		humidityNoise:    newNoise(4000),
+		heights:          make(map[int]int),
+		biomes:           make(map[int]*Biome),
+	}
+}
+
+// Seed returns the world seed the generator was created with
+func (g *Generator) Seed() int64 {
+	return g.seed
+}
+
+// Config returns the config the generator was created with
+func (g *Generator) Config() Config {
+	return g.config
+}
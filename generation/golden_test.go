@@ -0,0 +1,108 @@
+package generation
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden chunk hashes in testdata")
+
+const goldenFile = "golden_chunks.txt"
+
+// goldenChunks are the seeds and chunks whose hashes are pinned: the surface, caves,
+// a chunk west of the origin, deep chunks and far-off ones
+var goldenChunks = []struct {
+	seed int64
+	x, y int
+}{
+	{42, 0, 1}, {42, 0, 2}, {42, -1, 1}, {42, 5, 3},
+	{7, 0, 1}, {7, -30, 1}, {12345, 100, 2}, {1, 2, 6},
+}
+
+// hashChunk hashes a chunk's blocks by name, plus liquid levels, so the hash only
+// changes when the generated world does
+func hashChunk(chunk coretypes.Chunk) uint64 {
+	h := fnv.New64a()
+	for y, row := range chunk.Blocks {
+		for x, block := range row {
+			fmt.Fprintf(h, "%s/", block)
+			if block.IsLiquid() {
+				fmt.Fprintf(h, "%d/", chunk.LiquidLevel(x, y))
+			}
+		}
+	}
+	return h.Sum64()
+}
+
+// goldenHashes generates every golden chunk, in the given order, with one fresh
+// generator per seed
+func goldenHashes(order []int) map[int]string {
+	generators := map[int64]*Generator{}
+	hashes := map[int]string{}
+	for _, i := range order {
+		c := goldenChunks[i]
+		if generators[c.seed] == nil {
+			generators[c.seed] = NewGenerator(c.seed, DefaultConfig())
+		}
+		hashes[i] = fmt.Sprintf("%d %d %d %016x", c.seed, c.x, c.y, hashChunk(generators[c.seed].GenerateChunk(c.x, c.y)))
+	}
+	return hashes
+}
+
+func TestGoldenChunks(t *testing.T) {
+	forward, backward := make([]int, len(goldenChunks)), make([]int, len(goldenChunks))
+	for i := range goldenChunks {
+		forward[i], backward[len(goldenChunks)-1-i] = i, i
+	}
+	got := goldenHashes(forward)
+	path := filepath.Join("testdata", goldenFile)
+
+	if *update {
+		var b strings.Builder
+		b.WriteString("# seed chunkX chunkY hash; regenerate with go test ./generation -run TestGoldenChunks -update\n")
+		for i := range goldenChunks {
+			b.WriteString(got[i] + "\n")
+		}
+		if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var want []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" && !strings.HasPrefix(line, "#") {
+			want = append(want, line)
+		}
+	}
+	if len(want) != len(goldenChunks) {
+		t.Fatalf("%s has %d hashes for %d chunks; run with -update", path, len(want), len(goldenChunks))
+	}
+	for i := range goldenChunks {
+		if got[i] != want[i] {
+			t.Errorf("generated %s, golden %s", got[i], want[i])
+		}
+	}
+
+	// Generating the chunks the other way round must give the same blocks
+	reversed := goldenHashes(backward)
+	for i := range goldenChunks {
+		if reversed[i] != got[i] {
+			t.Errorf("generated in reverse order %s, in order %s", reversed[i], got[i])
+		}
+	}
+}
@@ -1,25 +1,20 @@
 package generation
 
 import (
-	"sync"
-
 	"github.com/KdntNinja/webcraft/settings"
 )
 
-var terrainHeights = make(map[int]int)
-var terrainHeightsMutex sync.RWMutex
-
 // GetHeightAt calculates the terrain height at a given world X coordinate
-func GetHeightAt(worldX int) int {
+func (g *Generator) GetHeightAt(worldX int) int {
 	// Check cache first (read lock)
-	terrainHeightsMutex.RLock()
-	height, exists := terrainHeights[worldX]
-	terrainHeightsMutex.RUnlock()
+	g.heightsMutex.RLock()
+	height, exists := g.heights[worldX]
+	g.heightsMutex.RUnlock()
 	if exists {
 		return height
 	}
 
-	noise := GetTerrainNoise()
+	noise := g.terrainNoise
 
 	// Generate height using multiple noise layers for realistic terrain
 	x := float64(worldX)
@@ -36,8 +31,9 @@ func GetHeightAt(worldX int) int {
 	// Combine noise layers
 	combinedNoise := baseHeight*settings.TerrainBaseWeight + hillHeight*settings.TerrainHillWeight + detailHeight*settings.TerrainDetailWeight
 
-	// Scale and offset to world coordinates
-	height = int(float64(settings.SurfaceBaseHeight) + combinedNoise*float64(settings.SurfaceHeightVar))
+	// Scale and offset to world coordinates, shaped by the biomes around this column
+	scale, offset := g.biomeHeight(worldX)
+	height = int(float64(g.config.SurfaceBaseHeight) + offset + combinedNoise*float64(g.config.SurfaceHeightVar)*scale)
 
 	// Ensure height is within reasonable bounds
 	minHeight := settings.TerrainMinHeight
@@ -50,16 +46,9 @@ func GetHeightAt(worldX int) int {
 	}
 
 	// Cache the result (write lock)
-	terrainHeightsMutex.Lock()
-	terrainHeights[worldX] = height
-	terrainHeightsMutex.Unlock()
+	g.heightsMutex.Lock()
+	g.heights[worldX] = height
+	g.heightsMutex.Unlock()
 
 	return height
 }
-
-// ResetHeightCache clears the terrain height cache
-func ResetHeightCache() {
-	terrainHeightsMutex.Lock()
-	terrainHeights = make(map[int]int)
-	terrainHeightsMutex.Unlock()
-}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 
@@ -27,6 +28,7 @@ type ChunkGenerationJob struct {
 
 // ChunkManager handles dynamic chunk loading and unloading with multithreaded generation
 type ChunkManager struct {
+	generator       *Generator
 	chunks          map[ChunkCoord]*coretypes.Chunk
 	loadedChunks    map[ChunkCoord]bool
 	generating      map[ChunkCoord]bool     // Track chunks being generated
@@ -49,6 +51,19 @@ type ChunkManager struct {
 	concurrentJobsMutex   sync.Mutex
 	frameCount            int // Frame counter for per-frame operations
 
+	// Synchronous mode generates chunks inline on the caller's goroutine so that
+	// chunk availability depends only on the sequence of calls (deterministic simulation)
+	synchronous bool
+
+	// Persistence of player-modified chunks
+	store        coretypes.ChunkStore
+	dirty        map[ChunkCoord]bool             // Chunks modified since they were last saved
+	pendingSaves map[ChunkCoord]*coretypes.Chunk // Unloaded dirty chunks waiting to be written
+	saveMutex    sync.Mutex                      // Serializes writes to the store
+
+	// Told about every chunk load and unload, e.g. to spawn and despawn mobs
+	listener coretypes.ChunkListener
+
 	// Performance metrics
 	generationMetrics struct {
 		totalGenerated int64
@@ -64,11 +79,14 @@ type chunkResult struct {
 	generationTime time.Duration
 }
 
-func NewChunkManager(viewDistance int) *ChunkManager {
+func NewChunkManager(generator *Generator, viewDistance int) *ChunkManager {
 	cm := &ChunkManager{
+		generator:       generator,
 		chunks:          make(map[ChunkCoord]*coretypes.Chunk),
 		loadedChunks:    make(map[ChunkCoord]bool),
 		generating:      make(map[ChunkCoord]bool),
+		dirty:           make(map[ChunkCoord]bool),
+		pendingSaves:    make(map[ChunkCoord]*coretypes.Chunk),
 		chunkQueue:      make(chan chunkResult, 32),
 		jobQueue:        make(chan ChunkGenerationJob, 64), // Buffered job queue
 		viewDistance:    viewDistance,
@@ -80,6 +98,79 @@ func NewChunkManager(viewDistance int) *ChunkManager {
 	return cm
 }
 
+// NewSyncChunkManager creates a chunk manager that generates chunks inline with no
+// worker goroutines. Chunks only appear through UpdatePlayerPosition/GetChunk, and
+// block lookups never trigger generation, so the loaded set is fully deterministic.
+func NewSyncChunkManager(generator *Generator, viewDistance int) *ChunkManager {
+	return &ChunkManager{
+		generator:       generator,
+		chunks:          make(map[ChunkCoord]*coretypes.Chunk),
+		loadedChunks:    make(map[ChunkCoord]bool),
+		generating:      make(map[ChunkCoord]bool),
+		dirty:           make(map[ChunkCoord]bool),
+		pendingSaves:    make(map[ChunkCoord]*coretypes.Chunk),
+		viewDistance:    viewDistance,
+		lastPlayerChunk: ChunkCoord{X: math.MaxInt32, Y: math.MaxInt32}, // Force initial load
+		synchronous:     true,
+	}
+}
+
+// Generator returns the generator new chunks come from
+func (cm *ChunkManager) Generator() *Generator {
+	return cm.generator
+}
+
+// SetChunkStore sets where modified chunks are saved on unload and loaded from before generating
+func (cm *ChunkManager) SetChunkStore(store coretypes.ChunkStore) {
+	cm.mutex.Lock()
+	cm.store = store
+	cm.mutex.Unlock()
+}
+
+// SetChunkListener sets who is told when chunks load and unload. The listener is
+// told about every chunk that is already loaded straight away, in coordinate order.
+func (cm *ChunkManager) SetChunkListener(listener coretypes.ChunkListener) {
+	cm.mutex.Lock()
+	cm.listener = listener
+	loaded := make([]ChunkCoord, 0, len(cm.chunks))
+	for coord := range cm.chunks {
+		loaded = append(loaded, coord)
+	}
+	sortChunkCoords(loaded)
+	chunks := make([]*coretypes.Chunk, len(loaded))
+	for i, coord := range loaded {
+		chunks[i] = cm.chunks[coord]
+	}
+	cm.mutex.Unlock()
+
+	if listener == nil {
+		return
+	}
+	for i, coord := range loaded {
+		listener.ChunkLoaded(coretypes.ChunkCoord{X: coord.X, Y: coord.Y}, chunks[i])
+	}
+}
+
+// notifyLoaded tells the listener, if any, that a chunk was loaded
+func (cm *ChunkManager) notifyLoaded(coord ChunkCoord, chunk *coretypes.Chunk) {
+	cm.mutex.RLock()
+	listener := cm.listener
+	cm.mutex.RUnlock()
+	if listener != nil {
+		listener.ChunkLoaded(coretypes.ChunkCoord{X: coord.X, Y: coord.Y}, chunk)
+	}
+}
+
+// sortChunkCoords sorts chunk coordinates by row, then column
+func sortChunkCoords(coords []ChunkCoord) {
+	sort.Slice(coords, func(i, j int) bool {
+		if coords[i].Y != coords[j].Y {
+			return coords[i].Y < coords[j].Y
+		}
+		return coords[i].X < coords[j].X
+	})
+}
+
 // chunkInsertWorker runs in the background and inserts generated chunks into the map
 func (cm *ChunkManager) chunkInsertWorker() {
 	for res := range cm.chunkQueue {
@@ -89,6 +180,7 @@ func (cm *ChunkManager) chunkInsertWorker() {
 		delete(cm.generating, res.coord)
 		cm.chunksLoadedThisFrame++
 		cm.mutex.Unlock()
+		cm.notifyLoaded(res.coord, res.chunk)
 
 		// Log performance if slow
 		if res.generationTime > time.Duration(settings.SlowChunkGenerationThreshold)*time.Millisecond {
@@ -136,11 +228,12 @@ func (cm *ChunkManager) worker(workerID int) {
 
 			start := time.Now()
 
-			// Generate the chunk using generation package
-			engChunk := GenerateChunk(job.coord.X, job.coord.Y)
+			// Prefer a saved copy of the chunk, falling back to generating it from noise
+			chunk, loaded := cm.loadSavedChunk(job.coord)
+			if !loaded {
+				chunk = cm.generator.GenerateChunk(job.coord.X, job.coord.Y)
+			}
 			generationTime := time.Since(start)
-			// Convert engine.Chunk to coretypes.Chunk if needed
-			var chunk coretypes.Chunk = engChunk
 
 			// Update concurrent job count
 			cm.concurrentJobsMutex.Lock()
@@ -171,6 +264,30 @@ func (cm *ChunkManager) worker(workerID int) {
 	}
 }
 
+// loadSavedChunk reads a chunk from the store, returning false if it was never saved
+func (cm *ChunkManager) loadSavedChunk(coord ChunkCoord) (coretypes.Chunk, bool) {
+	cm.mutex.RLock()
+	store := cm.store
+	cm.mutex.RUnlock()
+	if store == nil {
+		return coretypes.Chunk{}, false
+	}
+
+	// Wait for any in-flight save so we never read a stale copy
+	cm.saveMutex.Lock()
+	defer cm.saveMutex.Unlock()
+
+	saved, ok, err := store.LoadChunk(coretypes.ChunkCoord{X: coord.X, Y: coord.Y})
+	if err != nil {
+		fmt.Printf("CHUNK_MANAGER: Failed to load saved chunk (%d, %d): %v\n", coord.X, coord.Y, err)
+		return coretypes.Chunk{}, false
+	}
+	if !ok || len(saved.Blocks) != settings.ChunkHeight || len(saved.Blocks[0]) != settings.ChunkWidth {
+		return coretypes.Chunk{}, false
+	}
+	return *saved, true
+}
+
 // GetChunk returns a chunk at the given coordinates, generating it if necessary
 func (cm *ChunkManager) GetChunk(chunkX, chunkY int) *coretypes.Chunk {
 	coord := ChunkCoord{X: chunkX, Y: chunkY}
@@ -178,11 +295,33 @@ func (cm *ChunkManager) GetChunk(chunkX, chunkY int) *coretypes.Chunk {
 	cm.mutex.RLock()
 	chunk, exists := cm.chunks[coord]
 	generating := cm.generating[coord]
+	_, pending := cm.pendingSaves[coord]
 	cm.mutex.RUnlock()
 
 	if exists {
 		return chunk
 	}
+	if pending {
+		// Unloaded but not yet written; bring it straight back and keep it dirty
+		cm.mutex.Lock()
+		if chunk, ok := cm.pendingSaves[coord]; ok {
+			delete(cm.pendingSaves, coord)
+			cm.chunks[coord] = chunk
+			cm.loadedChunks[coord] = true
+			cm.dirty[coord] = true
+			cm.mutex.Unlock()
+			cm.notifyLoaded(coord, chunk)
+			return chunk
+		}
+		chunk, exists = cm.chunks[coord]
+		cm.mutex.Unlock()
+		if exists {
+			return chunk
+		}
+	}
+	if cm.synchronous {
+		return cm.generateChunkNow(coord)
+	}
 	if generating {
 		return nil // Still generating, return nil for now
 	}
@@ -203,6 +342,44 @@ func (cm *ChunkManager) GetChunk(chunkX, chunkY int) *coretypes.Chunk {
 	return nil // Not ready yet
 }
 
+// generateChunkNow loads or generates a chunk on the calling goroutine and inserts it
+func (cm *ChunkManager) generateChunkNow(coord ChunkCoord) *coretypes.Chunk {
+	start := time.Now()
+	chunk, loaded := cm.loadSavedChunk(coord)
+	if !loaded {
+		chunk = cm.generator.GenerateChunk(coord.X, coord.Y)
+	}
+	generationTime := time.Since(start)
+
+	cm.generationMetrics.mutex.Lock()
+	cm.generationMetrics.totalGenerated++
+	cm.generationMetrics.totalTime += generationTime
+	cm.generationMetrics.mutex.Unlock()
+
+	cm.mutex.Lock()
+	if existing, ok := cm.chunks[coord]; ok {
+		cm.mutex.Unlock()
+		return existing
+	}
+	cm.chunks[coord] = &chunk
+	cm.loadedChunks[coord] = true
+	cm.chunksLoadedThisFrame++
+	cm.mutex.Unlock()
+	cm.notifyLoaded(coord, &chunk)
+	return &chunk
+}
+
+// lookupChunk returns a chunk for block access. Synchronous managers only return
+// chunks that are already loaded so reads never change the loaded set.
+func (cm *ChunkManager) lookupChunk(chunkX, chunkY int) *coretypes.Chunk {
+	if !cm.synchronous {
+		return cm.GetChunk(chunkX, chunkY)
+	}
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+	return cm.chunks[ChunkCoord{X: chunkX, Y: chunkY}]
+}
+
 // UpdatePlayerPosition updates the chunk loading based on player position
 func (cm *ChunkManager) UpdatePlayerPosition(playerX, playerY float64) {
 	// Reset frame counters for anti-stutter tracking
@@ -307,10 +484,9 @@ func (cm *ChunkManager) loadChunksAroundPlayer(playerChunkX, playerChunkY int) {
 	}
 }
 
-// unloadDistantChunks unloads chunks that are too far from the player
+// unloadDistantChunks unloads chunks that are too far from the player, saving any the player modified
 func (cm *ChunkManager) unloadDistantChunks(playerChunkX, playerChunkY int) {
 	cm.mutex.Lock()
-	defer cm.mutex.Unlock()
 
 	unloadDistance := cm.viewDistance + 2 // Keep 2 extra chunks before unloading
 	var toUnload []ChunkCoord
@@ -325,14 +501,127 @@ func (cm *ChunkManager) unloadDistantChunks(playerChunkX, playerChunkY int) {
 		}
 	}
 
+	// Write modified chunks outside the map lock so disk I/O never blocks rendering
+	if cm.unload(toUnload) {
+		go func() {
+			if err := cm.flushPendingSaves(); err != nil {
+				fmt.Printf("CHUNK_MANAGER: Failed to save unloaded chunks: %v\n", err)
+			}
+		}()
+	}
+}
+
+// UnloadChunksOutside unloads every chunk more than radius chunks away, on either
+// axis, from all of the given centres, and writes the modified ones to the store
+// before returning. Managers shared by several players, like the server's, use it
+// instead of UpdatePlayerPosition. Chunks whose save fails stay pending and are
+// retried by the next save.
+func (cm *ChunkManager) UnloadChunksOutside(centers []coretypes.ChunkCoord, radius int) error {
+	cm.mutex.Lock()
+	var toUnload []ChunkCoord
+	for coord := range cm.chunks {
+		near := false
+		for _, center := range centers {
+			if abs(coord.X-center.X) <= radius && abs(coord.Y-center.Y) <= radius {
+				near = true
+				break
+			}
+		}
+		if !near {
+			toUnload = append(toUnload, coord)
+		}
+	}
+
+	if cm.unload(toUnload) {
+		return cm.flushPendingSaves()
+	}
+	return nil
+}
+
+// unload drops chunks from the loaded set, queueing the modified ones to be saved,
+// and tells the listener. It must be called with the mutex locked, and unlocks it.
+// It reports whether any chunk is waiting to be saved.
+func (cm *ChunkManager) unload(toUnload []ChunkCoord) bool {
+	savePending := false
 	for _, coord := range toUnload {
+		if cm.dirty[coord] && cm.store != nil {
+			cm.pendingSaves[coord] = cm.chunks[coord]
+			savePending = true
+		}
+		delete(cm.dirty, coord)
 		delete(cm.chunks, coord)
 		delete(cm.loadedChunks, coord)
 	}
+	listener := cm.listener
+	cm.mutex.Unlock()
+
+	if listener != nil {
+		sortChunkCoords(toUnload)
+		for _, coord := range toUnload {
+			listener.ChunkUnloaded(coretypes.ChunkCoord{X: coord.X, Y: coord.Y})
+		}
+	}
 
 	if len(toUnload) > 0 {
 		fmt.Printf("CHUNK_MANAGER: Unloaded %d distant chunks\n", len(toUnload))
 	}
+	return savePending
+}
+
+// flushPendingSaves writes every unloaded dirty chunk to the store
+func (cm *ChunkManager) flushPendingSaves() error {
+	cm.saveMutex.Lock()
+	defer cm.saveMutex.Unlock()
+
+	cm.mutex.RLock()
+	store := cm.store
+	pending := make(map[ChunkCoord]*coretypes.Chunk, len(cm.pendingSaves))
+	for coord, chunk := range cm.pendingSaves {
+		pending[coord] = chunk
+	}
+	cm.mutex.RUnlock()
+
+	var firstErr error
+	for coord, chunk := range pending {
+		// Hold the read lock so SetBlock can't modify a still-loaded chunk mid-encode
+		cm.mutex.RLock()
+		err := store.SaveChunk(coretypes.ChunkCoord{X: coord.X, Y: coord.Y}, chunk)
+		cm.mutex.RUnlock()
+
+		cm.mutex.Lock()
+		if err != nil {
+			// Keep it pending so the next flush retries
+			if firstErr == nil {
+				firstErr = err
+			}
+		} else if cm.pendingSaves[coord] == chunk {
+			delete(cm.pendingSaves, coord)
+		}
+		cm.mutex.Unlock()
+	}
+
+	if len(pending) > 0 && firstErr == nil {
+		fmt.Printf("CHUNK_MANAGER: Saved %d modified chunks\n", len(pending))
+	}
+	return firstErr
+}
+
+// SaveDirtyChunks writes all modified chunks, loaded or not, to the store
+func (cm *ChunkManager) SaveDirtyChunks() error {
+	cm.mutex.Lock()
+	if cm.store == nil {
+		cm.mutex.Unlock()
+		return nil
+	}
+	for coord := range cm.dirty {
+		if chunk, ok := cm.chunks[coord]; ok {
+			cm.pendingSaves[coord] = chunk
+		}
+	}
+	cm.dirty = make(map[ChunkCoord]bool)
+	cm.mutex.Unlock()
+
+	return cm.flushPendingSaves()
 }
 
 // InitialLoadWithProgress loads chunks around the spawn point during world creation and updates progress
@@ -349,6 +638,20 @@ func (cm *ChunkManager) InitialLoadWithProgress(spawnX, spawnY float64) {
 	// Set progress for initial chunk loading
 	progress.SetCurrentStepSubSteps(totalChunks, fmt.Sprintf("Loading %d initial chunks...", totalChunks))
 
+	// Synchronous managers load in a fixed order on this goroutine
+	if cm.synchronous {
+		for dx := -cm.viewDistance; dx <= cm.viewDistance; dx++ {
+			for dy := -cm.viewDistance; dy <= cm.viewDistance; dy++ {
+				cm.GetChunk(spawnChunkX+dx, spawnChunkY+dy)
+				generatedChunks++
+				progress.UpdateCurrentStepProgress(generatedChunks,
+					fmt.Sprintf("Loaded chunk %d/%d at (%d, %d)", generatedChunks, totalChunks, spawnChunkX+dx, spawnChunkY+dy))
+			}
+		}
+		cm.lastPlayerChunk = ChunkCoord{X: spawnChunkX, Y: spawnChunkY}
+		return
+	}
+
 	// Load chunks around spawn point in parallel
 	var wg sync.WaitGroup
 	for dx := -cm.viewDistance; dx <= cm.viewDistance; dx++ {
@@ -398,8 +701,14 @@ func (cm *ChunkManager) IsChunkLoaded(chunkX, chunkY int) bool {
 	return cm.loadedChunks[coord]
 }
 
-// SetBlock sets a block at the given world coordinates through the chunk manager
+// SetBlock sets a block at the given world coordinates through the chunk manager.
+// Liquids placed this way are full.
 func (cm *ChunkManager) SetBlock(blockX, blockY int, blockType coretypes.BlockType) bool {
+	return cm.SetLiquid(blockX, blockY, blockType, coretypes.MaxLiquidLevel)
+}
+
+// SetLiquid sets a block and its liquid fill level at the given world coordinates
+func (cm *ChunkManager) SetLiquid(blockX, blockY int, blockType coretypes.BlockType, level int) bool {
 	chunkX := blockX / settings.ChunkWidth
 	chunkY := blockY / settings.ChunkHeight
 	inChunkX := blockX % settings.ChunkWidth
@@ -416,7 +725,7 @@ func (cm *ChunkManager) SetBlock(blockX, blockY int, blockType coretypes.BlockTy
 	}
 
 	// Get or generate the chunk
-	chunk := cm.GetChunk(chunkX, chunkY)
+	chunk := cm.lookupChunk(chunkX, chunkY)
 	if chunk == nil || len(chunk.Blocks) == 0 {
 		return false
 	}
@@ -426,9 +735,11 @@ func (cm *ChunkManager) SetBlock(blockX, blockY int, blockType coretypes.BlockTy
 		return false
 	}
 
-	// Set the block in the chunk
+	// Set the block in the chunk and remember it needs saving
 	cm.mutex.Lock()
 	chunk.Blocks[inChunkY][inChunkX] = blockType
+	chunk.SetLiquidLevel(inChunkX, inChunkY, level)
+	cm.dirty[ChunkCoord{X: chunkX, Y: chunkY}] = true
 	cm.mutex.Unlock()
 
 	return true
@@ -451,7 +762,7 @@ func (cm *ChunkManager) GetBlock(blockX, blockY int) coretypes.BlockType {
 		inChunkY = ((blockY % settings.ChunkHeight) + settings.ChunkHeight) % settings.ChunkHeight
 	}
 
-	chunk := cm.GetChunk(chunkX, chunkY)
+	chunk := cm.lookupChunk(chunkX, chunkY)
 	if chunk == nil || len(chunk.Blocks) == 0 {
 		return coretypes.Air
 	}
@@ -483,6 +794,13 @@ func (cm *ChunkManager) GetGenerationMetrics() (int64, time.Duration) {
 // Shutdown cleanly stops all workers
 func (cm *ChunkManager) Shutdown() {
 	fmt.Println("CHUNK_MANAGER: Shutting down...")
+	if err := cm.SaveDirtyChunks(); err != nil {
+		fmt.Printf("CHUNK_MANAGER: Failed to save chunks on shutdown: %v\n", err)
+	}
+	if cm.synchronous {
+		fmt.Println("CHUNK_MANAGER: Shutdown complete")
+		return
+	}
 	cm.workerCancel()
 	cm.workerPool.Wait()
 	close(cm.chunkQueue)
@@ -492,6 +810,9 @@ func (cm *ChunkManager) Shutdown() {
 
 // Stop stops the chunk manager and waits for workers to finish
 func (cm *ChunkManager) Stop() {
+	if cm.synchronous {
+		return
+	}
 	cm.workerCancel()
 	cm.workerPool.Wait()
 	fmt.Println("CHUNK_MANAGER: All workers stopped")
@@ -516,8 +837,9 @@ func (cm *ChunkManager) ShouldLimitChunkLoading() bool {
 		return true
 	}
 
-	// Check if we've exceeded the time slice for this frame
-	if !cm.frameStartTime.IsZero() {
+	// Check if we've exceeded the time slice for this frame (wall-clock limits
+	// would make synchronous managers nondeterministic, so they only use the count)
+	if !cm.synchronous && !cm.frameStartTime.IsZero() {
 		elapsed := time.Since(cm.frameStartTime)
 		if elapsed.Milliseconds() >= int64(settings.ChunkGenerationTimeSlice) {
 			return true
@@ -0,0 +1,60 @@
+package generation
+
+import (
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// memoryStore is a chunk store kept in a map
+type memoryStore map[coretypes.ChunkCoord]*coretypes.Chunk
+
+func (m memoryStore) LoadChunk(coord coretypes.ChunkCoord) (*coretypes.Chunk, bool, error) {
+	chunk, ok := m[coord]
+	return chunk, ok, nil
+}
+
+func (m memoryStore) SaveChunk(coord coretypes.ChunkCoord, chunk *coretypes.Chunk) error {
+	m[coord] = chunk
+	return nil
+}
+
+func TestUnloadChunksOutside(t *testing.T) {
+	store := memoryStore{}
+	cm := NewSyncChunkManager(NewGenerator(3, DefaultConfig()), 1)
+	cm.SetChunkStore(store)
+	for _, x := range []int{0, 2, 3, 10} {
+		cm.GetChunk(x, 1)
+	}
+	edited := 10*settings.ChunkWidth + 4
+	if !cm.SetBlock(edited, settings.ChunkHeight+5, coretypes.Torch) {
+		t.Fatal("couldn't edit a loaded chunk")
+	}
+
+	// Chunk 2 is near the first centre and chunk 10 near the second
+	if err := cm.UnloadChunksOutside([]coretypes.ChunkCoord{{X: 0, Y: 1}, {X: 12, Y: 1}}, 2); err != nil {
+		t.Fatal(err)
+	}
+	if !cm.IsChunkLoaded(0, 1) || !cm.IsChunkLoaded(2, 1) || !cm.IsChunkLoaded(10, 1) || cm.IsChunkLoaded(3, 1) {
+		t.Fatalf("wrong chunks unloaded; %d still loaded", cm.GetLoadedChunkCount())
+	}
+	if len(store) != 0 {
+		t.Fatalf("saved %d chunks that are still loaded", len(store))
+	}
+
+	// With no one around everything goes, and the edited chunk is saved on the way out
+	if err := cm.UnloadChunksOutside(nil, 2); err != nil {
+		t.Fatal(err)
+	}
+	if cm.GetLoadedChunkCount() != 0 {
+		t.Fatalf("%d chunks still loaded with no centres", cm.GetLoadedChunkCount())
+	}
+	saved, ok := store[coretypes.ChunkCoord{X: 10, Y: 1}]
+	if !ok || len(store) != 1 || saved.Blocks[5][4] != coretypes.Torch {
+		t.Fatalf("saved %d chunks; edited chunk saved: %v", len(store), ok)
+	}
+	if cm.GetChunk(10, 1).Blocks[5][4] != coretypes.Torch {
+		t.Fatal("reloaded chunk lost its edit")
+	}
+}
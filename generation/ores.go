@@ -1,14 +1,16 @@
 package generation
 
+import "github.com/KdntNinja/webcraft/coretypes"
+
 // GetOreType determines what type of ore (if any) should be at a position
-func GetOreType(worldX, worldY int) int {
+func (g *Generator) GetOreType(worldX, worldY int) int {
 	// Only generate ores underground
-	surfaceHeight := GetHeightAt(worldX)
+	surfaceHeight := g.GetHeightAt(worldX)
 	if worldY <= surfaceHeight+10 {
 		return 0 // No ore near surface
 	}
 
-	oreNoise := GetOreNoise()
+	oreNoise := g.oresNoise
 	x := float64(worldX)
 	y := float64(worldY)
 	depth := worldY - surfaceHeight
@@ -49,8 +51,8 @@ func GetOreType(worldX, worldY int) int {
 }
 
 // GetOreVeinDensity returns how dense an ore vein should be (for clustering)
-func GetOreVeinDensity(worldX, worldY, oreType int) float64 {
-	oreNoise := GetOreNoise()
+func (g *Generator) GetOreVeinDensity(worldX, worldY, oreType int) float64 {
+	oreNoise := g.oresNoise
 	x := float64(worldX)
 	y := float64(worldY)
 
@@ -68,8 +70,8 @@ func GetOreVeinDensity(worldX, worldY, oreType int) float64 {
 }
 
 // IsOreVeinExtension checks if a position should extend an existing ore vein
-func IsOreVeinExtension(worldX, worldY, oreType int) bool {
-	density := GetOreVeinDensity(worldX, worldY, oreType)
+func (g *Generator) IsOreVeinExtension(worldX, worldY, oreType int) bool {
+	density := g.GetOreVeinDensity(worldX, worldY, oreType)
 
 	// Different thresholds for different ore types
 	switch oreType {
@@ -84,17 +86,17 @@ func IsOreVeinExtension(worldX, worldY, oreType int) bool {
 	}
 }
 
-// IsLiquid determines if a position should contain liquid (water or lava)
-func IsLiquid(worldX, worldY int) int {
-	surfaceHeight := GetHeightAt(worldX)
+// IsLiquid returns the liquid a cave position should be filled with, or Air for none
+func (g *Generator) IsLiquid(worldX, worldY int) coretypes.BlockType {
+	surfaceHeight := g.GetHeightAt(worldX)
 	depth := worldY - surfaceHeight
 
 	// No liquids near surface
 	if depth < 20 {
-		return 0
+		return coretypes.Air
 	}
 
-	oreNoise := GetOreNoise()
+	oreNoise := g.oresNoise
 	x := float64(worldX)
 	y := float64(worldY)
 
@@ -104,7 +106,7 @@ func IsLiquid(worldX, worldY int) int {
 		waterSpread := oreNoise.Noise2D(x/15.0+3100, y/15.0+3100)
 		waterCombined := waterNoise*0.7 + waterSpread*0.3
 		if waterCombined < -0.75 {
-			return 1 // Water
+			return coretypes.Water
 		}
 	}
 
@@ -114,9 +116,9 @@ func IsLiquid(worldX, worldY int) int {
 		lavaHeat := oreNoise.Noise2D(x/10.0+4100, y/10.0+4100)
 		lavaCombined := lavaNoise*0.8 + lavaHeat*0.2
 		if lavaCombined < -0.8 {
-			return 2 // Lava (represented as Water for now)
+			return coretypes.Lava
 		}
 	}
 
-	return 0 // No liquid
+	return coretypes.Air // No liquid
 }
@@ -0,0 +1,42 @@
+package generation
+
+import "math/rand"
+
+// Features that draw random numbers. Each has its own streams, so changing how one
+// feature draws never shifts another.
+const (
+	featureOres uint64 = iota + 1
+	featureAsh
+	featureDeepWater
+	featureTreePlacement
+	featureTrees
+	featureBiomeBorder
+	featureUnderground
+	FeatureMobSpawns // Drawn per chunk by the mob spawner
+)
+
+// hash mixes the seed, a block position and a feature into one value
+func (g *Generator) hash(x, y int, feature uint64) uint64 {
+	return Hash(g.seed, x, y, feature)
+}
+
+// Hash mixes a seed, a position and a feature into one value, for seeded randomness
+// tied to a place that doesn't depend on the order things are visited in
+func Hash(seed int64, x, y int, feature uint64) uint64 {
+	h := uint64(seed) ^ uint64(int64(x))*0x9e3779b97f4a7c15 ^ uint64(int64(y))*0xc2b2ae3d27d4eb4f ^ feature*0x165667b19e3779f9
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	return h
+}
+
+// chance returns a number in [0, 1) fixed by the seed, a block position and a feature
+func (g *Generator) chance(x, y int, feature uint64) float64 {
+	return float64(g.hash(x, y, feature)>>11) / (1 << 53)
+}
+
+// random returns a random source for a feature that needs many draws at one block
+// position, e.g. the shape of a tree growing there
+func (g *Generator) random(x, y int, feature uint64) *rand.Rand {
+	return rand.New(rand.NewSource(int64(g.hash(x, y, feature))))
+}
@@ -1,10 +1,6 @@
 package generation
 
-import (
-	"math/rand"
-
-	"github.com/KdntNinja/webcraft/coretypes"
-)
+import "math/rand"
 
 // TreeShape defines the shape and size parameters for a tree
 type TreeShape struct {
@@ -14,19 +10,22 @@ type TreeShape struct {
 	HasBranches   bool
 	BranchLength  int
 	IsSparse      bool
-	CustomPattern func(*coretypes.Chunk, int, int, *rand.Rand, TreeShape)
+	CustomPattern func(*Canvas, int, int, *rand.Rand, TreeShape) // Draws the tree instead of the standard pattern
 }
 
-// GetTreeTypeAndShape determines what type of tree to generate with weighted randomness
-func GetTreeTypeAndShape(rng *rand.Rand) (TreeType, TreeShape) {
-	roll := rng.Float64()
+// GetTreeTypeAndShape determines what type of tree to generate, weighted by a biome's
+// tree weights
+func GetTreeTypeAndShape(rng *rand.Rand, weights map[TreeType]float64) (TreeType, TreeShape) {
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	roll := rng.Float64() * total
 	cumulative := 0.0
 
-	// Check each tree type in order of rarity (most common first)
-	treeTypes := []TreeType{NormalTree, TallTree, BushyTree, WideTree, TwinTree, DeadTree, GiantTree, FlowerTree}
-
-	for _, treeType := range treeTypes {
-		cumulative += treeType.GetRarity()
+	// Walk the tree types in a fixed order so the same roll picks the same tree
+	for treeType := NormalTree; treeType <= SpookyTree; treeType++ {
+		cumulative += weights[treeType]
 		if roll < cumulative {
 			return treeType, generateShapeForType(treeType, rng)
 		}
@@ -101,6 +100,16 @@ func generateShapeForType(treeType TreeType, rng *rand.Rand) TreeShape {
 			HasBranches: false,
 			IsSparse:    true, // Some leaves replaced with other blocks
 		}
+	case PalmTree:
+		return TreeShape{
+			TrunkHeight:   5 + rng.Intn(3), // 5-7 blocks
+			CustomPattern: GeneratePalmTree,
+		}
+	case SpookyTree:
+		return TreeShape{
+			TrunkHeight:   4 + rng.Intn(4), // 4-7 blocks
+			CustomPattern: GenerateSpookyTree,
+		}
 	default:
 		return generateShapeForType(NormalTree, rng)
 	}
@@ -0,0 +1,129 @@
+package generation
+
+import (
+	"fmt"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// BlockWrite is one block a structure puts down
+type BlockWrite struct {
+	X, Y  int // World block position
+	Block coretypes.BlockType
+	Soft  bool // Only replaces blocks that aren't solid, like leaves
+}
+
+// Canvas collects the blocks of one structure in world coordinates, so a structure
+// can reach past the edges of the chunk it grows from. Reads see the structure's own
+// blocks on top of the generated terrain.
+type Canvas struct {
+	g       *Generator
+	source  ChunkCoord // Chunk the structures grow from
+	writes  []BlockWrite
+	blocks  map[[2]int]coretypes.BlockType
+	clipped int // Blocks dropped for reaching too far from the source chunk
+}
+
+// newCanvas creates an empty canvas over a generator's terrain for the structures
+// growing from a chunk
+func (g *Generator) newCanvas(source ChunkCoord) *Canvas {
+	return &Canvas{g: g, source: source, blocks: make(map[[2]int]coretypes.BlockType)}
+}
+
+// reaches reports whether a block is at most one chunk past the source chunk.
+// Generating a chunk only plans the structures of its neighbours, so blocks any
+// further away would never be placed; they are dropped here instead.
+func (c *Canvas) reaches(x, y int) bool {
+	coord := coretypes.ChunkCoordOfBlock(x, y)
+	return abs(coord.X-c.source.X) <= 1 && abs(coord.Y-c.source.Y) <= 1
+}
+
+// Get returns the block at a world position
+func (c *Canvas) Get(x, y int) coretypes.BlockType {
+	if block, ok := c.blocks[[2]int{x, y}]; ok {
+		return block
+	}
+	return c.g.terrainBlockAt(x, y, c.g.GetHeightAt(x))
+}
+
+// Set puts a block down, replacing whatever is there
+func (c *Canvas) Set(x, y int, block coretypes.BlockType) {
+	if !c.reaches(x, y) {
+		c.clipped++
+		return
+	}
+	c.blocks[[2]int{x, y}] = block
+	c.writes = append(c.writes, BlockWrite{X: x, Y: y, Block: block})
+}
+
+// Fill puts a block down only where the current block isn't solid
+func (c *Canvas) Fill(x, y int, block coretypes.BlockType) {
+	if c.Get(x, y).IsSolid() {
+		return
+	}
+	if !c.reaches(x, y) {
+		c.clipped++
+		return
+	}
+	c.blocks[[2]int{x, y}] = block
+	c.writes = append(c.writes, BlockWrite{X: x, Y: y, Block: block, Soft: true})
+}
+
+// planStructures decides the trees growing from a chunk and the underground
+// structures placed from it, and returns all of their blocks. Plans depend only on
+// the seed and the chunk, so they are worked out again whenever a chunk they reach
+// into is generated rather than kept around. Structures are logged when announce is
+// set, so each is logged once, with the chunk it grows from.
+func (g *Generator) planStructures(source ChunkCoord, announce bool) []BlockWrite {
+	canvas := g.newCanvas(source)
+	chunkWorldX := source.X * settings.ChunkWidth
+	chunkWorldY := source.Y * settings.ChunkHeight
+	for x := 0; x < settings.ChunkWidth; x++ {
+		worldX := chunkWorldX + x
+		surfaceHeight := g.GetHeightAt(worldX)
+		if surfaceHeight < chunkWorldY || surfaceHeight >= chunkWorldY+settings.ChunkHeight {
+			continue // Trees grow from the chunk holding their root
+		}
+		if biome, ok := g.treeAt(worldX, surfaceHeight); ok {
+			GenerateTreeAtPosition(canvas, worldX, surfaceHeight, g.random(worldX, surfaceHeight, featureTrees), biome, announce)
+		}
+	}
+	g.planUnderground(canvas, source, announce)
+	if announce && canvas.clipped > 0 {
+		fmt.Printf("STRUCTURE: Dropped %d blocks reaching more than a chunk past chunk (%d, %d)\n", canvas.clipped, source.X, source.Y)
+	}
+	return canvas.writes
+}
+
+// treeAt reports whether a tree grows from the surface of a column, and the biome
+// it belongs to
+func (g *Generator) treeAt(worldX, surfaceHeight int) (*Biome, bool) {
+	biome := g.GetSurfaceBiomeAt(worldX)
+	return biome, g.terrainBlockAt(worldX, surfaceHeight, surfaceHeight) == biome.Surface &&
+		g.chance(worldX, surfaceHeight, featureTreePlacement) < biome.TreeChance
+}
+
+// placeStructures applies every structure block that lands in the chunk. Structures
+// are smaller than a chunk, so only the chunk itself and its neighbours can reach
+// in; their plans are applied source chunk by source chunk in a fixed order, so the
+// result doesn't depend on which chunk was generated first, and a chunk that is
+// unloaded and generated again gets the same structures.
+func (g *Generator) placeStructures(chunk *coretypes.Chunk, chunkX, chunkY int) {
+	chunkWorldX := chunkX * settings.ChunkWidth
+	chunkWorldY := chunkY * settings.ChunkHeight
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			for _, write := range g.planStructures(ChunkCoord{X: chunkX + dx, Y: chunkY + dy}, dx == 0 && dy == 0) {
+				x, y := write.X-chunkWorldX, write.Y-chunkWorldY
+				if x < 0 || x >= settings.ChunkWidth || y < 0 || y >= settings.ChunkHeight {
+					continue // Lands in another chunk
+				}
+				if write.Soft && chunk.Blocks[y][x].IsSolid() {
+					continue
+				}
+				chunk.Blocks[y][x] = write.Block
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package generation
+
+import (
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// edgeTree finds a tree rooted in the given column of its chunk that reaches into the
+// neighbouring chunk on side (-1 west, 1 east), and returns the tree's chunk, the
+// neighbour and the tree's blocks that land in the neighbour
+func edgeTree(t *testing.T, g *Generator, column, side int) (source, neighbour ChunkCoord, blocks map[[2]int]coretypes.BlockType) {
+	for cx := -100; cx <= 100; cx++ {
+		worldX := cx*settings.ChunkWidth + column
+		surface := g.GetHeightAt(worldX)
+		biome, ok := g.treeAt(worldX, surface)
+		if !ok {
+			continue
+		}
+		source = ChunkCoord(coretypes.ChunkCoordOfBlock(worldX, surface))
+		neighbour = ChunkCoord{X: source.X + side, Y: source.Y}
+		canvas := g.newCanvas(source)
+		GenerateTreeAtPosition(canvas, worldX, surface, g.random(worldX, surface, featureTrees), biome, false)
+		blocks = map[[2]int]coretypes.BlockType{}
+		for _, write := range canvas.writes {
+			if ChunkCoord(coretypes.ChunkCoordOfBlock(write.X, write.Y)) == neighbour {
+				blocks[[2]int{write.X, write.Y}] = write.Block
+			}
+		}
+		if len(blocks) > 0 {
+			return source, neighbour, blocks
+		}
+	}
+	t.Fatalf("no tree in column %d reaches the next chunk", column)
+	return
+}
+
+func TestTreesAtChunkEdgesDontDependOnLoadOrder(t *testing.T) {
+	const seed = 42
+	generate := func(order ...ChunkCoord) map[ChunkCoord]coretypes.Chunk {
+		g := NewGenerator(seed, DefaultConfig())
+		chunks := map[ChunkCoord]coretypes.Chunk{}
+		for _, c := range order {
+			chunks[c] = g.GenerateChunk(c.X, c.Y)
+		}
+		return chunks
+	}
+
+	for _, edge := range []struct{ column, side int }{{0, -1}, {settings.ChunkWidth - 1, 1}} {
+		source, neighbour, blocks := edgeTree(t, NewGenerator(seed, DefaultConfig()), edge.column, edge.side)
+
+		sourceFirst := generate(source, neighbour)
+		neighbourFirst := generate(neighbour, source)
+		for _, c := range []ChunkCoord{source, neighbour} {
+			if hashChunk(sourceFirst[c]) != hashChunk(neighbourFirst[c]) {
+				t.Errorf("tree at column %d: chunk %v differs depending on load order", edge.column, c)
+			}
+		}
+
+		// The tree really reaches the neighbour, wherever it isn't blocked by terrain
+		chunk := sourceFirst[neighbour]
+		reached := 0
+		for pos, block := range blocks {
+			if _, x, y := coretypes.LocateBlock(pos[0], pos[1]); chunk.Blocks[y][x] == block {
+				reached++
+			}
+		}
+		if reached == 0 {
+			t.Errorf("none of the %d blocks the tree at column %d of %v puts in %v are there", len(blocks), edge.column, source, neighbour)
+		}
+
+		// Generating the neighbour again, as after an unload, gives the same blocks
+		g := NewGenerator(seed, DefaultConfig())
+		first := g.GenerateChunk(neighbour.X, neighbour.Y)
+		g.GenerateChunk(source.X, source.Y)
+		if again := g.GenerateChunk(neighbour.X, neighbour.Y); hashChunk(again) != hashChunk(first) {
+			t.Errorf("regenerating %v changed the tree reaching into it", neighbour)
+		}
+	}
+}
+
+func TestCanvasDropsBlocksBeyondNeighbours(t *testing.T) {
+	canvas := NewGenerator(1, DefaultConfig()).newCanvas(ChunkCoord{X: 0, Y: 1})
+	y := settings.ChunkHeight + 5
+	canvas.Set(-settings.ChunkWidth, y, coretypes.Wood)
+	canvas.Set(2*settings.ChunkWidth-1, y, coretypes.Wood)
+	canvas.Set(-settings.ChunkWidth-1, y, coretypes.Wood)
+	canvas.Set(2*settings.ChunkWidth, y, coretypes.Wood)
+	canvas.Set(0, 3*settings.ChunkHeight, coretypes.Wood)
+	if len(canvas.writes) != 2 || canvas.clipped != 3 {
+		t.Fatalf("kept %d blocks and dropped %d, want 2 kept and 3 dropped", len(canvas.writes), canvas.clipped)
+	}
+}
@@ -6,22 +6,25 @@ import (
 )
 
 // GetSurfaceBlockType determines the surface block type based on biome
-func GetSurfaceBlockType(worldX int) coretypes.BlockType {
-	// Always grass for now - could add biome variation later
-	return coretypes.Grass
+func (g *Generator) GetSurfaceBlockType(worldX int) coretypes.BlockType {
+	return g.GetSurfaceBiomeAt(worldX).Surface
 }
 
 // GetShallowUndergroundBlock determines shallow underground block types
-func GetShallowUndergroundBlock(worldX, worldY int) coretypes.BlockType {
-	terrainNoise := GetTerrainNoise()
+func (g *Generator) GetShallowUndergroundBlock(worldX, worldY int) coretypes.BlockType {
+	subsurface := g.GetSurfaceBiomeAt(worldX).Subsurface
+	if subsurface != coretypes.Dirt {
+		return subsurface
+	}
+
+	terrainNoise := g.terrainNoise
 
-	// Biome-based surface variation
+	// Clay deposits break up the dirt in patches
 	biomeNoise := terrainNoise.Noise2D(float64(worldX)/settings.TreeBiomeNoiseScale, 0)
 	noiseVal := terrainNoise.Noise2D(float64(worldX)/settings.TreeNoiseScale, float64(worldY)/settings.TreeNoiseScale)
 
 	if biomeNoise > settings.TreeClayBiomeThresh && noiseVal > settings.TreeClayNoiseThresh {
-		return coretypes.Clay // Clay deposits in certain biomes
-	} else {
-		return coretypes.Dirt // Normal dirt layer under grass
+		return coretypes.Clay
 	}
+	return coretypes.Dirt
 }
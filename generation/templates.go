@@ -0,0 +1,266 @@
+package generation
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+//go:embed structures.json
+var defaultTemplatesJSON []byte
+
+// templates is the underground structure set in use, and the block registry it was built against
+var (
+	templates       []*Template
+	templatesBlocks *coretypes.BlockRegistry
+	templatesMutex  sync.Mutex
+)
+
+// CaveRule says how a template must sit relative to caves
+type CaveRule string
+
+const (
+	CaveAny  CaveRule = "any"  // Placed whatever is around it
+	CaveNear CaveRule = "near" // Needs open cave cells in or around its footprint
+	CaveNone CaveRule = "none" // Needs solid ground all around, e.g. something buried
+)
+
+// Template is an underground structure drawn from a block pattern. Each pattern
+// character is looked up in the palette; spaces leave the terrain as it is.
+type Template struct {
+	Name               string
+	Pattern            []string // Rows from top to bottom, all the same width
+	Palette            map[rune]coretypes.BlockType
+	AnchorX, AnchorY   int // Pattern cell placed at the chosen position
+	Weight             float64
+	MinDepth, MaxDepth int // Blocks below the surface the anchor may sit
+	Cave               CaveRule
+}
+
+// templateJSON is a template as written in the structure file, with blocks named
+type templateJSON struct {
+	Name    string            `json:"name"`
+	Weight  float64           `json:"weight"`
+	Depth   [2]int            `json:"depth"`
+	Cave    CaveRule          `json:"cave"`
+	Anchor  [2]int            `json:"anchor"`
+	Palette map[string]string `json:"palette"`
+	Pattern []string          `json:"pattern"`
+}
+
+// ParseTemplates reads underground structure templates from JSON. Templates that
+// don't make sense or name blocks that don't exist are left out and reported in the
+// error; the rest are still usable.
+func ParseTemplates(data []byte) ([]*Template, error) {
+	var parsed struct {
+		Templates []templateJSON `json:"templates"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+
+	var parsedTemplates []*Template
+	var errs []error
+	for _, raw := range parsed.Templates {
+		t, err := parseTemplate(raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("template %q: %w", raw.Name, err))
+			continue
+		}
+		parsedTemplates = append(parsedTemplates, t)
+	}
+	return parsedTemplates, errors.Join(errs...)
+}
+
+// parseTemplate checks a template's pattern and resolves its palette
+func parseTemplate(raw templateJSON) (*Template, error) {
+	if len(raw.Pattern) == 0 {
+		return nil, errors.New("empty pattern")
+	}
+	width := len([]rune(raw.Pattern[0]))
+	for _, row := range raw.Pattern {
+		if len([]rune(row)) != width {
+			return nil, errors.New("pattern rows must all be the same width")
+		}
+	}
+	// Structures reach at most one chunk past the chunk they are placed from
+	if width > settings.ChunkWidth || len(raw.Pattern) > settings.ChunkHeight {
+		return nil, fmt.Errorf("pattern is larger than a chunk (%dx%d)", settings.ChunkWidth, settings.ChunkHeight)
+	}
+	if raw.Anchor[0] < 0 || raw.Anchor[0] >= width || raw.Anchor[1] < 0 || raw.Anchor[1] >= len(raw.Pattern) {
+		return nil, errors.New("anchor is outside the pattern")
+	}
+	if raw.Weight <= 0 {
+		return nil, errors.New("weight must be above 0")
+	}
+	if raw.Depth[1] < raw.Depth[0] {
+		return nil, errors.New("depth must be smallest first")
+	}
+	switch raw.Cave {
+	case CaveAny, CaveNear, CaveNone:
+	case "":
+		raw.Cave = CaveAny
+	default:
+		return nil, fmt.Errorf("unknown cave rule %q", raw.Cave)
+	}
+
+	t := &Template{
+		Name:     raw.Name,
+		Pattern:  raw.Pattern,
+		Palette:  make(map[rune]coretypes.BlockType, len(raw.Palette)),
+		AnchorX:  raw.Anchor[0],
+		AnchorY:  raw.Anchor[1],
+		Weight:   raw.Weight,
+		MinDepth: raw.Depth[0],
+		MaxDepth: raw.Depth[1],
+		Cave:     raw.Cave,
+	}
+	for key, name := range raw.Palette {
+		runes := []rune(key)
+		if len(runes) != 1 || runes[0] == ' ' {
+			return nil, fmt.Errorf("palette key %q must be one character other than a space", key)
+		}
+		block, ok := coretypes.BlockTypeByName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown block %q", name)
+		}
+		t.Palette[runes[0]] = block
+	}
+	for _, row := range raw.Pattern {
+		for _, r := range row {
+			if _, ok := t.Palette[r]; !ok && r != ' ' {
+				return nil, fmt.Errorf("pattern character %q is not in the palette", r)
+			}
+		}
+	}
+	return t, nil
+}
+
+// Templates returns the built-in underground structure templates, rebuilding them if
+// the block registry has changed since
+func Templates() []*Template {
+	templatesMutex.Lock()
+	defer templatesMutex.Unlock()
+	if templates == nil || templatesBlocks != coretypes.Blocks() {
+		parsed, err := ParseTemplates(defaultTemplatesJSON)
+		if err != nil {
+			fmt.Printf("STRUCTURE: Skipping templates: %v\n", err)
+		}
+		if parsed == nil {
+			parsed = []*Template{}
+		}
+		templates, templatesBlocks = parsed, coretypes.Blocks()
+	}
+	return templates
+}
+
+// Width returns the width of the template's pattern
+func (t *Template) Width() int {
+	return len([]rune(t.Pattern[0]))
+}
+
+// Height returns the height of the template's pattern
+func (t *Template) Height() int {
+	return len(t.Pattern)
+}
+
+// Draw puts the template down with its anchor at a world position
+func (t *Template) Draw(canvas *Canvas, x, y int) {
+	left, top := x-t.AnchorX, y-t.AnchorY
+	for row, line := range t.Pattern {
+		for col, r := range []rune(line) {
+			if r == ' ' {
+				continue
+			}
+			canvas.Set(left+col, top+row, t.Palette[r])
+		}
+	}
+}
+
+// templateFits reports whether a template's placement rules allow its anchor at a
+// world position: the anchor's depth band, the whole footprint staying underground,
+// and its cave rule
+func (g *Generator) templateFits(t *Template, x, y int) bool {
+	if depth := y - g.GetHeightAt(x); depth < t.MinDepth || depth > t.MaxDepth {
+		return false
+	}
+	left, top := x-t.AnchorX, y-t.AnchorY
+	for col := left - 1; col <= left+t.Width(); col++ {
+		if top-1 <= g.GetHeightAt(col)+1 {
+			return false // Would break through the surface
+		}
+	}
+	if t.Cave == CaveAny {
+		return true
+	}
+
+	// Count open cells in the footprint and the ring around it
+	open := 0
+	for row := top - 1; row <= top+t.Height(); row++ {
+		for col := left - 1; col <= left+t.Width(); col++ {
+			if !g.terrainBlockAt(col, row, g.GetHeightAt(col)).IsSolid() {
+				open++
+			}
+		}
+	}
+	if t.Cave == CaveNone {
+		return open == 0
+	}
+	return open >= settings.StructureCaveOpen
+}
+
+// planUnderground tries to place underground structures from a chunk. Each attempt
+// draws its position and chance from the chunk's own random stream, then picks by
+// weight among the templates whose depth band holds that position, so the same seed
+// always places the same structures. Placements are logged when announce is set.
+func (g *Generator) planUnderground(canvas *Canvas, source ChunkCoord, announce bool) {
+	all := Templates()
+	if len(all) == 0 {
+		return
+	}
+
+	rng := g.random(source.X, source.Y, featureUnderground)
+	candidates := make([]*Template, 0, len(all))
+	for attempt := 0; attempt < settings.StructureAttempts; attempt++ {
+		x := source.X*settings.ChunkWidth + rng.Intn(settings.ChunkWidth)
+		y := source.Y*settings.ChunkHeight + rng.Intn(settings.ChunkHeight)
+		roll, pick := rng.Float64(), rng.Float64()
+		if roll >= settings.StructureChance {
+			continue
+		}
+
+		depth := y - g.GetHeightAt(x)
+		candidates = candidates[:0]
+		total := 0.0
+		for _, t := range all {
+			if depth >= t.MinDepth && depth <= t.MaxDepth {
+				candidates = append(candidates, t)
+				total += t.Weight
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		pick *= total
+		template := candidates[len(candidates)-1]
+		for _, t := range candidates {
+			if pick < t.Weight {
+				template = t
+				break
+			}
+			pick -= t.Weight
+		}
+		if g.templateFits(template, x, y) {
+			if announce {
+				fmt.Printf("STRUCTURE: Placing %s at (%d, %d)\n", template.Name, x, y)
+			}
+			template.Draw(canvas, x, y)
+		}
+	}
+}
@@ -4,68 +4,59 @@ import (
 	"math/rand"
 
 	"github.com/KdntNinja/webcraft/coretypes"
-	"github.com/KdntNinja/webcraft/settings"
 )
 
 // generateStandardTree creates the basic tree structure
-func generateStandardTree(chunk *coretypes.Chunk, x, surfaceChunkY int, rng *rand.Rand, treeType TreeType, shape TreeShape) {
+func generateStandardTree(canvas *Canvas, x, surfaceY int, rng *rand.Rand, treeType TreeType, shape TreeShape) {
 	// Place trunk
 	trunkBlock := treeType.GetTrunkBlock()
 	for trunkLevel := 0; trunkLevel < shape.TrunkHeight; trunkLevel++ {
-		trunkChunkY := surfaceChunkY - trunkLevel
-		if trunkChunkY >= 0 && trunkChunkY < settings.ChunkHeight {
-			chunk.Blocks[trunkChunkY][x] = trunkBlock
-		}
+		canvas.Set(x, surfaceY-trunkLevel, trunkBlock)
 	}
 
 	// Place branches if the tree has them
 	if shape.HasBranches {
-		generateBranches(chunk, x, surfaceChunkY, shape, rng)
+		generateBranches(canvas, x, surfaceY, shape, rng)
 	}
 
 	// Place leaves (only if not a dead tree)
 	if treeType != DeadTree && shape.LeafLayers > 0 {
-		generateLeaves(chunk, x, surfaceChunkY, shape, treeType, rng)
+		generateLeaves(canvas, x, surfaceY, shape, treeType, rng)
 	}
 }
 
 // generateBranches creates branch structures
-func generateBranches(chunk *coretypes.Chunk, x, surfaceChunkY int, shape TreeShape, rng *rand.Rand) {
+func generateBranches(canvas *Canvas, x, surfaceY int, shape TreeShape, rng *rand.Rand) {
 	// Add branches at different heights
 	branchHeight := shape.TrunkHeight / 2
 	for i := 0; i < 2; i++ {
-		branchY := surfaceChunkY - branchHeight - i
-		if branchY >= 0 && branchY < settings.ChunkHeight {
-			// Left branch
-			if x > 0 && rng.Float64() < 0.7 {
-				chunk.Blocks[branchY][x-1] = coretypes.Wood
-				if shape.BranchLength > 1 && x > 1 && rng.Float64() < 0.5 {
-					chunk.Blocks[branchY][x-2] = coretypes.Wood
-				}
+		branchY := surfaceY - branchHeight - i
+		// Left branch
+		if rng.Float64() < 0.7 {
+			canvas.Set(x-1, branchY, coretypes.Wood)
+			if shape.BranchLength > 1 && rng.Float64() < 0.5 {
+				canvas.Set(x-2, branchY, coretypes.Wood)
 			}
-			// Right branch
-			if x < settings.ChunkWidth-1 && rng.Float64() < 0.7 {
-				chunk.Blocks[branchY][x+1] = coretypes.Wood
-				if shape.BranchLength > 1 && x < settings.ChunkWidth-2 && rng.Float64() < 0.5 {
-					chunk.Blocks[branchY][x+2] = coretypes.Wood
-				}
+		}
+		// Right branch
+		if rng.Float64() < 0.7 {
+			canvas.Set(x+1, branchY, coretypes.Wood)
+			if shape.BranchLength > 1 && rng.Float64() < 0.5 {
+				canvas.Set(x+2, branchY, coretypes.Wood)
 			}
 		}
 	}
 }
 
 // generateLeaves creates natural-looking leaf canopies with proper distribution
-func generateLeaves(chunk *coretypes.Chunk, x, surfaceChunkY int, shape TreeShape, treeType TreeType, rng *rand.Rand) {
+func generateLeaves(canvas *Canvas, x, surfaceY int, shape TreeShape, treeType TreeType, rng *rand.Rand) {
 	leafStartLevel := shape.TrunkHeight
 	leafEndLevel := shape.TrunkHeight + shape.LeafLayers
 	primaryLeafBlock := treeType.GetLeafBlock()
 
 	// Generate leaves layer by layer from bottom to top
 	for leafLevel := leafStartLevel; leafLevel <= leafEndLevel; leafLevel++ {
-		leafChunkY := surfaceChunkY - leafLevel
-		if leafChunkY < 0 || leafChunkY >= settings.ChunkHeight {
-			continue
-		}
+		leafY := surfaceY - leafLevel
 
 		// Calculate layer properties
 		layerFromBottom := leafLevel - leafStartLevel
@@ -74,28 +65,28 @@ func generateLeaves(chunk *coretypes.Chunk, x, surfaceChunkY int, shape TreeShap
 		// Generate natural leaf pattern based on tree type and layer
 		switch treeType {
 		case NormalTree, TallTree:
-			generateNaturalLeafLayer(chunk, x, leafChunkY, layerFromBottom, totalLayers, shape, primaryLeafBlock, rng)
+			generateNaturalLeafLayer(canvas, x, leafY, layerFromBottom, totalLayers, shape, primaryLeafBlock, rng)
 		case BushyTree:
-			generateBushyLeafLayer(chunk, x, leafChunkY, layerFromBottom, totalLayers, shape, primaryLeafBlock, rng)
+			generateBushyLeafLayer(canvas, x, leafY, layerFromBottom, totalLayers, shape, primaryLeafBlock, rng)
 		case WideTree:
-			generateWideLeafLayer(chunk, x, leafChunkY, layerFromBottom, totalLayers, shape, primaryLeafBlock, rng)
+			generateWideLeafLayer(canvas, x, leafY, layerFromBottom, totalLayers, shape, primaryLeafBlock, rng)
 		case TwinTree:
-			generateTwinLeafLayer(chunk, x, leafChunkY, layerFromBottom, totalLayers, shape, primaryLeafBlock, rng)
+			generateTwinLeafLayer(canvas, x, leafY, layerFromBottom, totalLayers, shape, primaryLeafBlock, rng)
 		case FlowerTree:
-			generateFlowerLeafLayer(chunk, x, leafChunkY, layerFromBottom, totalLayers, shape, primaryLeafBlock, rng)
+			generateFlowerLeafLayer(canvas, x, leafY, layerFromBottom, totalLayers, shape, primaryLeafBlock, rng)
 		default:
-			generateNaturalLeafLayer(chunk, x, leafChunkY, layerFromBottom, totalLayers, shape, primaryLeafBlock, rng)
+			generateNaturalLeafLayer(canvas, x, leafY, layerFromBottom, totalLayers, shape, primaryLeafBlock, rng)
 		}
 	}
 
 	// Add branch-end leaves if tree has branches
 	if shape.HasBranches {
-		generateBranchLeaves(chunk, x, surfaceChunkY, shape, primaryLeafBlock, rng)
+		generateBranchLeaves(canvas, x, surfaceY, shape, primaryLeafBlock, rng)
 	}
 }
 
 // generateNaturalLeafLayer creates a natural circular/oval leaf pattern
-func generateNaturalLeafLayer(chunk *coretypes.Chunk, centerX, chunkY, layerFromBottom, totalLayers int, shape TreeShape, leafBlock coretypes.BlockType, rng *rand.Rand) {
+func generateNaturalLeafLayer(canvas *Canvas, centerX, y, layerFromBottom, totalLayers int, shape TreeShape, leafBlock coretypes.BlockType, rng *rand.Rand) {
 	// Calculate radius based on layer position (wider in middle)
 	progress := float64(layerFromBottom) / float64(totalLayers)
 
@@ -116,9 +107,6 @@ func generateNaturalLeafLayer(chunk *coretypes.Chunk, centerX, chunkY, layerFrom
 	maxRadius := int(radius) + 1
 	for dx := -maxRadius; dx <= maxRadius; dx++ {
 		leafX := centerX + dx
-		if leafX < 0 || leafX >= settings.ChunkWidth {
-			continue
-		}
 
 		// Calculate distance from center
 		distance := float64(abs(dx))
@@ -141,21 +129,18 @@ func generateNaturalLeafLayer(chunk *coretypes.Chunk, centerX, chunkY, layerFrom
 		}
 
 		if leafProbability > 0.4 && rng.Float64() < leafProbability {
-			chunk.Blocks[chunkY][leafX] = leafBlock
+			placeLeaves(canvas, leafX, y, leafBlock)
 		}
 	}
 }
 
 // generateBushyLeafLayer creates dense, compact leaf patterns
-func generateBushyLeafLayer(chunk *coretypes.Chunk, centerX, chunkY, layerFromBottom, totalLayers int, shape TreeShape, leafBlock coretypes.BlockType, rng *rand.Rand) {
+func generateBushyLeafLayer(canvas *Canvas, centerX, y, layerFromBottom, totalLayers int, shape TreeShape, leafBlock coretypes.BlockType, rng *rand.Rand) {
 	// Bushy trees have consistent width with high density
 	width := shape.LeafWidth + 1
 
 	for dx := -width; dx <= width; dx++ {
 		leafX := centerX + dx
-		if leafX < 0 || leafX >= settings.ChunkWidth {
-			continue
-		}
 
 		// High density everywhere except very edges
 		leafProbability := 0.9
@@ -167,13 +152,13 @@ func generateBushyLeafLayer(chunk *coretypes.Chunk, centerX, chunkY, layerFromBo
 		leafProbability += rng.Float64()*0.2 - 0.1
 
 		if rng.Float64() < leafProbability {
-			chunk.Blocks[chunkY][leafX] = leafBlock
+			placeLeaves(canvas, leafX, y, leafBlock)
 		}
 	}
 }
 
 // generateWideLeafLayer creates spreading, wide leaf patterns
-func generateWideLeafLayer(chunk *coretypes.Chunk, centerX, chunkY, layerFromBottom, totalLayers int, shape TreeShape, leafBlock coretypes.BlockType, rng *rand.Rand) {
+func generateWideLeafLayer(canvas *Canvas, centerX, y, layerFromBottom, totalLayers int, shape TreeShape, leafBlock coretypes.BlockType, rng *rand.Rand) {
 	// Wide trees get progressively wider, then narrow at top
 	progress := float64(layerFromBottom) / float64(totalLayers)
 
@@ -192,9 +177,6 @@ func generateWideLeafLayer(chunk *coretypes.Chunk, centerX, chunkY, layerFromBot
 
 	for dx := -width; dx <= width; dx++ {
 		leafX := centerX + dx
-		if leafX < 0 || leafX >= settings.ChunkWidth {
-			continue
-		}
 
 		// Probability decreases with distance from center
 		distance := float64(abs(dx))
@@ -204,93 +186,79 @@ func generateWideLeafLayer(chunk *coretypes.Chunk, centerX, chunkY, layerFromBot
 		leafProbability += rng.Float64()*0.3 - 0.15
 
 		if rng.Float64() < leafProbability {
-			chunk.Blocks[chunkY][leafX] = leafBlock
+			placeLeaves(canvas, leafX, y, leafBlock)
 		}
 	}
 }
 
 // generateTwinLeafLayer creates two separate leaf clusters
-func generateTwinLeafLayer(chunk *coretypes.Chunk, centerX, chunkY, layerFromBottom, totalLayers int, shape TreeShape, leafBlock coretypes.BlockType, rng *rand.Rand) {
+func generateTwinLeafLayer(canvas *Canvas, centerX, y, layerFromBottom, totalLayers int, shape TreeShape, leafBlock coretypes.BlockType, rng *rand.Rand) {
 	// Two clusters separated by 2-3 blocks
 	separation := 2 + rng.Intn(2) // 2 or 3 blocks apart
 
 	// Left cluster
 	leftCenter := centerX - separation/2 - 1
-	generateSmallCluster(chunk, leftCenter, chunkY, shape.LeafWidth, leafBlock, rng)
+	generateSmallCluster(canvas, leftCenter, y, shape.LeafWidth, leafBlock, rng)
 
 	// Right cluster
 	rightCenter := centerX + separation/2 + 1
-	generateSmallCluster(chunk, rightCenter, chunkY, shape.LeafWidth, leafBlock, rng)
+	generateSmallCluster(canvas, rightCenter, y, shape.LeafWidth, leafBlock, rng)
 }
 
 // generateFlowerLeafLayer creates varied leaf patterns with flower blocks
-func generateFlowerLeafLayer(chunk *coretypes.Chunk, centerX, chunkY, layerFromBottom, totalLayers int, shape TreeShape, leafBlock coretypes.BlockType, rng *rand.Rand) {
+func generateFlowerLeafLayer(canvas *Canvas, centerX, y, layerFromBottom, totalLayers int, shape TreeShape, leafBlock coretypes.BlockType, rng *rand.Rand) {
 	// Similar to natural but with flower block variations
-	generateNaturalLeafLayer(chunk, centerX, chunkY, layerFromBottom, totalLayers, shape, leafBlock, rng)
+	generateNaturalLeafLayer(canvas, centerX, y, layerFromBottom, totalLayers, shape, leafBlock, rng)
 
 	// Add flower blocks (clay) scattered throughout
 	width := shape.LeafWidth + 1
 	for dx := -width; dx <= width; dx++ {
 		leafX := centerX + dx
-		if leafX < 0 || leafX >= settings.ChunkWidth {
-			continue
-		}
 
 		// 15% chance to replace leaf with flower block
-		if chunk.Blocks[chunkY][leafX] == leafBlock && rng.Float64() < 0.15 {
-			chunk.Blocks[chunkY][leafX] = coretypes.Clay // "Flowers"
+		if canvas.Get(leafX, y) == leafBlock && rng.Float64() < 0.15 {
+			canvas.Set(leafX, y, coretypes.Clay) // "Flowers"
 		}
 	}
 }
 
 // generateSmallCluster creates a small compact leaf cluster
-func generateSmallCluster(chunk *coretypes.Chunk, centerX, chunkY, maxWidth int, leafBlock coretypes.BlockType, rng *rand.Rand) {
+func generateSmallCluster(canvas *Canvas, centerX, y, maxWidth int, leafBlock coretypes.BlockType, rng *rand.Rand) {
 	width := 1 + rng.Intn(maxWidth)
 
 	for dx := -width; dx <= width; dx++ {
 		leafX := centerX + dx
-		if leafX < 0 || leafX >= settings.ChunkWidth {
-			continue
-		}
 
 		// High probability for small clusters
 		if rng.Float64() < 0.8 {
-			chunk.Blocks[chunkY][leafX] = leafBlock
+			placeLeaves(canvas, leafX, y, leafBlock)
 		}
 	}
 }
 
 // generateBranchLeaves adds leaves at the end of branches
-func generateBranchLeaves(chunk *coretypes.Chunk, trunkX, surfaceChunkY int, shape TreeShape, leafBlock coretypes.BlockType, rng *rand.Rand) {
+func generateBranchLeaves(canvas *Canvas, trunkX, surfaceY int, shape TreeShape, leafBlock coretypes.BlockType, rng *rand.Rand) {
 	branchHeight := shape.TrunkHeight / 2
 
 	for i := 0; i < 2; i++ {
-		branchY := surfaceChunkY - branchHeight - i
-		if branchY < 0 || branchY >= settings.ChunkHeight {
-			continue
-		}
+		branchY := surfaceY - branchHeight - i
 
 		// Check for branches and add leaves at their ends
 		for dx := -shape.BranchLength - 1; dx <= shape.BranchLength+1; dx++ {
 			branchX := trunkX + dx
-			if branchX < 0 || branchX >= settings.ChunkWidth {
-				continue
-			}
 
 			// If there's a branch block, potentially add leaves around it
-			if chunk.Blocks[branchY][branchX] == coretypes.Wood && abs(dx) > 1 {
+			if canvas.Get(branchX, branchY) == coretypes.Wood && abs(dx) > 1 {
 				// Add leaves above branch
-				if branchY > 0 && rng.Float64() < 0.7 {
-					chunk.Blocks[branchY-1][branchX] = leafBlock
+				if rng.Float64() < 0.7 {
+					placeLeaves(canvas, branchX, branchY-1, leafBlock)
 				}
 				// Add leaves beside branch end
 				if abs(dx) >= shape.BranchLength && rng.Float64() < 0.5 {
-					if branchX > 0 && branchX < settings.ChunkWidth-1 {
-						if rng.Float64() < 0.5 {
-							chunk.Blocks[branchY][branchX-1] = leafBlock
-						} else {
-							chunk.Blocks[branchY][branchX+1] = leafBlock
-						}
+					if rng.Float64() < 0.5 {
+						placeLeaves(canvas, branchX-1, branchY, leafBlock)
+					} else {
+						placeLeaves(canvas, branchX+1, branchY, leafBlock)
 					}
 				}
 			}
@@ -298,6 +266,12 @@ func generateBranchLeaves(chunk *coretypes.Chunk, trunkX, surfaceChunkY int, sha
 	}
 }
 
+// placeLeaves puts a leaf block down without overwriting solid blocks such as trunks
+// and branches
+func placeLeaves(canvas *Canvas, x, y int, leafBlock coretypes.BlockType) {
+	canvas.Fill(x, y, leafBlock)
+}
+
 // abs returns the absolute value of an integer
 func abs(x int) int {
 	if x < 0 {
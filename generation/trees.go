@@ -3,24 +3,25 @@ package generation
 import (
 	"fmt"
 	"math/rand"
-
-	"github.com/KdntNinja/webcraft/coretypes"
 )
 
-// GenerateTreeAtPosition generates a tree at the specified position in a chunk
-func GenerateTreeAtPosition(chunk *coretypes.Chunk, x, surfaceChunkY int, rng *rand.Rand) {
-	treeType, shape := GetTreeTypeAndShape(rng)
+// GenerateTreeAtPosition draws one of a biome's trees growing from a surface block,
+// in world coordinates. The tree is logged when announce is set.
+func GenerateTreeAtPosition(canvas *Canvas, x, surfaceY int, rng *rand.Rand, biome *Biome, announce bool) {
+	treeType, shape := GetTreeTypeAndShape(rng, biome.Trees)
 	shape.ValidateShape()
 
-	fmt.Printf("TREE_DEBUG: Placing %v tree (height %d) at x=%d, surfaceChunkY=%d\n",
-		treeType, shape.TrunkHeight, x, surfaceChunkY)
+	if announce {
+		fmt.Printf("TREE_DEBUG: Placing %v tree (height %d) at x=%d, surfaceY=%d\n",
+			treeType, shape.TrunkHeight, x, surfaceY)
+	}
 
 	// Use custom pattern if available
 	if shape.CustomPattern != nil {
-		shape.CustomPattern(chunk, x, surfaceChunkY, rng, shape)
+		shape.CustomPattern(canvas, x, surfaceY, rng, shape)
 		return
 	}
 
 	// Generate standard tree pattern
-	generateStandardTree(chunk, x, surfaceChunkY, rng, treeType, shape)
+	generateStandardTree(canvas, x, surfaceY, rng, treeType, shape)
 }
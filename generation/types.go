@@ -14,6 +14,8 @@ const (
 	GiantTree  // Very rare: massive tree
 	DeadTree   // Rare: only trunk, no leaves
 	FlowerTree // Rare: leaves mixed with other blocks
+	PalmTree   // Desert: tall bare trunk with fronds at the top
+	SpookyTree // Spooky biome: twisted leafless branches
 )
 
 // String returns the name of the tree type
@@ -35,6 +37,10 @@ func (t TreeType) String() string {
 		return "Dead"
 	case FlowerTree:
 		return "Flower"
+	case PalmTree:
+		return "Palm"
+	case SpookyTree:
+		return "Spooky"
 	default:
 		return "Unknown"
 	}
@@ -67,7 +73,7 @@ func (t TreeType) GetRarity() float64 {
 // GetLeafBlock returns the primary leaf block type for this tree
 func (t TreeType) GetLeafBlock() coretypes.BlockType {
 	switch t {
-	case DeadTree:
+	case DeadTree, SpookyTree:
 		return coretypes.Air // Dead trees have no leaves
 	default:
 		return coretypes.Leaves
@@ -1,29 +1,27 @@
 package generation
 
 import (
-	"math/rand"
-
 	"github.com/KdntNinja/webcraft/coretypes"
 	"github.com/KdntNinja/webcraft/settings"
 )
 
 // GetUndergroundBlock determines the block type for underground positions
-func GetUndergroundBlock(worldX, worldY, surfaceHeight int, rng *rand.Rand) coretypes.BlockType {
+func (g *Generator) GetUndergroundBlock(worldX, worldY, surfaceHeight int) coretypes.BlockType {
 	depthFromSurface := worldY - surfaceHeight
-	terrainNoise := GetTerrainNoise()
+	terrainNoise := g.terrainNoise
 
 	// Check for ore veins with enhanced vein generation
-	oreType := GetOreType(worldX, worldY)
+	oreType := g.GetOreType(worldX, worldY)
 	if oreType > 0 {
 		// Base ore chance from settings, but increased for visibility
 		oreChance := settings.OreVeinChance * 4.0 // Significantly increased
 
 		// Check if this position extends an existing ore vein (makes ores cluster)
-		if IsOreVeinExtension(worldX, worldY, oreType) {
+		if g.IsOreVeinExtension(worldX, worldY, oreType) {
 			oreChance *= 3.0 // Much more likely to place ore near other ore
 		}
 
-		if rng.Float64() < oreChance {
+		if g.chance(worldX, worldY, featureOres) < oreChance {
 			switch oreType {
 			case 1:
 				return coretypes.CopperOre
@@ -70,7 +68,7 @@ func GetUndergroundBlock(worldX, worldY, surfaceHeight int, rng *rand.Rand) core
 		}
 		// Ash pockets in deeper stone
 		stoneVariation := terrainNoise.Noise2D(float64(worldX)/15.0, float64(worldY)/15.0)
-		if stoneVariation > 0.4 && depthFromSurface > 15 && rng.Float64() < 0.10 {
+		if stoneVariation > 0.4 && depthFromSurface > 15 && g.chance(worldX, worldY, featureAsh) < 0.10 {
 			return coretypes.Ash
 		}
 		return coretypes.Stone
@@ -80,7 +78,7 @@ func GetUndergroundBlock(worldX, worldY, surfaceHeight int, rng *rand.Rand) core
 
 		if deepNoise > 0.5 {
 			return coretypes.Ash
-		} else if deepNoise < -0.3 && rng.Float64() < 0.2 {
+		} else if deepNoise < -0.3 && g.chance(worldX, worldY, featureDeepWater) < 0.2 {
 			// Rare water pockets in deep areas
 			return coretypes.Water
 		} else {
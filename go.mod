@@ -4,6 +4,7 @@ go 1.24.3
 
 require (
 	github.com/aquilax/go-perlin v1.1.0
+	github.com/coder/websocket v1.8.13
 	github.com/ebitenui/ebitenui v0.6.2
 	github.com/hajimehoshi/ebiten/v2 v2.8.8
 	github.com/solarlune/resolv v0.8.1
@@ -0,0 +1,46 @@
+package input
+
+import "fmt"
+
+// Action is a named game control that bindings map physical inputs onto
+type Action string
+
+const (
+	MoveLeft   Action = "move_left"
+	MoveRight  Action = "move_right"
+	Jump       Action = "jump"
+	Sneak      Action = "sneak"
+	Sprint     Action = "sprint"
+	Break      Action = "break"
+	Place      Action = "place"
+	Attack     Action = "attack"
+	HotbarNext Action = "hotbar_next"
+	HotbarPrev Action = "hotbar_prev"
+)
+
+// HotbarSlots is the number of hotbar slots that have a direct-select action
+const HotbarSlots = 9
+
+// HotbarSlot returns the action that selects the given hotbar slot (0-based)
+func HotbarSlot(slot int) Action {
+	return Action(fmt.Sprintf("hotbar_%d", slot+1))
+}
+
+// AllActions returns every action in a stable order
+func AllActions() []Action {
+	actions := []Action{MoveLeft, MoveRight, Jump, Sneak, Sprint, Break, Place, Attack, HotbarNext, HotbarPrev}
+	for i := 0; i < HotbarSlots; i++ {
+		actions = append(actions, HotbarSlot(i))
+	}
+	return actions
+}
+
+// IsValid reports whether a is a known action
+func (a Action) IsValid() bool {
+	for _, known := range AllActions() {
+		if a == known {
+			return true
+		}
+	}
+	return false
+}
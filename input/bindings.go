@@ -0,0 +1,102 @@
+package input
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+//go:embed default_bindings.json
+var defaultBindingsJSON []byte
+
+// Bindings maps actions to physical inputs, by device. Input names are device
+// specific: ebiten key names for the keyboard ("A", "Space", "ArrowLeft"), mouse
+// buttons and wheel directions ("Left", "WheelUp") and standard-layout gamepad
+// buttons ("A", "RB", "DPadLeft").
+type Bindings struct {
+	Keyboard        map[Action][]string `json:"keyboard"`
+	Mouse           map[Action][]string `json:"mouse"`
+	Gamepad         map[Action][]string `json:"gamepad"`
+	GamepadDeadzone float64             `json:"gamepadDeadzone"` // Stick deflection ignored around the centre
+	CursorSpeed     float64             `json:"cursorSpeed"`     // Virtual cursor pixels per tick at full deflection
+}
+
+// DefaultBindings returns the built-in control scheme
+func DefaultBindings() *Bindings {
+	b, err := parseBindings(defaultBindingsJSON)
+	if err != nil {
+		panic(fmt.Sprintf("input: invalid default bindings: %v", err))
+	}
+	return b
+}
+
+// ParseBindings reads bindings from JSON. Devices or actions missing from the
+// data keep their default bindings, so a config file only needs the overrides.
+func ParseBindings(data []byte) (*Bindings, error) {
+	parsed, err := parseBindings(data)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := DefaultBindings()
+	mergeDevice(merged.Keyboard, parsed.Keyboard)
+	mergeDevice(merged.Mouse, parsed.Mouse)
+	mergeDevice(merged.Gamepad, parsed.Gamepad)
+	if parsed.GamepadDeadzone > 0 {
+		merged.GamepadDeadzone = parsed.GamepadDeadzone
+	}
+	if parsed.CursorSpeed > 0 {
+		merged.CursorSpeed = parsed.CursorSpeed
+	}
+	return merged, nil
+}
+
+// parseBindings decodes and validates bindings without applying defaults
+func parseBindings(data []byte) (*Bindings, error) {
+	var parsed Bindings
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("parsing bindings: %w", err)
+	}
+	for _, device := range []map[Action][]string{parsed.Keyboard, parsed.Mouse, parsed.Gamepad} {
+		for action := range device {
+			if !action.IsValid() {
+				return nil, fmt.Errorf("unknown action %q", action)
+			}
+		}
+	}
+	if err := parsed.CheckDeadzone(); err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// CheckDeadzone rejects gamepad deadzones outside [0, 1); a deadzone of 1 or more
+// would swallow the whole stick and divide by zero when rescaling
+func (b *Bindings) CheckDeadzone() error {
+	if !(b.GamepadDeadzone >= 0 && b.GamepadDeadzone < 1) {
+		return fmt.Errorf("gamepad deadzone %v is outside [0, 1)", b.GamepadDeadzone)
+	}
+	return nil
+}
+
+// mergeDevice replaces the default bindings of every action present in overrides
+func mergeDevice(dst, overrides map[Action][]string) {
+	for action, names := range overrides {
+		dst[action] = names
+	}
+}
+
+// LoadBindingsFile reads bindings from a JSON file on disk
+func LoadBindingsFile(path string) (*Bindings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBindings(data)
+}
+
+// Marshal encodes the bindings as indented JSON, suitable for writing a config file
+func (b *Bindings) Marshal() ([]byte, error) {
+	return json.MarshalIndent(b, "", "  ")
+}
@@ -0,0 +1,28 @@
+package input
+
+import "testing"
+
+func TestParseBindingsDeadzone(t *testing.T) {
+	tests := []struct {
+		json string
+		want float64
+		ok   bool
+	}{
+		{`{}`, DefaultBindings().GamepadDeadzone, true},
+		{`{"gamepadDeadzone": 0.1}`, 0.1, true},
+		{`{"gamepadDeadzone": 0.99}`, 0.99, true},
+		{`{"gamepadDeadzone": 1}`, 0, false},
+		{`{"gamepadDeadzone": 7.5}`, 0, false},
+		{`{"gamepadDeadzone": -0.2}`, 0, false},
+	}
+	for _, tt := range tests {
+		b, err := ParseBindings([]byte(tt.json))
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: err = %v, want ok = %v", tt.json, err, tt.ok)
+			continue
+		}
+		if tt.ok && b.GamepadDeadzone != tt.want {
+			t.Errorf("%s: deadzone %v, want %v", tt.json, b.GamepadDeadzone, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package ebiteninput
+
+import (
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/input"
+)
+
+// Auto switches between keyboard+mouse and gamepad depending on which was used last
+type Auto struct {
+	Keyboard *KeyboardMouse
+	Gamepad  *Gamepad
+
+	usingGamepad bool
+}
+
+// New creates the default input source for the game from bindings
+func New(bindings *input.Bindings) (*Auto, error) {
+	keyboard, err := NewKeyboardMouse(bindings)
+	if err != nil {
+		return nil, err
+	}
+	gamepad, err := NewGamepad(bindings)
+	if err != nil {
+		return nil, err
+	}
+	return &Auto{Keyboard: keyboard, Gamepad: gamepad}, nil
+}
+
+// Poll implements input.InputSource
+func (a *Auto) Poll(view input.View) coretypes.InputFrame {
+	if a.Gamepad.Active() {
+		if !a.usingGamepad {
+			// Start the virtual cursor where the mouse was
+			a.Gamepad.CursorX = float64(a.Keyboard.lastCursorX)
+			a.Gamepad.CursorY = float64(a.Keyboard.lastCursorY)
+			a.Gamepad.cursorPlaced = true
+		}
+		a.usingGamepad = true
+	} else if a.Keyboard.Active() {
+		a.usingGamepad = false
+	}
+
+	if a.usingGamepad {
+		return a.Gamepad.Poll(view)
+	}
+	return a.Keyboard.Poll(view)
+}
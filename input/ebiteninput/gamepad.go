@@ -0,0 +1,131 @@
+package ebiteninput
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/input"
+)
+
+// Gamepad reads actions from the first standard-layout gamepad. The left stick
+// moves the player and the right stick drives a virtual cursor used for aiming.
+type Gamepad struct {
+	buttons     map[input.Action][]ebiten.StandardGamepadButton
+	deadzone    float64
+	cursorSpeed float64
+	builder     input.FrameBuilder
+
+	// Virtual cursor in screen pixels
+	CursorX, CursorY float64
+	cursorPlaced     bool
+
+	gamepadIDs []ebiten.GamepadID
+}
+
+// NewGamepad creates a gamepad source from bindings
+func NewGamepad(bindings *input.Bindings) (*Gamepad, error) {
+	buttons, err := parseGamepad(bindings.Gamepad)
+	if err != nil {
+		return nil, err
+	}
+	if err := bindings.CheckDeadzone(); err != nil {
+		return nil, err
+	}
+	return &Gamepad{
+		buttons:     buttons,
+		deadzone:    bindings.GamepadDeadzone,
+		cursorSpeed: bindings.CursorSpeed,
+	}, nil
+}
+
+// gamepad returns the first connected gamepad with a standard layout
+func (g *Gamepad) gamepad() (ebiten.GamepadID, bool) {
+	g.gamepadIDs = ebiten.AppendGamepadIDs(g.gamepadIDs[:0])
+	for _, id := range g.gamepadIDs {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// Connected reports whether a usable gamepad is plugged in
+func (g *Gamepad) Connected() bool {
+	_, ok := g.gamepad()
+	return ok
+}
+
+// axis reads a stick axis with the deadzone removed and the remainder rescaled to [-1, 1]
+func (g *Gamepad) axis(id ebiten.GamepadID, axis ebiten.StandardGamepadAxis) float64 {
+	v := ebiten.StandardGamepadAxisValue(id, axis)
+	if math.Abs(v) <= g.deadzone {
+		return 0
+	}
+	return math.Copysign((math.Abs(v)-g.deadzone)/(1-g.deadzone), v)
+}
+
+// Poll implements input.InputSource
+func (g *Gamepad) Poll(view input.View) coretypes.InputFrame {
+	if !g.cursorPlaced && view.ScreenW > 0 {
+		g.CursorX, g.CursorY = float64(view.ScreenW)/2, float64(view.ScreenH)/2
+		g.cursorPlaced = true
+	}
+
+	id, ok := g.gamepad()
+	if !ok {
+		return g.builder.Build(nil, nil, 0, g.CursorX+view.CameraX, g.CursorY+view.CameraY, view.SelectedSlot)
+	}
+
+	held, pressed := g.actions(id)
+
+	// Right stick moves the virtual cursor, kept on screen
+	g.CursorX += g.axis(id, ebiten.StandardGamepadAxisRightStickHorizontal) * g.cursorSpeed
+	g.CursorY += g.axis(id, ebiten.StandardGamepadAxisRightStickVertical) * g.cursorSpeed
+	g.CursorX = math.Max(0, math.Min(g.CursorX, float64(view.ScreenW-1)))
+	g.CursorY = math.Max(0, math.Min(g.CursorY, float64(view.ScreenH-1)))
+
+	moveAxis := g.axis(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+	return g.builder.Build(held, pressed, moveAxis, g.CursorX+view.CameraX, g.CursorY+view.CameraY, view.SelectedSlot)
+}
+
+// actions collects the held and just-pressed actions on a gamepad
+func (g *Gamepad) actions(id ebiten.GamepadID) (input.ActionSet, input.ActionSet) {
+	held := input.ActionSet{}
+	pressed := input.ActionSet{}
+	for action, buttons := range g.buttons {
+		for _, button := range buttons {
+			if ebiten.IsStandardGamepadButtonPressed(id, button) {
+				held[action] = true
+			}
+			if inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+				pressed[action] = true
+			}
+		}
+	}
+	return held, pressed
+}
+
+// Active reports whether any bound button or stick was used this tick
+func (g *Gamepad) Active() bool {
+	id, ok := g.gamepad()
+	if !ok {
+		return false
+	}
+	held, pressed := g.actions(id)
+	if len(held) > 0 || len(pressed) > 0 {
+		return true
+	}
+	for _, axis := range []ebiten.StandardGamepadAxis{
+		ebiten.StandardGamepadAxisLeftStickHorizontal,
+		ebiten.StandardGamepadAxisRightStickHorizontal,
+		ebiten.StandardGamepadAxisRightStickVertical,
+	} {
+		if g.axis(id, axis) != 0 {
+			return true
+		}
+	}
+	return false
+}
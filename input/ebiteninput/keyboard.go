@@ -0,0 +1,88 @@
+package ebiteninput
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/input"
+)
+
+// KeyboardMouse reads actions from the keyboard and aims with the mouse cursor
+type KeyboardMouse struct {
+	keys    map[input.Action][]ebiten.Key
+	buttons map[input.Action][]ebiten.MouseButton
+	wheel   map[input.Action][]string
+	builder input.FrameBuilder
+
+	lastCursorX, lastCursorY int
+}
+
+// NewKeyboardMouse creates a keyboard and mouse source from bindings
+func NewKeyboardMouse(bindings *input.Bindings) (*KeyboardMouse, error) {
+	keys, err := parseKeys(bindings.Keyboard)
+	if err != nil {
+		return nil, err
+	}
+	buttons, wheel, err := parseMouse(bindings.Mouse)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyboardMouse{keys: keys, buttons: buttons, wheel: wheel}, nil
+}
+
+// Poll implements input.InputSource
+func (k *KeyboardMouse) Poll(view input.View) coretypes.InputFrame {
+	held, pressed := k.actions()
+	cursorX, cursorY := ebiten.CursorPosition()
+	return k.builder.Build(held, pressed, 0, float64(cursorX)+view.CameraX, float64(cursorY)+view.CameraY, view.SelectedSlot)
+}
+
+// actions collects the held and just-pressed actions across keys, buttons and the wheel
+func (k *KeyboardMouse) actions() (input.ActionSet, input.ActionSet) {
+	held := input.ActionSet{}
+	pressed := input.ActionSet{}
+
+	for action, keys := range k.keys {
+		for _, key := range keys {
+			if ebiten.IsKeyPressed(key) {
+				held[action] = true
+			}
+			if inpututil.IsKeyJustPressed(key) {
+				pressed[action] = true
+			}
+		}
+	}
+	for action, buttons := range k.buttons {
+		for _, button := range buttons {
+			if ebiten.IsMouseButtonPressed(button) {
+				held[action] = true
+			}
+			if inpututil.IsMouseButtonJustPressed(button) {
+				pressed[action] = true
+			}
+		}
+	}
+
+	// Each wheel notch counts as a single press
+	_, wheelY := ebiten.Wheel()
+	for action, directions := range k.wheel {
+		for _, direction := range directions {
+			if (direction == wheelUp && wheelY > 0) || (direction == wheelDown && wheelY < 0) {
+				pressed[action] = true
+			}
+		}
+	}
+
+	return held, pressed
+}
+
+// Active reports whether the keyboard or mouse was used this tick
+func (k *KeyboardMouse) Active() bool {
+	cursorX, cursorY := ebiten.CursorPosition()
+	moved := cursorX != k.lastCursorX || cursorY != k.lastCursorY
+	k.lastCursorX, k.lastCursorY = cursorX, cursorY
+
+	held, pressed := k.actions()
+	return moved || len(held) > 0 || len(pressed) > 0
+}
@@ -0,0 +1,95 @@
+package ebiteninput
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/KdntNinja/webcraft/input"
+)
+
+// Mouse inputs that aren't buttons
+const (
+	wheelUp   = "wheelup"
+	wheelDown = "wheeldown"
+)
+
+var mouseButtonNames = map[string]ebiten.MouseButton{
+	"left":    ebiten.MouseButtonLeft,
+	"right":   ebiten.MouseButtonRight,
+	"middle":  ebiten.MouseButtonMiddle,
+	"back":    ebiten.MouseButton3,
+	"forward": ebiten.MouseButton4,
+}
+
+var gamepadButtonNames = map[string]ebiten.StandardGamepadButton{
+	"a":          ebiten.StandardGamepadButtonRightBottom,
+	"b":          ebiten.StandardGamepadButtonRightRight,
+	"x":          ebiten.StandardGamepadButtonRightLeft,
+	"y":          ebiten.StandardGamepadButtonRightTop,
+	"lb":         ebiten.StandardGamepadButtonFrontTopLeft,
+	"rb":         ebiten.StandardGamepadButtonFrontTopRight,
+	"lt":         ebiten.StandardGamepadButtonFrontBottomLeft,
+	"rt":         ebiten.StandardGamepadButtonFrontBottomRight,
+	"back":       ebiten.StandardGamepadButtonCenterLeft,
+	"start":      ebiten.StandardGamepadButtonCenterRight,
+	"home":       ebiten.StandardGamepadButtonCenterCenter,
+	"leftstick":  ebiten.StandardGamepadButtonLeftStick,
+	"rightstick": ebiten.StandardGamepadButtonRightStick,
+	"dpadup":     ebiten.StandardGamepadButtonLeftTop,
+	"dpaddown":   ebiten.StandardGamepadButtonLeftBottom,
+	"dpadleft":   ebiten.StandardGamepadButtonLeftLeft,
+	"dpadright":  ebiten.StandardGamepadButtonLeftRight,
+}
+
+// parseKeys resolves keyboard binding names to ebiten keys
+func parseKeys(bindings map[input.Action][]string) (map[input.Action][]ebiten.Key, error) {
+	result := make(map[input.Action][]ebiten.Key)
+	for action, names := range bindings {
+		for _, name := range names {
+			var key ebiten.Key
+			if err := key.UnmarshalText([]byte(name)); err != nil {
+				return nil, fmt.Errorf("binding %s: %w", action, err)
+			}
+			result[action] = append(result[action], key)
+		}
+	}
+	return result, nil
+}
+
+// parseMouse resolves mouse binding names to buttons and wheel directions
+func parseMouse(bindings map[input.Action][]string) (map[input.Action][]ebiten.MouseButton, map[input.Action][]string, error) {
+	buttons := make(map[input.Action][]ebiten.MouseButton)
+	wheel := make(map[input.Action][]string)
+	for action, names := range bindings {
+		for _, name := range names {
+			lower := strings.ToLower(name)
+			if lower == wheelUp || lower == wheelDown {
+				wheel[action] = append(wheel[action], lower)
+				continue
+			}
+			button, ok := mouseButtonNames[lower]
+			if !ok {
+				return nil, nil, fmt.Errorf("binding %s: unknown mouse input %q", action, name)
+			}
+			buttons[action] = append(buttons[action], button)
+		}
+	}
+	return buttons, wheel, nil
+}
+
+// parseGamepad resolves gamepad binding names to standard-layout buttons
+func parseGamepad(bindings map[input.Action][]string) (map[input.Action][]ebiten.StandardGamepadButton, error) {
+	result := make(map[input.Action][]ebiten.StandardGamepadButton)
+	for action, names := range bindings {
+		for _, name := range names {
+			button, ok := gamepadButtonNames[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("binding %s: unknown gamepad button %q", action, name)
+			}
+			result[action] = append(result[action], button)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package input
+
+import "github.com/KdntNinja/webcraft/coretypes"
+
+// ScriptStep holds a set of actions for a number of ticks
+type ScriptStep struct {
+	Ticks      int
+	Hold       []Action
+	MoveAxis   float64 // Optional analog movement, as from a stick
+	AimX, AimY float64 // Aim point in world pixel coordinates
+}
+
+// ScriptedSource replays a fixed list of frames, one per Poll, then idles.
+// It needs no devices, which makes it the input source for headless tests.
+type ScriptedSource struct {
+	Frames []coretypes.InputFrame
+	pos    int
+}
+
+// NewScriptedSource creates a source that returns the given frames in order
+func NewScriptedSource(frames ...coretypes.InputFrame) *ScriptedSource {
+	return &ScriptedSource{Frames: frames}
+}
+
+// NewScriptedSourceFromSteps expands action steps into frames. An action counts as
+// pressed on the first tick of a step that holds it after a step that didn't.
+// Hotbar next/previous count from the slots the script itself selected, starting
+// at the first.
+func NewScriptedSourceFromSteps(steps ...ScriptStep) *ScriptedSource {
+	var builder FrameBuilder
+	var frames []coretypes.InputFrame
+	previous := ActionSet{}
+	selected := 0
+
+	for _, step := range steps {
+		held := ActionSet{}
+		for _, action := range step.Hold {
+			held[action] = true
+		}
+		for tick := 0; tick < step.Ticks; tick++ {
+			pressed := ActionSet{}
+			for action := range held {
+				if !previous[action] {
+					pressed[action] = true
+				}
+			}
+			frame := builder.Build(held, pressed, step.MoveAxis, step.AimX, step.AimY, selected)
+			if frame.HotbarSlot >= 0 {
+				selected = frame.HotbarSlot
+			}
+			frames = append(frames, frame)
+			previous = held
+		}
+	}
+	return NewScriptedSource(frames...)
+}
+
+// Poll returns the next scripted frame, or an empty frame once the script is done
+func (s *ScriptedSource) Poll(view View) coretypes.InputFrame {
+	if s.pos >= len(s.Frames) {
+		return coretypes.EmptyInputFrame()
+	}
+	frame := s.Frames[s.pos]
+	s.pos++
+	return frame
+}
+
+// Done reports whether every scripted frame has been returned
+func (s *ScriptedSource) Done() bool {
+	return s.pos >= len(s.Frames)
+}
@@ -0,0 +1,75 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/sim"
+)
+
+func TestScriptedStepsExpandToFrames(t *testing.T) {
+	src := NewScriptedSourceFromSteps(
+		ScriptStep{Ticks: 3, Hold: []Action{MoveRight, Jump}},
+		ScriptStep{Ticks: 2, Hold: []Action{MoveLeft}, MoveAxis: -0.5, AimX: 10, AimY: 20},
+		ScriptStep{Ticks: 1, Hold: []Action{HotbarSlot(8)}},
+		ScriptStep{Ticks: 1, Hold: []Action{HotbarNext}},
+		ScriptStep{Ticks: 1},
+		ScriptStep{Ticks: 1, Hold: []Action{HotbarPrev}},
+	)
+	if len(src.Frames) != 9 {
+		t.Fatalf("got %d frames, want 9", len(src.Frames))
+	}
+
+	for i := 0; i < 3; i++ {
+		if f := src.Frames[i]; f.MoveX != 1 || !f.Jump {
+			t.Fatalf("frame %d: %+v, want moving right and jumping", i, f)
+		}
+	}
+	if f := src.Frames[3]; f.MoveX != -1 || f.Jump || f.AimX != 10 || f.AimY != 20 {
+		t.Fatalf("frame 3: %+v, want moving left (axis clamped), aiming at 10,20", f)
+	}
+	// Selection is only sent on the tick a key goes down; next and previous wrap
+	for i, want := range []int{8, 0, -1, 8} {
+		if got := src.Frames[5+i].HotbarSlot; got != want {
+			t.Fatalf("frame %d selects slot %d, want %d", 5+i, got, want)
+		}
+	}
+}
+
+func TestFrameBuilderStepsFromSelectedSlot(t *testing.T) {
+	var b FrameBuilder
+	next := ActionSet{HotbarNext: true}
+	prev := ActionSet{HotbarPrev: true}
+	if got := b.Build(next, next, 0, 0, 0, 4).HotbarSlot; got != 5 {
+		t.Fatalf("next from slot 4 selected %d", got)
+	}
+	// The player changed slots elsewhere; the builder must follow
+	if got := b.Build(prev, prev, 0, 0, 0, 0).HotbarSlot; got != HotbarSlots-1 {
+		t.Fatalf("previous from slot 0 selected %d", got)
+	}
+}
+
+func TestScriptedSourceDrivesSimulation(t *testing.T) {
+	run := func() uint64 {
+		s := sim.NewHeadless(5)
+		src := NewScriptedSourceFromSteps(
+			ScriptStep{Ticks: 60, Hold: []Action{MoveRight}},
+			ScriptStep{Ticks: 30, Hold: []Action{MoveLeft, Jump, Break}, AimX: 100, AimY: 1900},
+		)
+		for !src.Done() {
+			frame := src.Poll(View{})
+			s.Step(frame)
+			if s.Player().Input != frame {
+				t.Fatalf("tick %d: player got %+v, want %+v", s.Tick, s.Player().Input, frame)
+			}
+		}
+		return s.StateHash()
+	}
+
+	if run() != run() {
+		t.Fatal("the same script gave a different state")
+	}
+	if f := NewScriptedSource().Poll(View{}); f != coretypes.EmptyInputFrame() {
+		t.Fatalf("finished script returned %+v, want an empty frame", f)
+	}
+}
@@ -0,0 +1,75 @@
+package input
+
+import "github.com/KdntNinja/webcraft/coretypes"
+
+// View describes the camera and screen an input source aims through
+type View struct {
+	CameraX, CameraY float64 // World position of the screen's top-left corner
+	ScreenW, ScreenH int     // Screen size in pixels
+	SelectedSlot     int     // Hotbar slot currently in the player's hand
+}
+
+// InputSource produces one input frame per simulation tick
+type InputSource interface {
+	Poll(view View) coretypes.InputFrame
+}
+
+// ActionSet is the set of actions active in a tick
+type ActionSet map[Action]bool
+
+// FrameBuilder turns per-device action state into input frames. Relative hotbar
+// selection (next/previous) is expressed as the absolute slot an InputFrame
+// carries, counted from the slot the player has selected, so it follows changes
+// made elsewhere (the inventory screen, a respawn, a server correction).
+type FrameBuilder struct{}
+
+// Build assembles a frame from the held actions, the actions pressed this tick,
+// an analog horizontal axis (added to the digital move actions), the aim point and
+// the hotbar slot currently selected
+func (b *FrameBuilder) Build(held, pressed ActionSet, moveAxis, aimX, aimY float64, selected int) coretypes.InputFrame {
+	frame := coretypes.EmptyInputFrame()
+
+	frame.MoveX = moveAxis
+	if held[MoveLeft] {
+		frame.MoveX -= 1
+	}
+	if held[MoveRight] {
+		frame.MoveX += 1
+	}
+	if frame.MoveX < -1 {
+		frame.MoveX = -1
+	}
+	if frame.MoveX > 1 {
+		frame.MoveX = 1
+	}
+
+	frame.Jump = held[Jump]
+	frame.Sneak = held[Sneak]
+	frame.Sprint = held[Sprint]
+	frame.Break = held[Break]
+	frame.Place = held[Place]
+	frame.Attack = held[Attack]
+
+	slot := selected
+	if slot < 0 || slot >= HotbarSlots {
+		slot = 0
+	}
+	for i := 0; i < HotbarSlots; i++ {
+		if pressed[HotbarSlot(i)] {
+			slot = i
+			frame.HotbarSlot = i
+		}
+	}
+	if pressed[HotbarNext] {
+		slot = (slot + 1) % HotbarSlots
+		frame.HotbarSlot = slot
+	}
+	if pressed[HotbarPrev] {
+		slot = (slot + HotbarSlots - 1) % HotbarSlots
+		frame.HotbarSlot = slot
+	}
+
+	frame.AimX = aimX
+	frame.AimY = aimY
+	return frame
+}
@@ -0,0 +1,101 @@
+package inventory
+
+import (
+	"slices"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+// Click is a left click on a slot while carrying the cursor stack, and returns what
+// is carried afterwards. An empty cursor picks up the whole slot; a stack that
+// matches the slot (or an empty slot) is put down as far as it fits; anything else
+// swaps with the slot.
+func (c *Container) Click(slot int, cursor coretypes.ItemStack) coretypes.ItemStack {
+	if slot < 0 || slot >= len(c.Slots) {
+		return cursor
+	}
+	current := c.Slots[slot]
+	switch {
+	case cursor.IsEmpty():
+		c.Slots[slot] = coretypes.ItemStack{}
+		return normalize(current)
+	case current.IsEmpty() || current.StacksWith(cursor):
+		return c.Insert(slot, cursor)
+	default:
+		c.Slots[slot] = cursor
+		return current
+	}
+}
+
+// Split is a right click on a slot while carrying the cursor stack, and returns what
+// is carried afterwards. An empty cursor picks up half the slot (rounded up);
+// otherwise one item is put down if the slot is empty or matches.
+func (c *Container) Split(slot int, cursor coretypes.ItemStack) coretypes.ItemStack {
+	if slot < 0 || slot >= len(c.Slots) {
+		return cursor
+	}
+	if cursor.IsEmpty() {
+		return c.Take(slot, (c.Slots[slot].Count+1)/2)
+	}
+	one := cursor.Clone()
+	one.Count = 1
+	if c.Insert(slot, one).IsEmpty() {
+		cursor.Count--
+	}
+	return normalize(cursor)
+}
+
+// MoveTo moves the stack in a slot into the slots from first up to (not including)
+// last, topping up matching stacks before using empty slots. Whatever doesn't fit
+// stays where it was.
+func (c *Container) MoveTo(slot, first, last int) {
+	if slot < 0 || slot >= len(c.Slots) || c.Slots[slot].IsEmpty() {
+		return
+	}
+	first, last = max(first, 0), min(last, len(c.Slots))
+	stack := c.Slots[slot]
+	c.Slots[slot] = coretypes.ItemStack{}
+	for i := first; i < last && !stack.IsEmpty(); i++ {
+		if i != slot && !c.Slots[i].IsEmpty() && c.Slots[i].StacksWith(stack) {
+			stack = c.Insert(i, stack)
+		}
+	}
+	for i := first; i < last && !stack.IsEmpty(); i++ {
+		if i != slot && c.Slots[i].IsEmpty() {
+			stack = c.Insert(i, stack)
+		}
+	}
+	c.Slots[slot] = normalize(stack)
+}
+
+// Sort merges matching stacks in the slots from first up to (not including) last
+// and orders them by item and wear, leaving the empty slots at the end. Stacks
+// that only differ in metadata keep their order.
+func (c *Container) Sort(first, last int) {
+	first, last = max(first, 0), min(last, len(c.Slots))
+	if first >= last {
+		return
+	}
+	var stacks []coretypes.ItemStack
+	for i := first; i < last; i++ {
+		if !c.Slots[i].IsEmpty() {
+			stacks = append(stacks, c.Slots[i])
+		}
+	}
+	slices.SortStableFunc(stacks, func(a, b coretypes.ItemStack) int {
+		if a.Item != b.Item {
+			return int(a.Item) - int(b.Item)
+		}
+		return a.Damage - b.Damage
+	})
+
+	sorted := &Container{Slots: make([]coretypes.ItemStack, last-first)}
+	n := 0
+	for _, stack := range stacks {
+		if stack = sorted.Merge(stack); !stack.IsEmpty() {
+			sorted.Slots[n] = stack
+			n++
+		}
+	}
+	copy(c.Slots[first:last], sorted.Slots)
+}
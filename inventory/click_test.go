@@ -0,0 +1,98 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+func TestClick(t *testing.T) {
+	dirt := coretypes.Dirt.Item()
+	full := stone.MaxStack()
+	tests := []struct {
+		name                 string
+		slot, cursor         coretypes.ItemStack
+		wantSlot, wantCursor coretypes.ItemStack
+	}{
+		{"pick up", coretypes.NewStack(stone, 5), coretypes.ItemStack{}, coretypes.ItemStack{}, coretypes.NewStack(stone, 5)},
+		{"put down", coretypes.ItemStack{}, coretypes.NewStack(stone, 5), coretypes.NewStack(stone, 5), coretypes.ItemStack{}},
+		{"merge", coretypes.NewStack(stone, 5), coretypes.NewStack(stone, 3), coretypes.NewStack(stone, 8), coretypes.ItemStack{}},
+		{"merge past full", coretypes.NewStack(stone, full-1), coretypes.NewStack(stone, 3), coretypes.NewStack(stone, full), coretypes.NewStack(stone, 2)},
+		{"swap", coretypes.NewStack(stone, 5), coretypes.NewStack(dirt, 2), coretypes.NewStack(dirt, 2), coretypes.NewStack(stone, 5)},
+	}
+	for _, tt := range tests {
+		c := NewContainer(1)
+		c.Set(0, tt.slot)
+		cursor := c.Click(0, tt.cursor)
+		if c.Slot(0).Item != tt.wantSlot.Item || c.Slot(0).Count != tt.wantSlot.Count ||
+			cursor.Item != tt.wantCursor.Item || cursor.Count != tt.wantCursor.Count {
+			t.Errorf("%s: slot %+v cursor %+v, want slot %+v cursor %+v", tt.name, c.Slot(0), cursor, tt.wantSlot, tt.wantCursor)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	c := NewContainer(2)
+	c.Set(0, coretypes.NewStack(stone, 7))
+
+	// Picking up half rounds up
+	cursor := c.Split(0, coretypes.ItemStack{})
+	if cursor.Count != 4 || c.Slot(0).Count != 3 {
+		t.Fatalf("split 7 into cursor %d and slot %d, want 4 and 3", cursor.Count, c.Slot(0).Count)
+	}
+	// Right clicks while carrying put down one at a time, as when dragging across slots
+	for slot := range 2 {
+		cursor = c.Split(slot, cursor)
+	}
+	if cursor.Count != 2 || c.Slot(0).Count != 4 || c.Slot(1).Count != 1 {
+		t.Fatalf("dealing one each left cursor %d and slots %+v", cursor.Count, c.Slots)
+	}
+	// Nothing goes down on a slot holding something else
+	c.Set(1, coretypes.NewStack(coretypes.Dirt.Item(), 1))
+	if cursor = c.Split(1, cursor); cursor.Count != 2 {
+		t.Fatalf("put stone down on dirt: cursor %d", cursor.Count)
+	}
+}
+
+func TestMoveTo(t *testing.T) {
+	full := stone.MaxStack()
+	c := NewContainer(6)
+	c.Set(0, coretypes.NewStack(stone, 10))
+	c.Set(3, coretypes.NewStack(stone, full-4))
+	c.Set(4, coretypes.NewStack(coretypes.Dirt.Item(), 1))
+
+	// Quick move from the "hotbar" (0-2) into the rest tops up slot 3, then fills slot 5
+	c.MoveTo(0, 3, 6)
+	if !c.Slot(0).IsEmpty() || c.Slot(3).Count != full || c.Slot(5).Count != 6 {
+		t.Fatalf("slots after moving: %+v", c.Slots)
+	}
+
+	// What doesn't fit stays behind
+	c.Set(1, coretypes.NewStack(stone, full))
+	c.MoveTo(1, 3, 6)
+	if got := c.Slot(1).Count; got != 6 {
+		t.Fatalf("%d stone left behind, want the 6 that didn't fit", got)
+	}
+}
+
+func TestSort(t *testing.T) {
+	dirt := coretypes.Dirt.Item()
+	c := NewContainer(6)
+	c.Set(0, coretypes.NewStack(stone, 3))
+	c.Set(1, coretypes.NewStack(stone, 2)) // Outside the sorted range
+	c.Set(2, coretypes.NewStack(stone, 4))
+	c.Set(4, coretypes.NewStack(dirt, 1))
+	c.Set(5, coretypes.NewStack(stone, 5))
+
+	c.Sort(2, 6)
+	if c.Slot(0).Count != 3 || c.Slot(1).Count != 2 {
+		t.Fatalf("slots outside the range changed: %+v", c.Slots[:2])
+	}
+	first, second := c.Slot(2), c.Slot(3)
+	if dirt < stone {
+		first, second = second, first
+	}
+	if first.Item != stone || first.Count != 9 || second.Item != dirt || !c.Slot(4).IsEmpty() || !c.Slot(5).IsEmpty() {
+		t.Fatalf("sorted slots %+v, want 9 stone and 1 dirt in item order, then empty", c.Slots[2:])
+	}
+}
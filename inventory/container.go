@@ -0,0 +1,181 @@
+package inventory
+
+import (
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+// Container is a fixed number of item slots, such as a player's inventory.
+// Stacks never hold more than their item's MaxStack.
+type Container struct {
+	Slots []coretypes.ItemStack
+}
+
+// NewContainer creates a container with the given number of empty slots
+func NewContainer(size int) *Container {
+	return &Container{Slots: make([]coretypes.ItemStack, size)}
+}
+
+// Len returns the number of slots
+func (c *Container) Len() int {
+	return len(c.Slots)
+}
+
+// Slot returns the stack in a slot; out of range slots are empty
+func (c *Container) Slot(slot int) coretypes.ItemStack {
+	if slot < 0 || slot >= len(c.Slots) {
+		return coretypes.ItemStack{}
+	}
+	return c.Slots[slot]
+}
+
+// Set puts a stack in a slot, replacing what was there
+func (c *Container) Set(slot int, stack coretypes.ItemStack) {
+	if slot < 0 || slot >= len(c.Slots) {
+		return
+	}
+	if stack.IsEmpty() {
+		stack = coretypes.ItemStack{}
+	}
+	c.Slots[slot] = stack
+}
+
+// Count returns how many of an item the container holds across all slots
+func (c *Container) Count(item coretypes.ItemID) int {
+	total := 0
+	for _, s := range c.Slots {
+		if s.Item == item && !s.IsEmpty() {
+			total += s.Count
+		}
+	}
+	return total
+}
+
+// Add puts a stack into the container, topping up matching stacks first and then
+// filling empty slots in order. It returns what didn't fit.
+func (c *Container) Add(stack coretypes.ItemStack) coretypes.ItemStack {
+	return c.Fill(c.Merge(stack), nil)
+}
+
+// Merge tops up the stacks that match the given one, in slot order, and returns
+// what is left
+func (c *Container) Merge(stack coretypes.ItemStack) coretypes.ItemStack {
+	for i := range c.Slots {
+		if stack.IsEmpty() {
+			break
+		}
+		if !c.Slots[i].IsEmpty() && c.Slots[i].StacksWith(stack) {
+			stack = c.Insert(i, stack)
+		}
+	}
+	return normalize(stack)
+}
+
+// Fill puts a stack into empty slots, trying the given slots in order first and
+// then the rest in slot order, and returns what is left
+func (c *Container) Fill(stack coretypes.ItemStack, preferred []int) coretypes.ItemStack {
+	for _, i := range preferred {
+		if stack.IsEmpty() {
+			break
+		}
+		if i >= 0 && i < len(c.Slots) && c.Slots[i].IsEmpty() {
+			stack = c.Insert(i, stack)
+		}
+	}
+	for i := range c.Slots {
+		if stack.IsEmpty() {
+			break
+		}
+		if c.Slots[i].IsEmpty() {
+			stack = c.Insert(i, stack)
+		}
+	}
+	return normalize(stack)
+}
+
+// Insert puts as much of a stack as fits into one slot, which must be empty or hold
+// a matching stack, and returns what is left. A slot holding something else is left
+// alone and the whole stack is returned.
+func (c *Container) Insert(slot int, stack coretypes.ItemStack) coretypes.ItemStack {
+	if slot < 0 || slot >= len(c.Slots) || stack.IsEmpty() {
+		return normalize(stack)
+	}
+	current := &c.Slots[slot]
+	if current.IsEmpty() {
+		*current = stack.Clone()
+		current.Count = 0
+	} else if !current.StacksWith(stack) {
+		return stack
+	}
+
+	moved := min(stack.Count, stack.Item.MaxStack()-current.Count)
+	if moved <= 0 {
+		return stack
+	}
+	current.Count += moved
+	stack.Count -= moved
+	return normalize(stack)
+}
+
+// Take removes up to count items from a slot and returns them. Taking half a stack
+// (rounded up) splits it.
+func (c *Container) Take(slot, count int) coretypes.ItemStack {
+	if slot < 0 || slot >= len(c.Slots) || count <= 0 || c.Slots[slot].IsEmpty() {
+		return coretypes.ItemStack{}
+	}
+	taken := c.Slots[slot].Clone()
+	taken.Count = min(count, taken.Count)
+	c.Slots[slot].Count -= taken.Count
+	if c.Slots[slot].Count <= 0 {
+		c.Slots[slot] = coretypes.ItemStack{}
+	}
+	return taken
+}
+
+// Remove takes count of an item out of the container, from the first slots holding
+// it. It removes nothing and returns false if there aren't enough.
+func (c *Container) Remove(item coretypes.ItemID, count int) bool {
+	if count <= 0 || c.Count(item) < count {
+		return false
+	}
+	for i := range c.Slots {
+		if count == 0 {
+			break
+		}
+		if c.Slots[i].Item == item && !c.Slots[i].IsEmpty() {
+			count -= c.Take(i, count).Count
+		}
+	}
+	return true
+}
+
+// Clear empties every slot and returns the stacks that were in them
+func (c *Container) Clear() []coretypes.ItemStack {
+	var stacks []coretypes.ItemStack
+	for i, s := range c.Slots {
+		if !s.IsEmpty() {
+			stacks = append(stacks, s)
+		}
+		c.Slots[i] = coretypes.ItemStack{}
+	}
+	return stacks
+}
+
+// Clone returns a deep copy of the container
+func (c *Container) Clone() *Container {
+	if c == nil {
+		return nil
+	}
+	clone := &Container{Slots: make([]coretypes.ItemStack, len(c.Slots))}
+	for i, s := range c.Slots {
+		clone.Slots[i] = s.Clone()
+	}
+	return clone
+}
+
+// normalize turns an empty stack into the zero stack
+func normalize(stack coretypes.ItemStack) coretypes.ItemStack {
+	if stack.IsEmpty() {
+		return coretypes.ItemStack{}
+	}
+	return stack
+}
@@ -0,0 +1,98 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+var stone = coretypes.Stone.Item()
+
+func TestAddMergesBeforeFilling(t *testing.T) {
+	full := stone.MaxStack()
+	c := NewContainer(4)
+	c.Set(2, coretypes.NewStack(stone, full-5))
+
+	if left := c.Add(coretypes.NewStack(stone, 8)); !left.IsEmpty() {
+		t.Fatalf("%d stone didn't fit in a near-empty container", left.Count)
+	}
+	if c.Slot(2).Count != full || c.Slot(0).Count != 3 {
+		t.Fatalf("slots %+v: want slot 2 topped up to %d, then 3 in slot 0", c.Slots, full)
+	}
+	if c.Count(stone) != full+3 {
+		t.Fatalf("count %d, want %d", c.Count(stone), full+3)
+	}
+}
+
+func TestAddReturnsOverflow(t *testing.T) {
+	full := stone.MaxStack()
+	c := NewContainer(2)
+	left := c.Add(coretypes.NewStack(stone, 2*full+7))
+	if left.Item != stone || left.Count != 7 {
+		t.Fatalf("leftover %+v, want 7 stone", left)
+	}
+	if c.Slot(0).Count != full || c.Slot(1).Count != full {
+		t.Fatalf("slots %+v, want both full", c.Slots)
+	}
+	if left := c.Add(coretypes.NewStack(stone, 1)); left.Count != 1 {
+		t.Fatalf("full container took an item: leftover %+v", left)
+	}
+}
+
+func TestStacksOnlyMergeWhenIdentical(t *testing.T) {
+	c := NewContainer(4)
+	worn := coretypes.ItemStack{Item: coretypes.CopperPickaxe, Count: 1, Damage: 10}
+	named := coretypes.ItemStack{Item: stone, Count: 1, Meta: map[string]string{"name": "keepsake"}}
+
+	for _, stack := range []coretypes.ItemStack{
+		coretypes.NewStack(coretypes.CopperPickaxe, 1),
+		coretypes.NewStack(coretypes.CopperPickaxe, 1), // Tools don't stack
+		worn,
+		named,
+	} {
+		if left := c.Add(stack); !left.IsEmpty() {
+			t.Fatalf("no room for %+v", stack)
+		}
+	}
+	for i, s := range c.Slots {
+		if s.Count != 1 {
+			t.Fatalf("slot %d holds %d; stacks that differ shouldn't merge", i, s.Count)
+		}
+	}
+	if left := c.Add(coretypes.NewStack(stone, 1)); left.IsEmpty() {
+		t.Fatal("plain stone merged into a stack with metadata")
+	}
+}
+
+func TestTakeAndRemove(t *testing.T) {
+	c := NewContainer(3)
+	c.Set(0, coretypes.NewStack(stone, 5))
+	c.Set(2, coretypes.NewStack(stone, 4))
+
+	if got := c.Take(0, 2); got.Count != 2 || c.Slot(0).Count != 3 {
+		t.Fatalf("took %+v leaving %+v", got, c.Slot(0))
+	}
+	if c.Remove(stone, 8) {
+		t.Fatal("removed 8 stone from a container holding 7")
+	}
+	if c.Count(stone) != 7 {
+		t.Fatalf("failed remove changed the count to %d", c.Count(stone))
+	}
+	if !c.Remove(stone, 5) || c.Count(stone) != 2 || !c.Slot(0).IsEmpty() {
+		t.Fatalf("remove 5: slots %+v", c.Slots)
+	}
+	if got := c.Take(1, 1); !got.IsEmpty() {
+		t.Fatalf("took %+v from an empty slot", got)
+	}
+}
+
+func TestCloneSharesNothing(t *testing.T) {
+	c := NewContainer(1)
+	c.Set(0, coretypes.ItemStack{Item: stone, Count: 3, Meta: map[string]string{"k": "v"}})
+	clone := c.Clone()
+	clone.Slots[0].Count = 1
+	clone.Slots[0].Meta["k"] = "changed"
+	if c.Slot(0).Count != 3 || c.Slot(0).Meta["k"] != "v" {
+		t.Fatalf("changing the clone changed the original: %+v", c.Slot(0))
+	}
+}
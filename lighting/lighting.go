@@ -0,0 +1,327 @@
+package lighting
+
+import (
+	"sort"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// World is the block access the lighting engine needs
+type World interface {
+	// LoadedChunks returns the chunks currently in memory; only these are lit
+	LoadedChunks() map[coretypes.ChunkCoord]*coretypes.Chunk
+}
+
+// Surface returns the surface height of a column. Cells above it whose chunk above
+// isn't loaded are assumed to be open to the sky.
+type Surface func(x int) int
+
+// channel selects one of the two light maps
+type channel int
+
+const (
+	sunLight   channel = iota // Light from the sky, scaled by time of day when drawn
+	blockLight                // Light from emitting blocks (torches, hellstone, lava)
+)
+
+type grid [settings.ChunkHeight][settings.ChunkWidth]uint8
+
+// lightChunk holds the light of one loaded chunk
+type lightChunk struct {
+	blocks *coretypes.Chunk // The chunk the light was computed for
+	levels [2]grid          // Indexed by channel
+}
+
+type cell struct {
+	X, Y int
+}
+
+// removed is a cell cleared while relighting, with the light it had
+type removed struct {
+	cell  cell
+	level int
+}
+
+// Engine keeps a light level, 0 to coretypes.MaxLight, for every block in the loaded
+// chunks. Sunlight falls straight down through open cells without fading and spreads
+// sideways like block light; block light floods out from emitting blocks. Every step
+// into a cell costs light depending on the block there, and light that has entered a
+// solid block doesn't come back out into open space, so walls are lit on the near
+// side only. Newly loaded chunks are lit by Sync and block changes are relit
+// incrementally by BlockChanged.
+type Engine struct {
+	world   World
+	surface Surface
+	chunks  map[coretypes.ChunkCoord]*lightChunk
+}
+
+// New creates a lighting engine for a world
+func New(world World, surface Surface) *Engine {
+	return &Engine{
+		world:   world,
+		surface: surface,
+		chunks:  make(map[coretypes.ChunkCoord]*lightChunk),
+	}
+}
+
+// Sync lights chunks that were loaded since the last call and forgets unloaded ones.
+// Call it once per tick, after the world changed.
+func (e *Engine) Sync() {
+	loaded := e.world.LoadedChunks()
+	for coord, lc := range e.chunks {
+		if loaded[coord] != lc.blocks {
+			delete(e.chunks, coord)
+		}
+	}
+
+	var fresh []coretypes.ChunkCoord
+	for coord, chunk := range loaded {
+		if _, ok := e.chunks[coord]; !ok && len(chunk.Blocks) == settings.ChunkHeight {
+			fresh = append(fresh, coord)
+		}
+	}
+	// Top to bottom, so sunlight from above is already there when a chunk is lit
+	sort.Slice(fresh, func(i, j int) bool {
+		if fresh[i].Y != fresh[j].Y {
+			return fresh[i].Y < fresh[j].Y
+		}
+		return fresh[i].X < fresh[j].X
+	})
+	for _, coord := range fresh {
+		e.light(coord, loaded[coord])
+	}
+}
+
+// BlockChanged relights the area around a block after it changed. Blocks in chunks
+// that haven't been lit yet are ignored; Sync lights them in full.
+func (e *Engine) BlockChanged(x, y int) {
+	c := cell{x, y}
+	if _, ok := e.blockAt(c); !ok {
+		return
+	}
+	e.relight(sunLight, c)
+	e.relight(blockLight, c)
+}
+
+// Sunlight returns the sky light at a block, before time of day is applied
+func (e *Engine) Sunlight(x, y int) int {
+	return e.get(sunLight, cell{x, y})
+}
+
+// BlockLight returns the light from emitting blocks at a block
+func (e *Engine) BlockLight(x, y int) int {
+	return e.get(blockLight, cell{x, y})
+}
+
+// Level returns the brighter of the sun and block light at a block
+func (e *Engine) Level(x, y int) int {
+	return max(e.Sunlight(x, y), e.BlockLight(x, y))
+}
+
+// Brightness returns a block's light from 0 to 1, with sunlight scaled by daylight
+// (clock.Clock.Daylight)
+func (e *Engine) Brightness(x, y int, daylight float64) float64 {
+	sun := float64(e.Sunlight(x, y)) * daylight
+	return max(sun, float64(e.BlockLight(x, y))) / coretypes.MaxLight
+}
+
+// Lit reports whether a block's chunk has been lit
+func (e *Engine) Lit(x, y int) bool {
+	_, ok := e.blockAt(cell{x, y})
+	return ok
+}
+
+// light computes the light of a newly loaded chunk and lets it spill into its neighbours
+func (e *Engine) light(coord coretypes.ChunkCoord, chunk *coretypes.Chunk) {
+	e.chunks[coord] = &lightChunk{blocks: chunk}
+	baseX, baseY := coord.X*settings.ChunkWidth, coord.Y*settings.ChunkHeight
+
+	var queues [2][]cell
+	for y := 0; y < settings.ChunkHeight; y++ {
+		for x := 0; x < settings.ChunkWidth; x++ {
+			c := cell{baseX + x, baseY + y}
+			for ch := range queues {
+				if e.seed(channel(ch), c) {
+					queues[ch] = append(queues[ch], c)
+				}
+			}
+		}
+	}
+
+	// Light already in the neighbouring chunks flows in across the edges
+	var edges []cell
+	for y := baseY; y < baseY+settings.ChunkHeight; y++ {
+		edges = append(edges, cell{baseX - 1, y}, cell{baseX + settings.ChunkWidth, y})
+	}
+	for x := baseX; x < baseX+settings.ChunkWidth; x++ {
+		edges = append(edges, cell{x, baseY - 1}, cell{x, baseY + settings.ChunkHeight})
+	}
+	for _, c := range edges {
+		for ch := range queues {
+			if e.get(channel(ch), c) > 0 {
+				queues[ch] = append(queues[ch], c)
+			}
+		}
+	}
+	for ch, queue := range queues {
+		e.propagate(channel(ch), queue)
+	}
+
+	// The chunk below may have assumed open sky where this chunk now blocks it
+	if _, ok := e.chunks[coretypes.ChunkCoord{X: coord.X, Y: coord.Y + 1}]; ok {
+		bottom := baseY + settings.ChunkHeight - 1
+		for x := baseX; x < baseX+settings.ChunkWidth; x++ {
+			e.fixSky(cell{x, bottom}, cell{x, bottom + 1})
+		}
+	}
+}
+
+// fixSky relights a cell that was lit as if open sky were above it, if the real
+// block above now gives it less sunlight
+func (e *Engine) fixSky(above, c cell) {
+	if c.Y-1 >= e.surface(c.X) {
+		return // It was never assumed to see the sky
+	}
+	from, _ := e.blockAt(above)
+	to, _ := e.blockAt(c)
+	if received(sunLight, from, to, e.get(sunLight, above), true) < e.get(sunLight, c) {
+		e.relight(sunLight, c)
+	}
+}
+
+// seed gives a cell the light it makes itself: its block's emission, or sunlight if it
+// is at the top of the lit area and above the surface. It reports whether that raised
+// the cell's light.
+func (e *Engine) seed(ch channel, c cell) bool {
+	block, ok := e.blockAt(c)
+	if !ok {
+		return false
+	}
+	level := 0
+	switch ch {
+	case blockLight:
+		level = block.LightEmission()
+	case sunLight:
+		above := cell{c.X, c.Y - 1}
+		if _, loaded := e.blockAt(above); !loaded && above.Y < e.surface(c.X) {
+			level = received(sunLight, coretypes.Air, block, coretypes.MaxLight, true)
+		}
+	}
+	if level <= e.get(ch, c) {
+		return false
+	}
+	e.set(ch, c, level)
+	return true
+}
+
+// propagate floods light outward from the queued cells
+func (e *Engine) propagate(ch channel, queue []cell) {
+	for i := 0; i < len(queue); i++ {
+		c := queue[i]
+		from, _ := e.blockAt(c)
+		level := e.get(ch, c)
+		for d, n := range neighbours(c) {
+			to, ok := e.blockAt(n)
+			if !ok {
+				continue
+			}
+			if l := received(ch, from, to, level, d == 0); l > e.get(ch, n) {
+				e.set(ch, n, l)
+				queue = append(queue, n)
+			}
+		}
+	}
+}
+
+// relight clears the light that may have come through a cell, then refills the
+// cleared area from the light around it and from any sources inside it
+func (e *Engine) relight(ch channel, c cell) {
+	cleared := []removed{{c, e.get(ch, c)}}
+	e.set(ch, c, 0)
+	var sources []cell
+	for i := 0; i < len(cleared); i++ {
+		r := cleared[i]
+		for d, n := range neighbours(r.cell) {
+			level := e.get(ch, n)
+			if level == 0 {
+				continue
+			}
+			column := ch == sunLight && d == 0 && level == coretypes.MaxLight && r.level == coretypes.MaxLight
+			if level < r.level || column {
+				e.set(ch, n, 0)
+				cleared = append(cleared, removed{n, level})
+			} else {
+				sources = append(sources, n)
+			}
+		}
+	}
+	for _, r := range cleared {
+		if e.seed(ch, r.cell) {
+			sources = append(sources, r.cell)
+		}
+	}
+	e.propagate(ch, sources)
+}
+
+// received returns the light a block gets from a neighbour lit to level.
+// down is true when the neighbour is directly above.
+func received(ch channel, from, to coretypes.BlockType, level int, down bool) int {
+	if from.IsSolid() && !to.IsSolid() {
+		if ch == sunLight {
+			return 0
+		}
+		level = min(level, from.LightEmission()) // Only a block's own glow leaves it
+	}
+	if ch == sunLight && down && level == coretypes.MaxLight && !to.IsSolid() && !to.IsLiquid() {
+		return coretypes.MaxLight
+	}
+	return level - falloff(to)
+}
+
+// falloff returns the light lost entering a block
+func falloff(b coretypes.BlockType) int {
+	switch {
+	case b.IsSolid():
+		return settings.LightSolidFalloff
+	case b.IsLiquid():
+		return settings.LightLiquidFalloff
+	}
+	return 1
+}
+
+// blockAt returns the block in a cell, and false if its chunk isn't lit
+func (e *Engine) blockAt(c cell) (coretypes.BlockType, bool) {
+	coord, x, y := locate(c)
+	lc := e.chunks[coord]
+	if lc == nil {
+		return coretypes.Air, false
+	}
+	return lc.blocks.Blocks[y][x], true
+}
+
+func (e *Engine) get(ch channel, c cell) int {
+	coord, x, y := locate(c)
+	lc := e.chunks[coord]
+	if lc == nil {
+		return 0
+	}
+	return int(lc.levels[ch][y][x])
+}
+
+func (e *Engine) set(ch channel, c cell, level int) {
+	coord, x, y := locate(c)
+	if lc := e.chunks[coord]; lc != nil {
+		lc.levels[ch][y][x] = uint8(max(level, 0))
+	}
+}
+
+// neighbours lists the four adjacent cells, below first
+func neighbours(c cell) [4]cell {
+	return [4]cell{{c.X, c.Y + 1}, {c.X - 1, c.Y}, {c.X + 1, c.Y}, {c.X, c.Y - 1}}
+}
+
+// locate returns the chunk containing a cell and the cell's position inside it
+func locate(c cell) (coretypes.ChunkCoord, int, int) {
+	return coretypes.LocateBlock(c.X, c.Y)
+}
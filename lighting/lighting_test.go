@@ -0,0 +1,124 @@
+package lighting
+
+import (
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// surfaceY is where the test world's stone starts; everything above it is air
+const surfaceY = settings.ChunkHeight / 2
+
+// testWorld is two chunks side by side, open to the sky above surfaceY
+type testWorld map[coretypes.ChunkCoord]*coretypes.Chunk
+
+func newTestWorld() testWorld {
+	w := testWorld{}
+	for cx := 0; cx < 2; cx++ {
+		chunk := &coretypes.Chunk{Blocks: make([][]coretypes.BlockType, settings.ChunkHeight)}
+		for y := range chunk.Blocks {
+			chunk.Blocks[y] = make([]coretypes.BlockType, settings.ChunkWidth)
+			for x := range chunk.Blocks[y] {
+				if y >= surfaceY {
+					chunk.Blocks[y][x] = coretypes.Stone
+				}
+			}
+		}
+		w[coretypes.ChunkCoord{X: cx, Y: 0}] = chunk
+	}
+	return w
+}
+
+func (w testWorld) LoadedChunks() map[coretypes.ChunkCoord]*coretypes.Chunk {
+	return w
+}
+
+func (w testWorld) set(x, y int, block coretypes.BlockType) {
+	w[coretypes.ChunkCoord{X: x / settings.ChunkWidth}].Blocks[y][x%settings.ChunkWidth] = block
+}
+
+func newTestEngine(w testWorld) *Engine {
+	e := New(w, func(int) int { return surfaceY })
+	e.Sync()
+	return e
+}
+
+func TestSunlightFallsToTheSurface(t *testing.T) {
+	e := newTestEngine(newTestWorld())
+	for _, x := range []int{0, 15, 16, 31} {
+		if got := e.Sunlight(x, surfaceY-1); got != coretypes.MaxLight {
+			t.Errorf("open cell above the surface at x=%d has sunlight %d", x, got)
+		}
+		if got := e.Sunlight(x, surfaceY+5); got != 0 {
+			t.Errorf("stone deep underground at x=%d has sunlight %d", x, got)
+		}
+	}
+	if got := e.Sunlight(4, surfaceY); got != coretypes.MaxLight-settings.LightSolidFalloff {
+		t.Errorf("top stone has sunlight %d, want it lit from the open side", got)
+	}
+}
+
+func TestTorchLightCrossesChunks(t *testing.T) {
+	w := newTestWorld()
+	y := surfaceY + 20
+	for x := 12; x <= 20; x++ {
+		w.set(x, y, coretypes.Air)
+	}
+	w.set(22, y, coretypes.Air)                    // A pocket behind a one-block wall
+	w.set(settings.ChunkWidth, y, coretypes.Torch) // First column of the second chunk
+	e := newTestEngine(w)
+
+	emission := coretypes.Torch.LightEmission()
+	for dx := -4; dx <= 4; dx++ {
+		want := emission - max(dx, -dx)
+		if got := e.BlockLight(settings.ChunkWidth+dx, y); got != want {
+			t.Errorf("block light %d blocks from the torch is %d, want %d", dx, got, want)
+		}
+	}
+	if got := e.BlockLight(settings.ChunkWidth, y+1); got != emission-settings.LightSolidFalloff {
+		t.Errorf("stone under the torch has block light %d", got)
+	}
+	if got := e.BlockLight(21, y); got != emission-4-settings.LightSolidFalloff {
+		t.Errorf("wall next to the cave has block light %d", got)
+	}
+	if got := e.BlockLight(22, y); got != 0 {
+		t.Errorf("light came back out of the wall: %d", got)
+	}
+	if got := e.Sunlight(settings.ChunkWidth, y); got != 0 {
+		t.Errorf("closed cave has sunlight %d", got)
+	}
+}
+
+func TestBlockChangedMatchesFullRelight(t *testing.T) {
+	w := newTestWorld()
+	e := newTestEngine(w)
+
+	edits := []struct {
+		x, y  int
+		block coretypes.BlockType
+	}{
+		// Dig a shaft across the chunk edge and a room at the bottom
+		{15, surfaceY, coretypes.Air}, {15, surfaceY + 1, coretypes.Air}, {16, surfaceY + 1, coretypes.Air},
+		{16, surfaceY + 2, coretypes.Air}, {16, surfaceY + 3, coretypes.Air}, {17, surfaceY + 3, coretypes.Air},
+		{18, surfaceY + 3, coretypes.Torch},
+		// Put a roof over the shaft, then take the torch away again
+		{15, surfaceY - 3, coretypes.Stone},
+		{18, surfaceY + 3, coretypes.Air},
+		{14, surfaceY + 1, coretypes.Water},
+	}
+	for i, edit := range edits {
+		w.set(edit.x, edit.y, edit.block)
+		e.BlockChanged(edit.x, edit.y)
+
+		fresh := newTestEngine(w)
+		for x := 0; x < 2*settings.ChunkWidth; x++ {
+			for y := 0; y < settings.ChunkHeight; y++ {
+				if e.Sunlight(x, y) != fresh.Sunlight(x, y) || e.BlockLight(x, y) != fresh.BlockLight(x, y) {
+					t.Fatalf("after edit %d, (%d, %d) has sun %d block %d; relit from scratch it has sun %d block %d",
+						i, x, y, e.Sunlight(x, y), e.BlockLight(x, y), fresh.Sunlight(x, y), fresh.BlockLight(x, y))
+				}
+			}
+		}
+	}
+}
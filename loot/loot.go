@@ -0,0 +1,158 @@
+package loot
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+//go:embed tables.json
+var defaultTablesJSON []byte
+
+// tables is the loot table registry in use, and the item registry it was built against
+var (
+	tables      *Tables
+	tablesItems *coretypes.ItemRegistry
+)
+
+// Entry is one possible drop: between Min and Max of an item, dropped with a chance
+type Entry struct {
+	Item     coretypes.ItemID
+	Min, Max int
+	Chance   float64 // 0 to 1
+}
+
+// entryJSON is an entry as written in the loot table file, with the item named
+type entryJSON struct {
+	Item   string   `json:"item"`
+	Count  []int    `json:"count,omitempty"`  // [min, max], or [n]; defaults to 1
+	Chance *float64 `json:"chance,omitempty"` // Defaults to always
+}
+
+// Tables holds the loot table of each block that has one. Blocks without one drop
+// their registry drop (coretypes.BlockType.Drop), one at a time.
+type Tables struct {
+	byBlock map[coretypes.BlockType][]Entry
+}
+
+// Parse reads loot tables from JSON. Tables naming blocks or items that don't exist
+// are left out and reported in the error; the rest are still usable.
+func Parse(data []byte) (*Tables, error) {
+	var parsed struct {
+		Tables map[string][]entryJSON `json:"tables"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+
+	t := &Tables{byBlock: make(map[coretypes.BlockType][]Entry, len(parsed.Tables))}
+	var errs []error
+	for name, raw := range parsed.Tables {
+		block, ok := coretypes.BlockTypeByName(name)
+		if !ok {
+			errs = append(errs, fmt.Errorf("table for unknown block %q", name))
+			continue
+		}
+		entries, err := parseEntries(raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("table for %s: %w", name, err))
+			continue
+		}
+		t.byBlock[block] = entries
+	}
+	return t, errors.Join(errs...)
+}
+
+// parseEntries resolves a table's item names and fills in the defaults
+func parseEntries(raw []entryJSON) ([]Entry, error) {
+	entries := make([]Entry, 0, len(raw))
+	for _, r := range raw {
+		item, ok := coretypes.ItemByName(r.Item)
+		if !ok || item == coretypes.NoItem {
+			return nil, fmt.Errorf("unknown item %q", r.Item)
+		}
+		e := Entry{Item: item, Min: 1, Max: 1, Chance: 1}
+		switch len(r.Count) {
+		case 0:
+		case 1:
+			e.Min, e.Max = r.Count[0], r.Count[0]
+		case 2:
+			e.Min, e.Max = r.Count[0], r.Count[1]
+		default:
+			return nil, fmt.Errorf("%s: count needs one or two numbers", r.Item)
+		}
+		if e.Min < 0 || e.Max < e.Min {
+			return nil, fmt.Errorf("%s: count must be 0 or more, smallest first", r.Item)
+		}
+		if r.Chance != nil {
+			e.Chance = *r.Chance
+		}
+		if e.Chance < 0 || e.Chance > 1 {
+			return nil, fmt.Errorf("%s: chance must be between 0 and 1", r.Item)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Default returns the built-in loot tables, rebuilding them if the item registry
+// has changed since
+func Default() *Tables {
+	if tables == nil || tablesItems != coretypes.Items() {
+		registry, err := Parse(defaultTablesJSON)
+		if err != nil {
+			fmt.Printf("LOOT: Skipping loot tables: %v\n", err)
+		}
+		if registry == nil {
+			registry = &Tables{}
+		}
+		tables, tablesItems = registry, coretypes.Items()
+	}
+	return tables
+}
+
+// Table returns a block's loot table, or nil if it drops its registry drop
+func (t *Tables) Table(block coretypes.BlockType) []Entry {
+	return t.byBlock[block]
+}
+
+// Roll returns what breaking a block drops. Entries are rolled in table order, so
+// the same random source always gives the same drops.
+func (t *Tables) Roll(block coretypes.BlockType, rng *rand.Rand) []coretypes.ItemStack {
+	entries, ok := t.byBlock[block]
+	if !ok {
+		if drop := block.Drop(); drop != coretypes.Air {
+			return []coretypes.ItemStack{coretypes.NewStack(drop.Item(), 1)}
+		}
+		return nil
+	}
+
+	var drops []coretypes.ItemStack
+	for _, e := range entries {
+		if e.Chance < 1 && rng.Float64() >= e.Chance {
+			continue
+		}
+		count := e.Min
+		if e.Max > e.Min {
+			count += rng.Intn(e.Max - e.Min + 1)
+		}
+		if count > 0 {
+			drops = append(drops, coretypes.NewStack(e.Item, count))
+		}
+	}
+	return drops
+}
+
+// Rand returns the random source for the loot of a block broken at a tick of a
+// world, so every peer and every replay rolls the same drops
+func Rand(seed int64, blockX, blockY int, tick uint64) *rand.Rand {
+	h := uint64(seed) ^ uint64(int64(blockX))*0x9e3779b97f4a7c15 ^ uint64(int64(blockY))*0xc2b2ae3d27d4eb4f ^ tick*0x165667b19e3779f9
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	return rand.New(rand.NewSource(int64(h)))
+}
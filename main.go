@@ -1,27 +1,68 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"io/fs"
 	"log"
 
 	"github.com/hajimehoshi/ebiten/v2"
 
 	game "github.com/KdntNinja/webcraft/engine"
+	"github.com/KdntNinja/webcraft/netclient"
+	"github.com/KdntNinja/webcraft/settings"
 )
 
 func main() {
 	log.Println("Starting Webcraft...")
 
 	// Graphics settings for performance
-	ebiten.SetVsyncEnabled(true) // Prevent screen tearing
-	ebiten.SetTPS(60)            // 60 ticks per second
+	ebiten.SetVsyncEnabled(true)           // Prevent screen tearing
+	ebiten.SetTPS(settings.TicksPerSecond) // Fixed simulation tick rate
 
 	// Set window size hint for better performance
 	ebiten.SetWindowSize(1280, 720)
 	ebiten.SetWindowTitle("Webcraft")
 
-	// World creation is handled inside game.NewGame()
-	g := game.NewGame()
+	replayName := flag.String("replay", "", "play back a replay file from the replay directory instead of playing")
+	serverURL := flag.String("connect", netclient.DefaultServerURL(), "join the game server at this WebSocket URL")
+	flag.Parse()
+
+	game.LoadBlockDefinitions()
+
+	var g *game.Game
+	var err error
+	switch {
+	case *replayName != "":
+		if g, err = game.NewReplayGame(*replayName); err != nil {
+			log.Fatal(err)
+		}
+	case *serverURL != "":
+		if g, err = game.NewNetworkGame(*serverURL); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		// Resume the saved world if there is one, otherwise create a new one. A save
+		// that can't be read is moved aside by NewGame, never deleted.
+		g, err = game.LoadGame(game.DefaultSavePath())
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				log.Printf("Could not load saved world, moving it aside and starting a new one: %v", err)
+			}
+			g = game.NewGame()
+		}
+	}
 	if err := ebiten.RunGame(g); err != nil {
 		log.Fatal(err)
 	}
+
+	// Save on a clean exit (native builds; the browser relies on quicksave/autosave)
+	if *replayName == "" && *serverURL == "" {
+		if err := g.Save(g.SavePath); err != nil {
+			log.Printf("Failed to save world: %v", err)
+		}
+		if err := g.SaveReplay(); err != nil {
+			log.Printf("Failed to save replay: %v", err)
+		}
+	}
 }
@@ -0,0 +1,42 @@
+//go:build js && wasm
+
+package netclient
+
+import (
+	"net/url"
+	"syscall/js"
+
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// DefaultServerURL returns the server named in the page URL: ?server=ws://host/ws,
+// or ?multiplayer to join the server that served the page. It is empty for single player.
+func DefaultServerURL() string {
+	location := js.Global().Get("location")
+	if !location.Truthy() {
+		return ""
+	}
+	query, err := url.ParseQuery(trimQuery(location.Get("search").String()))
+	if err != nil {
+		return ""
+	}
+	if server := query.Get("server"); server != "" {
+		return server
+	}
+	if !query.Has("multiplayer") {
+		return ""
+	}
+	scheme := "ws://"
+	if location.Get("protocol").String() == "https:" {
+		scheme = "wss://"
+	}
+	return scheme + location.Get("host").String() + settings.ServerWebSocketPath
+}
+
+// trimQuery drops the leading '?' from location.search
+func trimQuery(search string) string {
+	if len(search) > 0 && search[0] == '?' {
+		return search[1:]
+	}
+	return search
+}
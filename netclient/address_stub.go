@@ -0,0 +1,8 @@
+//go:build !js || !wasm
+
+package netclient
+
+// DefaultServerURL returns "" on native builds; use the -connect flag to join a server
+func DefaultServerURL() string {
+	return ""
+}
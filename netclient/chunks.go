@@ -0,0 +1,207 @@
+package netclient
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/netproto"
+	"github.com/KdntNinja/webcraft/progress"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// ChunkManager implements coretypes.ChunkManager on top of a server connection.
+// Chunks around the player are requested as it moves and appear once the server
+// sends them; block changes are applied locally and sent to the server, which
+// answers with the authoritative block if it rejects them.
+type ChunkManager struct {
+	client       *Client
+	viewDistance int
+
+	mutex     sync.RWMutex
+	chunks    map[coretypes.ChunkCoord]*coretypes.Chunk
+	requested map[coretypes.ChunkCoord]bool // Requested but not received yet
+	center    coretypes.ChunkCoord
+}
+
+func newChunkManager(client *Client, viewDistance int) *ChunkManager {
+	return &ChunkManager{
+		client:       client,
+		viewDistance: viewDistance,
+		chunks:       make(map[coretypes.ChunkCoord]*coretypes.Chunk),
+		requested:    make(map[coretypes.ChunkCoord]bool),
+	}
+}
+
+// insert stores a chunk received from the server if it is still wanted
+func (cm *ChunkManager) insert(coord coretypes.ChunkCoord, chunk *coretypes.Chunk) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+	if !cm.requested[coord] {
+		return // Player moved away before it arrived
+	}
+	delete(cm.requested, coord)
+	cm.chunks[coord] = chunk
+}
+
+// inRange reports whether a chunk is close enough to the center to keep
+func (cm *ChunkManager) inRange(coord coretypes.ChunkCoord, distance int) bool {
+	return abs(coord.X-cm.center.X) <= distance && abs(coord.Y-cm.center.Y) <= distance
+}
+
+// GetChunk returns a loaded chunk, requesting it from the server if necessary
+func (cm *ChunkManager) GetChunk(chunkX, chunkY int) *coretypes.Chunk {
+	coord := coretypes.ChunkCoord{X: chunkX, Y: chunkY}
+
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+	if chunk, ok := cm.chunks[coord]; ok {
+		return chunk
+	}
+	if !cm.requested[coord] {
+		cm.requested[coord] = true
+		cm.client.send(&netproto.ChunkRequest{Coords: []coretypes.ChunkCoord{coord}})
+	}
+	return nil
+}
+
+// UpdatePlayerPosition requests chunks around the player and drops distant ones
+func (cm *ChunkManager) UpdatePlayerPosition(playerX, playerY float64) {
+	center := coretypes.ChunkCoordOfPixel(playerX, playerY)
+
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+	cm.center = center
+
+	// Keep one extra ring loaded so walking back and forth over a border doesn't refetch
+	for coord := range cm.chunks {
+		if !cm.inRange(coord, cm.viewDistance+1) {
+			delete(cm.chunks, coord)
+		}
+	}
+	for coord := range cm.requested {
+		if !cm.inRange(coord, cm.viewDistance+1) {
+			delete(cm.requested, coord)
+		}
+	}
+
+	var missing []coretypes.ChunkCoord
+	for dx := -cm.viewDistance; dx <= cm.viewDistance; dx++ {
+		for dy := -cm.viewDistance; dy <= cm.viewDistance; dy++ {
+			coord := coretypes.ChunkCoord{X: center.X + dx, Y: center.Y + dy}
+			if cm.chunks[coord] == nil && !cm.requested[coord] {
+				cm.requested[coord] = true
+				missing = append(missing, coord)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		cm.client.send(&netproto.ChunkRequest{Coords: missing})
+	}
+}
+
+// InitialLoadWithProgress requests the chunks around spawn and waits for them to arrive
+func (cm *ChunkManager) InitialLoadWithProgress(spawnX, spawnY float64) {
+	cm.UpdatePlayerPosition(spawnX, spawnY)
+
+	totalChunks := (cm.viewDistance*2 + 1) * (cm.viewDistance*2 + 1)
+	progress.SetCurrentStepSubSteps(totalChunks, fmt.Sprintf("Downloading %d initial chunks...", totalChunks))
+
+	deadline := time.Now().Add(settings.ClientInitialLoadTimeout * time.Second)
+	for time.Now().Before(deadline) && cm.client.Err() == nil {
+		loaded := cm.GetLoadedChunkCount()
+		progress.UpdateCurrentStepProgress(loaded, fmt.Sprintf("Received chunk %d/%d", loaded, totalChunks))
+		if loaded >= totalChunks {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	fmt.Printf("NETWORK: Only %d/%d chunks arrived before starting\n", cm.GetLoadedChunkCount(), totalChunks)
+}
+
+// GetAllChunks returns all currently loaded chunks (for rendering)
+func (cm *ChunkManager) GetAllChunks() map[coretypes.ChunkCoord]*coretypes.Chunk {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+	result := make(map[coretypes.ChunkCoord]*coretypes.Chunk, len(cm.chunks))
+	for coord, chunk := range cm.chunks {
+		result[coord] = chunk
+	}
+	return result
+}
+
+// GetLoadedChunkCount returns the number of currently loaded chunks
+func (cm *ChunkManager) GetLoadedChunkCount() int {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+	return len(cm.chunks)
+}
+
+// setLocal changes a loaded block and its liquid level without telling the server
+func (cm *ChunkManager) setLocal(blockX, blockY int, blockType coretypes.BlockType, level int) bool {
+	coord, inX, inY := coretypes.LocateBlock(blockX, blockY)
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+	chunk := cm.chunks[coord]
+	if chunk == nil || len(chunk.Blocks) == 0 {
+		return false
+	}
+	chunk.Blocks[inY][inX] = blockType
+	chunk.SetLiquidLevel(inX, inY, level)
+	return true
+}
+
+// SetBlock predicts a block change locally and asks the server to make it
+func (cm *ChunkManager) SetBlock(blockX, blockY int, blockType coretypes.BlockType) bool {
+	if !cm.setLocal(blockX, blockY, blockType, coretypes.MaxLiquidLevel) {
+		return false
+	}
+	if blockType == coretypes.Air {
+		cm.client.send(&netproto.BreakBlock{X: blockX, Y: blockY})
+	} else {
+		cm.client.send(&netproto.PlaceBlock{X: blockX, Y: blockY, Block: blockType})
+	}
+	return true
+}
+
+// SetLiquid changes a liquid cell locally. Liquids are simulated by the server, which
+// sends the results as block deltas.
+func (cm *ChunkManager) SetLiquid(blockX, blockY int, blockType coretypes.BlockType, level int) bool {
+	return cm.setLocal(blockX, blockY, blockType, level)
+}
+
+// GetBlock returns a loaded block, or Air if its chunk hasn't arrived
+func (cm *ChunkManager) GetBlock(blockX, blockY int) coretypes.BlockType {
+	coord, inX, inY := coretypes.LocateBlock(blockX, blockY)
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+	chunk := cm.chunks[coord]
+	if chunk == nil || len(chunk.Blocks) == 0 {
+		return coretypes.Air
+	}
+	return chunk.Blocks[inY][inX]
+}
+
+// SetChunkStore is ignored; the server owns persistence
+func (cm *ChunkManager) SetChunkStore(store coretypes.ChunkStore) {}
+
+// SetChunkListener is ignored; mobs aren't simulated in multiplayer
+func (cm *ChunkManager) SetChunkListener(listener coretypes.ChunkListener) {}
+
+// SaveDirtyChunks does nothing; the server saves the world
+func (cm *ChunkManager) SaveDirtyChunks() error {
+	return nil
+}
+
+// Shutdown disconnects from the server
+func (cm *ChunkManager) Shutdown() {
+	cm.client.Close()
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,283 @@
+package netclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/gameplay"
+	"github.com/KdntNinja/webcraft/gameplay/world"
+	"github.com/KdntNinja/webcraft/netproto"
+	"github.com/KdntNinja/webcraft/rollback"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// Client is a connection to a game server. Chunks stream into Chunks as they
+// arrive; everything else is queued and applied to the world by Sync on the game loop.
+type Client struct {
+	Welcome netproto.Welcome
+	Chunks  *ChunkManager
+
+	// Predictor, when set, records the local player each tick and rewinds and
+	// replays it when the server corrects a past position
+	Predictor *rollback.Predictor
+
+	conn     *websocket.Conn
+	ctx      context.Context
+	cancel   context.CancelFunc
+	outgoing chan []byte
+
+	mutex   sync.Mutex
+	inbox   []netproto.Message // Messages waiting for Sync, in arrival order
+	err     error              // Why the connection closed, once it has
+	remotes map[uint32]*RemotePlayer
+}
+
+// Dial connects to a game server and completes the version handshake
+func Dial(ctx context.Context, url, name string) (*Client, error) {
+	dialCtx, dialCancel := context.WithTimeout(ctx, settings.ClientConnectTimeout*time.Second)
+	defer dialCancel()
+
+	conn, _, err := websocket.Dial(dialCtx, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", url, err)
+	}
+	conn.SetReadLimit(1 << 20)
+
+	if err := conn.Write(dialCtx, websocket.MessageBinary, netproto.Encode(netproto.NewHello(name))); err != nil {
+		conn.CloseNow()
+		return nil, err
+	}
+	_, data, err := conn.Read(dialCtx)
+	if err != nil {
+		conn.CloseNow()
+		return nil, err
+	}
+	msg, err := netproto.Decode(data)
+	if err != nil {
+		conn.CloseNow()
+		return nil, err
+	}
+
+	var welcome *netproto.Welcome
+	switch m := msg.(type) {
+	case *netproto.Welcome:
+		welcome = m
+	case *netproto.Reject:
+		conn.CloseNow()
+		return nil, fmt.Errorf("server rejected connection: %s", m.Reason)
+	default:
+		conn.CloseNow()
+		return nil, fmt.Errorf("expected welcome, got message type %d", msg.Type())
+	}
+	if welcome.Version < netproto.MinProtocolVersion || welcome.Version > netproto.ProtocolVersion {
+		conn.CloseNow()
+		return nil, fmt.Errorf("server chose unsupported protocol version %d", welcome.Version)
+	}
+
+	c := &Client{
+		Welcome:  *welcome,
+		conn:     conn,
+		outgoing: make(chan []byte, settings.ServerSendBuffer),
+		remotes:  make(map[uint32]*RemotePlayer),
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	c.Chunks = newChunkManager(c, settings.ChunkViewDistance)
+
+	go c.readLoop()
+	go c.writeLoop()
+	fmt.Printf("NETWORK: Joined %s as player %d (protocol v%d)\n", url, welcome.PlayerID, welcome.Version)
+	return c, nil
+}
+
+// Err returns why the connection closed, or nil while it is open
+func (c *Client) Err() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.err
+}
+
+// Close disconnects from the server
+func (c *Client) Close() {
+	c.conn.Close(websocket.StatusNormalClosure, "")
+	c.fail(errors.New("disconnected"))
+}
+
+// fail records the first reason the connection ended and stops the loops
+func (c *Client) fail(err error) {
+	c.mutex.Lock()
+	if c.err == nil {
+		c.err = err
+	}
+	c.mutex.Unlock()
+	c.cancel()
+}
+
+// send queues a message for the server; messages are dropped once the connection is gone
+func (c *Client) send(m netproto.Message) {
+	select {
+	case c.outgoing <- netproto.Encode(m):
+	case <-c.ctx.Done():
+	default:
+		c.fail(errors.New("send buffer full"))
+	}
+}
+
+func (c *Client) writeLoop() {
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case data := <-c.outgoing:
+			if err := c.conn.Write(c.ctx, websocket.MessageBinary, data); err != nil {
+				c.fail(err)
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) readLoop() {
+	for {
+		_, data, err := c.conn.Read(c.ctx)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		msg, err := netproto.Decode(data)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+
+		switch m := msg.(type) {
+		case *netproto.ChunkData:
+			c.Chunks.insert(m.Coord, m.Chunk)
+		case *netproto.Reject:
+			c.fail(fmt.Errorf("server closed connection: %s", m.Reason))
+			return
+		default:
+			c.mutex.Lock()
+			c.inbox = append(c.inbox, msg)
+			c.mutex.Unlock()
+		}
+	}
+}
+
+// Sync applies queued server messages to the world and reports the local player's
+// state for this tick. Call it once per tick after the simulation step.
+func (c *Client) Sync(w *world.World, tick uint64) {
+	c.mutex.Lock()
+	inbox := c.inbox
+	c.inbox = nil
+	c.mutex.Unlock()
+
+	var local *gameplay.Player
+	if len(w.Entities) > 0 {
+		local, _ = w.Entities[0].(*gameplay.Player)
+	}
+	if local != nil && c.Predictor != nil {
+		c.Predictor.Record(tick, local.Input)
+	}
+
+	for _, msg := range inbox {
+		switch m := msg.(type) {
+		case *netproto.BlockDelta:
+			if c.Chunks.setLocal(m.X, m.Y, m.Block, int(m.Level)) {
+				w.MarkGridDirty()
+				w.Light.BlockChanged(m.X, m.Y)
+			}
+		case *netproto.BlockBatch:
+			for _, d := range m.Deltas {
+				if c.Chunks.setLocal(d.X, d.Y, d.Block, int(d.Level)) {
+					w.MarkGridDirty()
+					w.Light.BlockChanged(d.X, d.Y)
+				}
+			}
+		case *netproto.Inventory:
+			if local != nil {
+				applyInventory(local, m)
+			}
+		case *netproto.EntityDelta:
+			if m.ID == c.Welcome.PlayerID {
+				// The server refused a move; rewind to where it last accepted us
+				c.correct(local, m)
+				continue
+			}
+			c.updateRemote(w, m)
+		case *netproto.EntityRemove:
+			c.removeRemote(w, m.ID)
+		}
+	}
+
+	if local != nil && local.Input.Inventory.Action != coretypes.NoInventoryAction {
+		// The simulation applied it this tick; the server does the same and answers
+		// with the resulting inventory
+		c.send(&netproto.InventoryAction{Action: local.Input.Inventory.Action, Slot: local.Input.Inventory.Slot})
+	}
+	if local != nil {
+		state := &netproto.PlayerState{Tick: uint32(tick), X: local.X, Y: local.Y, VX: local.VX, VY: local.VY, Slot: uint8(local.SelectedSlot)}
+		state.MineX, state.MineY, _, state.Mining = local.MiningProgress()
+		c.send(state)
+	}
+}
+
+// updateRemote moves another player, adding it to the world the first time it is seen
+func (c *Client) updateRemote(w *world.World, m *netproto.EntityDelta) {
+	remote, ok := c.remotes[m.ID]
+	if !ok {
+		remote = &RemotePlayer{ID: m.ID}
+		c.remotes[m.ID] = remote
+		w.Entities = append(w.Entities, remote)
+	}
+	if !ok {
+		remote.X, remote.Y = m.X, m.Y
+	}
+	remote.addSample(m.X, m.Y, m.VX, m.VY)
+}
+
+// correct applies a server correction to the local player, replaying the inputs
+// since the corrected tick when a predictor is set
+func (c *Client) correct(local *gameplay.Player, m *netproto.EntityDelta) {
+	if local == nil {
+		return
+	}
+	if c.Predictor != nil {
+		c.Predictor.CorrectPosition(uint64(m.Tick), m.X, m.Y, m.VX, m.VY)
+		return
+	}
+	local.X, local.Y = m.X, m.Y
+	local.VX, local.VY = m.VX, m.VY
+}
+
+// removeRemote takes a player that left out of the world
+func (c *Client) removeRemote(w *world.World, id uint32) {
+	remote, ok := c.remotes[id]
+	if !ok {
+		return
+	}
+	delete(c.remotes, id)
+	for i, e := range w.Entities {
+		if e == remote {
+			w.Entities = append(w.Entities[:i], w.Entities[i+1:]...)
+			break
+		}
+	}
+}
+
+// applyInventory replaces a player's inventory slots and carried stack with the server's
+func applyInventory(p *gameplay.Player, m *netproto.Inventory) {
+	for i := 0; i < p.Inventory.Len(); i++ {
+		var s coretypes.ItemStack
+		if i < len(m.Slots) {
+			s = m.Slots[i]
+		}
+		p.Inventory.Set(i, s)
+	}
+	p.Cursor = m.Cursor
+}
@@ -0,0 +1,58 @@
+package netclient
+
+import (
+	"github.com/KdntNinja/webcraft/rollback"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// RemotePlayer is another client's player. Its position comes from the server,
+// so it is never simulated locally; it is interpolated between received states.
+type RemotePlayer struct {
+	ID     uint32
+	X, Y   float64 // Collider position, like gameplay.Player
+	VX, VY float64
+
+	clock  uint64 // Local ticks since the player was first seen
+	interp rollback.Interpolator
+}
+
+// addSample records a state received from the server at the current local tick
+func (r *RemotePlayer) addSample(x, y, vx, vy float64) {
+	r.interp.Add(rollback.Sample{Tick: float64(r.clock), X: x, Y: y, VX: vx, VY: vy})
+	r.VX, r.VY = vx, vy
+}
+
+// Update moves the player along its interpolated path
+func (r *RemotePlayer) Update() {
+	r.clock++
+	if x, y, ok := r.interp.At(float64(r.clock)); ok {
+		r.X, r.Y = x, y
+	}
+}
+
+func (r *RemotePlayer) ClampX(min, max float64) {
+	if r.X < min {
+		r.X = min
+	}
+	if r.X > max {
+		r.X = max
+	}
+}
+
+func (r *RemotePlayer) GetPosition() (float64, float64) {
+	return r.X, r.Y
+}
+
+func (r *RemotePlayer) SetPosition(x, y float64) {
+	r.X, r.Y = x, y
+}
+
+// GetColliderWidth returns the player collider width, so remote players are drawn like local ones
+func (r *RemotePlayer) GetColliderWidth() float64 {
+	return settings.PlayerColliderWidth
+}
+
+// GetColliderHeight returns the player collider height
+func (r *RemotePlayer) GetColliderHeight() float64 {
+	return settings.PlayerColliderHeight
+}
@@ -0,0 +1,124 @@
+package netproto
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var errShortMessage = errors.New("message truncated")
+
+// writer appends little-endian fields to a message buffer
+type writer struct {
+	buf []byte
+}
+
+func (w *writer) uint8(v uint8)   { w.buf = append(w.buf, v) }
+func (w *writer) uint16(v uint16) { w.buf = binary.LittleEndian.AppendUint16(w.buf, v) }
+func (w *writer) uint32(v uint32) { w.buf = binary.LittleEndian.AppendUint32(w.buf, v) }
+func (w *writer) uint64(v uint64) { w.buf = binary.LittleEndian.AppendUint64(w.buf, v) }
+func (w *writer) int64(v int64)   { w.buf = binary.LittleEndian.AppendUint64(w.buf, uint64(v)) }
+func (w *writer) varint(v int)    { w.buf = binary.AppendVarint(w.buf, int64(v)) }
+func (w *writer) uvarint(v int)   { w.buf = binary.AppendUvarint(w.buf, uint64(v)) }
+func (w *writer) float64(v float64) {
+	w.buf = binary.LittleEndian.AppendUint64(w.buf, math.Float64bits(v))
+}
+func (w *writer) bytes(v []byte) {
+	w.uvarint(len(v))
+	w.buf = append(w.buf, v...)
+}
+func (w *writer) string(v string) { w.bytes([]byte(v)) }
+
+// reader consumes fields written by writer. The first error sticks, so decoders
+// can read every field and check err once at the end.
+type reader struct {
+	buf []byte
+	err error
+}
+
+func (r *reader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.buf) < n {
+		r.err = errShortMessage
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *reader) uint8() uint8 {
+	if b := r.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) uint16() uint16 {
+	if b := r.take(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) uint32() uint32 {
+	if b := r.take(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) uint64() uint64 {
+	if b := r.take(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *reader) int64() int64 {
+	return int64(r.uint64())
+}
+
+func (r *reader) float64() float64 {
+	if b := r.take(8); b != nil {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (r *reader) varint() int {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = errShortMessage
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return int(v)
+}
+
+func (r *reader) uvarint() int {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 || v > math.MaxInt32 {
+		r.err = errShortMessage
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return int(v)
+}
+
+func (r *reader) bytes() []byte {
+	n := r.uvarint()
+	return append([]byte(nil), r.take(n)...)
+}
+
+func (r *reader) string() string {
+	return string(r.bytes())
+}
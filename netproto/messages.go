@@ -0,0 +1,402 @@
+package netproto
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/storage"
+)
+
+// MessageType is the first byte of every message
+type MessageType uint8
+
+const (
+	// Handshake
+	TypeHello   MessageType = 1 // client -> server
+	TypeWelcome MessageType = 2 // server -> client
+	TypeReject  MessageType = 3 // server -> client, connection is closed afterwards
+
+	// World streaming
+	TypeChunkRequest MessageType = 10 // client -> server
+	TypeChunkData    MessageType = 11 // server -> client
+
+	// Block interactions
+	TypeBreakBlock MessageType = 20 // client -> server
+	TypePlaceBlock MessageType = 21 // client -> server
+	TypeBlockDelta MessageType = 22 // server -> clients
+	TypeInventory  MessageType = 23 // server -> client
+	TypeBlockBatch MessageType = 24 // server -> clients
+
+	// Inventory
+	TypeInventoryAction MessageType = 25 // client -> server
+
+	// Entities
+	TypePlayerState  MessageType = 30 // client -> server
+	TypeEntityDelta  MessageType = 31 // server -> clients
+	TypeEntityRemove MessageType = 32 // server -> clients
+)
+
+// Message is a single protocol message
+type Message interface {
+	Type() MessageType
+	encode(w *writer)
+	decode(r *reader)
+}
+
+// Hello opens a connection and lists the protocol versions the client speaks
+type Hello struct {
+	MinVersion uint16
+	MaxVersion uint16
+	Name       string
+	Blocks     uint32 // Block registry checksum; block IDs only mean the same thing if it matches
+}
+
+// Welcome accepts a client and describes the world it joined
+type Welcome struct {
+	Version   uint16 // Negotiated protocol version
+	PlayerID  uint32
+	Seed      int64
+	SpawnX    float64
+	SpawnY    float64
+	Time      uint64 // World clock ticks
+	DayLength uint64 // Ticks per day
+}
+
+// Reject refuses a connection
+type Reject struct {
+	Reason string
+}
+
+// ChunkRequest asks the server to send the given chunks
+type ChunkRequest struct {
+	Coords []coretypes.ChunkCoord
+}
+
+// ChunkData carries one chunk, encoded with the save file chunk codec
+type ChunkData struct {
+	Coord coretypes.ChunkCoord
+	Chunk *coretypes.Chunk
+}
+
+// BreakBlock asks the server to break the block at X, Y
+type BreakBlock struct {
+	X, Y int
+}
+
+// PlaceBlock asks the server to place Block at X, Y from the player's inventory
+type PlaceBlock struct {
+	X, Y  int
+	Block coretypes.BlockType
+}
+
+// BlockDelta is the authoritative value of a block. It is broadcast when a block
+// changes and sent back to a client whose request was rejected.
+type BlockDelta struct {
+	X, Y  int
+	Block coretypes.BlockType
+	Level uint8 // Liquid fill level; 0 means full (and is used for every other block)
+}
+
+// BlockBatch carries many block changes at once, such as a tick of liquid flow
+type BlockBatch struct {
+	Deltas []BlockDelta
+}
+
+// Inventory is the authoritative contents of each inventory slot and of the stack
+// the player is carrying on the inventory screen
+type Inventory struct {
+	Slots  []coretypes.ItemStack
+	Cursor coretypes.ItemStack
+}
+
+// InventoryAction asks the server to apply an inventory screen action
+type InventoryAction struct {
+	Action coretypes.InventoryAction
+	Slot   int
+}
+
+// PlayerState reports the client's simulated player at a tick, along with the hotbar
+// slot in hand and the block being mined, so the server can time mining itself
+type PlayerState struct {
+	Tick         uint32
+	X, Y         float64
+	VX, VY       float64
+	Slot         uint8 // Selected hotbar slot
+	Mining       bool  // Whether the block at MineX, MineY is being broken
+	MineX, MineY int
+}
+
+// EntityDelta is the latest state of another player
+type EntityDelta struct {
+	ID     uint32
+	Tick   uint32
+	X, Y   float64
+	VX, VY float64
+}
+
+// EntityRemove tells clients an entity left the world
+type EntityRemove struct {
+	ID uint32
+}
+
+func (*Hello) Type() MessageType           { return TypeHello }
+func (*Welcome) Type() MessageType         { return TypeWelcome }
+func (*Reject) Type() MessageType          { return TypeReject }
+func (*ChunkRequest) Type() MessageType    { return TypeChunkRequest }
+func (*ChunkData) Type() MessageType       { return TypeChunkData }
+func (*BreakBlock) Type() MessageType      { return TypeBreakBlock }
+func (*PlaceBlock) Type() MessageType      { return TypePlaceBlock }
+func (*BlockDelta) Type() MessageType      { return TypeBlockDelta }
+func (*Inventory) Type() MessageType       { return TypeInventory }
+func (*InventoryAction) Type() MessageType { return TypeInventoryAction }
+func (*BlockBatch) Type() MessageType      { return TypeBlockBatch }
+func (*PlayerState) Type() MessageType     { return TypePlayerState }
+func (*EntityDelta) Type() MessageType     { return TypeEntityDelta }
+func (*EntityRemove) Type() MessageType    { return TypeEntityRemove }
+
+func (m *Hello) encode(w *writer) {
+	w.uint16(m.MinVersion)
+	w.uint16(m.MaxVersion)
+	w.string(m.Name)
+	w.uint32(m.Blocks)
+}
+
+func (m *Hello) decode(r *reader) {
+	m.MinVersion = r.uint16()
+	m.MaxVersion = r.uint16()
+	m.Name = r.string()
+	m.Blocks = r.uint32()
+}
+
+func (m *Welcome) encode(w *writer) {
+	w.uint16(m.Version)
+	w.uint32(m.PlayerID)
+	w.int64(m.Seed)
+	w.float64(m.SpawnX)
+	w.float64(m.SpawnY)
+	w.uint64(m.Time)
+	w.uint64(m.DayLength)
+}
+
+func (m *Welcome) decode(r *reader) {
+	m.Version = r.uint16()
+	m.PlayerID = r.uint32()
+	m.Seed = r.int64()
+	m.SpawnX = r.float64()
+	m.SpawnY = r.float64()
+	m.Time = r.uint64()
+	m.DayLength = r.uint64()
+}
+
+func (m *Reject) encode(w *writer) { w.string(m.Reason) }
+func (m *Reject) decode(r *reader) { m.Reason = r.string() }
+
+func (m *ChunkRequest) encode(w *writer) {
+	w.uvarint(len(m.Coords))
+	for _, c := range m.Coords {
+		w.varint(c.X)
+		w.varint(c.Y)
+	}
+}
+
+func (m *ChunkRequest) decode(r *reader) {
+	n := r.uvarint()
+	// Each coordinate takes at least two bytes, which bounds the allocation
+	m.Coords = make([]coretypes.ChunkCoord, 0, min(n, len(r.buf)/2))
+	for i := 0; i < n && r.err == nil; i++ {
+		m.Coords = append(m.Coords, coretypes.ChunkCoord{X: r.varint(), Y: r.varint()})
+	}
+}
+
+func (m *ChunkData) encode(w *writer) {
+	w.varint(m.Coord.X)
+	w.varint(m.Coord.Y)
+	w.bytes(storage.EncodeChunk(m.Chunk))
+}
+
+func (m *ChunkData) decode(r *reader) {
+	m.Coord.X = r.varint()
+	m.Coord.Y = r.varint()
+	payload := r.bytes()
+	if r.err != nil {
+		return
+	}
+	chunk, err := storage.DecodeChunk(payload)
+	if err != nil {
+		r.err = fmt.Errorf("chunk %d,%d: %w", m.Coord.X, m.Coord.Y, err)
+		return
+	}
+	m.Chunk = chunk
+}
+
+func (m *BreakBlock) encode(w *writer) {
+	w.varint(m.X)
+	w.varint(m.Y)
+}
+
+func (m *BreakBlock) decode(r *reader) {
+	m.X = r.varint()
+	m.Y = r.varint()
+}
+
+func (m *PlaceBlock) encode(w *writer) {
+	w.varint(m.X)
+	w.varint(m.Y)
+	w.uvarint(int(m.Block))
+}
+
+func (m *PlaceBlock) decode(r *reader) {
+	m.X = r.varint()
+	m.Y = r.varint()
+	m.Block = coretypes.BlockType(r.uvarint())
+}
+
+func (m *BlockDelta) encode(w *writer) {
+	w.varint(m.X)
+	w.varint(m.Y)
+	w.uvarint(int(m.Block))
+	w.uint8(m.Level)
+}
+
+func (m *BlockDelta) decode(r *reader) {
+	m.X = r.varint()
+	m.Y = r.varint()
+	m.Block = coretypes.BlockType(r.uvarint())
+	m.Level = r.uint8()
+}
+
+func (m *BlockBatch) encode(w *writer) {
+	w.uvarint(len(m.Deltas))
+	for i := range m.Deltas {
+		m.Deltas[i].encode(w)
+	}
+}
+
+func (m *BlockBatch) decode(r *reader) {
+	n := r.uvarint()
+	// Each delta takes at least four bytes, which bounds the allocation
+	m.Deltas = make([]BlockDelta, 0, min(n, len(r.buf)/4))
+	for i := 0; i < n && r.err == nil; i++ {
+		var d BlockDelta
+		d.decode(r)
+		m.Deltas = append(m.Deltas, d)
+	}
+}
+
+func (m *Inventory) encode(w *writer) {
+	w.uvarint(len(m.Slots))
+	for _, s := range m.Slots {
+		w.itemStack(s)
+	}
+	w.itemStack(m.Cursor)
+}
+
+func (m *Inventory) decode(r *reader) {
+	n := r.uvarint()
+	// Each slot takes at least four bytes, which bounds the allocation
+	m.Slots = make([]coretypes.ItemStack, 0, min(n, len(r.buf)/4))
+	for i := 0; i < n && r.err == nil; i++ {
+		m.Slots = append(m.Slots, r.itemStack())
+	}
+	m.Cursor = r.itemStack()
+}
+
+func (m *InventoryAction) encode(w *writer) {
+	w.uint8(uint8(m.Action))
+	w.uvarint(m.Slot)
+}
+
+func (m *InventoryAction) decode(r *reader) {
+	m.Action = coretypes.InventoryAction(r.uint8())
+	m.Slot = r.uvarint()
+}
+
+// itemStack writes a stack as its item ID, count, wear and metadata pairs in key
+// order, so equal stacks encode the same
+func (w *writer) itemStack(s coretypes.ItemStack) {
+	if s.IsEmpty() {
+		s = coretypes.ItemStack{}
+	}
+	w.uvarint(int(s.Item))
+	w.uvarint(s.Count)
+	w.uvarint(s.Damage)
+	keys := slices.Sorted(maps.Keys(s.Meta))
+	w.uvarint(len(keys))
+	for _, k := range keys {
+		w.string(k)
+		w.string(s.Meta[k])
+	}
+}
+
+// itemStack reads a stack written by writer.itemStack. Items this build doesn't
+// know about come back empty.
+func (r *reader) itemStack() coretypes.ItemStack {
+	s := coretypes.ItemStack{
+		Item:   coretypes.ItemID(r.uvarint()),
+		Count:  r.uvarint(),
+		Damage: r.uvarint(),
+	}
+	pairs := r.uvarint()
+	for j := 0; j < pairs && r.err == nil; j++ {
+		if s.Meta == nil {
+			s.Meta = make(map[string]string)
+		}
+		k := r.string()
+		s.Meta[k] = r.string()
+	}
+	if s.Item.Def() == nil || s.IsEmpty() {
+		return coretypes.ItemStack{}
+	}
+	return s
+}
+
+func (m *PlayerState) encode(w *writer) {
+	w.uint32(m.Tick)
+	w.float64(m.X)
+	w.float64(m.Y)
+	w.float64(m.VX)
+	w.float64(m.VY)
+	w.uint8(m.Slot)
+	mining := uint8(0)
+	if m.Mining {
+		mining = 1
+	}
+	w.uint8(mining)
+	w.varint(m.MineX)
+	w.varint(m.MineY)
+}
+
+func (m *PlayerState) decode(r *reader) {
+	m.Tick = r.uint32()
+	m.X = r.float64()
+	m.Y = r.float64()
+	m.VX = r.float64()
+	m.VY = r.float64()
+	m.Slot = r.uint8()
+	m.Mining = r.uint8() != 0
+	m.MineX = r.varint()
+	m.MineY = r.varint()
+}
+
+func (m *EntityDelta) encode(w *writer) {
+	w.uint32(m.ID)
+	w.uint32(m.Tick)
+	w.float64(m.X)
+	w.float64(m.Y)
+	w.float64(m.VX)
+	w.float64(m.VY)
+}
+
+func (m *EntityDelta) decode(r *reader) {
+	m.ID = r.uint32()
+	m.Tick = r.uint32()
+	m.X = r.float64()
+	m.Y = r.float64()
+	m.VX = r.float64()
+	m.VY = r.float64()
+}
+
+func (m *EntityRemove) encode(w *writer) { w.uint32(m.ID) }
+func (m *EntityRemove) decode(r *reader) { m.ID = r.uint32() }
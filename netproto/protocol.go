@@ -0,0 +1,117 @@
+package netproto
+
+import (
+	"fmt"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+// Protocol versions this build can speak. Bump ProtocolVersion whenever a message
+// changes shape; raise MinProtocolVersion once older clients are no longer supported.
+const (
+	ProtocolVersion    uint16 = 8
+	MinProtocolVersion uint16 = 8
+)
+
+// Negotiate picks the highest protocol version both sides support
+func Negotiate(peerMin, peerMax uint16) (uint16, error) {
+	version := ProtocolVersion
+	if peerMax < version {
+		version = peerMax
+	}
+	if version < MinProtocolVersion || version < peerMin {
+		return 0, fmt.Errorf("no common protocol version (server %d-%d, client %d-%d)",
+			MinProtocolVersion, ProtocolVersion, peerMin, peerMax)
+	}
+	return version, nil
+}
+
+// NewHello returns the handshake message for this build
+func NewHello(name string) *Hello {
+	return &Hello{
+		MinVersion: MinProtocolVersion,
+		MaxVersion: ProtocolVersion,
+		Name:       name,
+		Blocks:     coretypes.Blocks().Checksum(),
+	}
+}
+
+// Encode serializes a message: one type byte followed by the message fields
+func Encode(m Message) []byte {
+	w := &writer{buf: []byte{byte(m.Type())}}
+	m.encode(w)
+	return w.buf
+}
+
+// Decode parses a message produced by Encode
+func Decode(data []byte) (Message, error) {
+	if len(data) == 0 {
+		return nil, errShortMessage
+	}
+	m := newMessage(MessageType(data[0]))
+	if m == nil {
+		return nil, fmt.Errorf("unknown message type %d", data[0])
+	}
+	r := &reader{buf: data[1:]}
+	m.decode(r)
+	if r.err != nil {
+		return nil, fmt.Errorf("decoding message type %d: %w", data[0], r.err)
+	}
+	return m, nil
+}
+
+// DecodeFromClient parses a message received from a client. Messages that only the
+// server sends are refused before their fields are read.
+func DecodeFromClient(data []byte) (Message, error) {
+	if len(data) == 0 {
+		return nil, errShortMessage
+	}
+	if !sentByClient(MessageType(data[0])) {
+		return nil, fmt.Errorf("message type %d is not sent by clients", data[0])
+	}
+	return Decode(data)
+}
+
+// sentByClient reports whether clients send messages of the given type
+func sentByClient(t MessageType) bool {
+	switch t {
+	case TypeHello, TypeChunkRequest, TypeBreakBlock, TypePlaceBlock, TypeInventoryAction, TypePlayerState:
+		return true
+	}
+	return false
+}
+
+// newMessage returns an empty message of the given type, or nil if it is unknown
+func newMessage(t MessageType) Message {
+	switch t {
+	case TypeHello:
+		return &Hello{}
+	case TypeWelcome:
+		return &Welcome{}
+	case TypeReject:
+		return &Reject{}
+	case TypeChunkRequest:
+		return &ChunkRequest{}
+	case TypeChunkData:
+		return &ChunkData{}
+	case TypeBreakBlock:
+		return &BreakBlock{}
+	case TypePlaceBlock:
+		return &PlaceBlock{}
+	case TypeBlockDelta:
+		return &BlockDelta{}
+	case TypeInventory:
+		return &Inventory{}
+	case TypeBlockBatch:
+		return &BlockBatch{}
+	case TypeInventoryAction:
+		return &InventoryAction{}
+	case TypePlayerState:
+		return &PlayerState{}
+	case TypeEntityDelta:
+		return &EntityDelta{}
+	case TypeEntityRemove:
+		return &EntityRemove{}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package netproto
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+	"github.com/KdntNinja/webcraft/storage"
+)
+
+func TestChunkDataRoundTrip(t *testing.T) {
+	chunk := emptyChunk()
+	chunk.Blocks[3][4] = coretypes.Stone
+	msg, err := Decode(Encode(&ChunkData{Coord: coretypes.ChunkCoord{X: -2, Y: 5}, Chunk: chunk}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := msg.(*ChunkData)
+	if got.Coord != (coretypes.ChunkCoord{X: -2, Y: 5}) || got.Chunk.Blocks[3][4] != coretypes.Stone {
+		t.Fatalf("chunk data changed in transit: %+v", got.Coord)
+	}
+}
+
+func TestChunkDataRejectsOversizedChunk(t *testing.T) {
+	// A chunk header claiming 65535x65535 blocks with no block data behind it
+	payload := binary.LittleEndian.AppendUint16(nil, storage.BlockFormatVersion)
+	payload = binary.LittleEndian.AppendUint16(payload, 0xFFFF)
+	payload = binary.LittleEndian.AppendUint16(payload, 0xFFFF)
+	payload = append(payload, 1, 0, 'x')
+	w := &writer{buf: []byte{byte(TypeChunkData)}}
+	w.varint(0)
+	w.varint(0)
+	w.bytes(payload)
+
+	if _, err := Decode(w.buf); err == nil {
+		t.Fatalf("decoded a chunk larger than %dx%d", settings.ChunkWidth, settings.ChunkHeight)
+	}
+}
+
+func TestDecodeFromClientRefusesServerMessages(t *testing.T) {
+	serverOnly := []Message{
+		&Welcome{},
+		&Reject{},
+		&ChunkData{Chunk: emptyChunk()},
+		&BlockDelta{},
+		&Inventory{},
+		&BlockBatch{},
+		&EntityDelta{},
+		&EntityRemove{},
+	}
+	for _, m := range serverOnly {
+		if _, err := DecodeFromClient(Encode(m)); err == nil {
+			t.Errorf("accepted message type %d from a client", m.Type())
+		}
+	}
+
+	msg, err := DecodeFromClient(Encode(&PlayerState{Tick: 7}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.(*PlayerState).Tick != 7 {
+		t.Fatalf("player state tick = %d, want 7", msg.(*PlayerState).Tick)
+	}
+}
+
+// emptyChunk returns an all-air chunk of the standard size
+func emptyChunk() *coretypes.Chunk {
+	chunk := &coretypes.Chunk{Blocks: make([][]coretypes.BlockType, settings.ChunkHeight)}
+	for y := range chunk.Blocks {
+		chunk.Blocks[y] = make([]coretypes.BlockType, settings.ChunkWidth)
+	}
+	return chunk
+}
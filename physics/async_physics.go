@@ -6,7 +6,6 @@ import (
 	"sync"
 
 	"github.com/KdntNinja/webcraft/coretypes"
-	"github.com/KdntNinja/webcraft/settings"
 )
 
 // PhysicsUpdateJob represents a physics update job
@@ -25,9 +24,7 @@ type AsyncPhysicsSystem struct {
 	cancel     context.CancelFunc
 
 	// Spatial partitioning for better performance
-	spatialGrid map[int]map[int][]coretypes.Entity
-	gridMutex   sync.RWMutex
-	cellSize    int
+	grid *SpatialGrid
 }
 
 var (
@@ -40,12 +37,11 @@ func GetAsyncPhysicsSystem() *AsyncPhysicsSystem {
 	physicsOnce.Do(func() {
 		ctx, cancel := context.WithCancel(context.Background())
 		asyncPhysics = &AsyncPhysicsSystem{
-			jobs:        make(chan PhysicsUpdateJob, 256),
-			numWorkers:  runtime.NumCPU(),
-			ctx:         ctx,
-			cancel:      cancel,
-			spatialGrid: make(map[int]map[int][]coretypes.Entity),
-			cellSize:    settings.TileSize * 4, // Each cell is 4x4 tiles
+			jobs:       make(chan PhysicsUpdateJob, 256),
+			numWorkers: runtime.NumCPU(),
+			ctx:        ctx,
+			cancel:     cancel,
+			grid:       NewSpatialGrid(),
 		}
 		asyncPhysics.startWorkers()
 	})
@@ -96,55 +92,12 @@ func (aps *AsyncPhysicsSystem) processPhysicsJob(job PhysicsUpdateJob) {
 
 // UpdateSpatialGrid updates the spatial partitioning grid
 func (aps *AsyncPhysicsSystem) UpdateSpatialGrid(entities []coretypes.Entity) {
-	aps.gridMutex.Lock()
-	defer aps.gridMutex.Unlock()
-
-	// Clear the grid
-	aps.spatialGrid = make(map[int]map[int][]coretypes.Entity)
-
-	// Add entities to spatial grid
-	for _, e := range entities {
-		x, y := e.GetPosition()
-		cellX := int(x) / aps.cellSize
-		cellY := int(y) / aps.cellSize
-
-		if aps.spatialGrid[cellX] == nil {
-			aps.spatialGrid[cellX] = make(map[int][]coretypes.Entity)
-		}
-		aps.spatialGrid[cellX][cellY] = append(aps.spatialGrid[cellX][cellY], e)
-	}
+	aps.grid.Update(entities)
 }
 
 // GetEntitiesInRadius returns entities within a radius of a position
 func (aps *AsyncPhysicsSystem) GetEntitiesInRadius(x, y, radius float64) []coretypes.Entity {
-	aps.gridMutex.RLock()
-	defer aps.gridMutex.RUnlock()
-
-	var results []coretypes.Entity
-	cellRadius := int(radius)/aps.cellSize + 1
-	centerCellX := int(x) / aps.cellSize
-	centerCellY := int(y) / aps.cellSize
-
-	for dx := -cellRadius; dx <= cellRadius; dx++ {
-		for dy := -cellRadius; dy <= cellRadius; dy++ {
-			cellX := centerCellX + dx
-			cellY := centerCellY + dy
-
-			if cells, exists := aps.spatialGrid[cellX]; exists {
-				if entities, exists := cells[cellY]; exists {
-					for _, entity := range entities {
-						ex, ey := entity.GetPosition()
-						distance := (ex-x)*(ex-x) + (ey-y)*(ey-y)
-						if distance <= radius*radius {
-							results = append(results, entity)
-						}
-					}
-				}
-			}
-		}
-	}
-
-	return results
+	return aps.grid.GetEntitiesInRadius(x, y, radius)
 }
 
 // SubmitPhysicsJob submits a physics job to the worker pool
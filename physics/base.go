@@ -8,6 +8,8 @@ type AABB struct {
 	OnGround      bool    // Whether entity is touching ground
 	GridOffsetX   int     // Offset for collision grid X (for infinite world)
 	GridOffsetY   int     // Offset for collision grid Y (for infinite world)
+	Submerged     float64 // Fraction of the collider inside liquid, set by ApplyLiquids
+	Impact        float64 // Downward speed the collider hit the ground with this tick, set by CollideBlocks
 }
 
 // Entity interface implementations for AABB
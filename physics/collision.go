@@ -54,6 +54,7 @@ func (a *AABB) CollideBlocks(world *PhysicsWorld) {
 
 	// Move vertically with sub-stepping
 	a.OnGround = false
+	a.Impact = 0
 	if a.VY != 0 {
 		steps := 1
 		if a.VY > tileSize/2 || a.VY < -tileSize/2 {
@@ -71,6 +72,7 @@ func (a *AABB) CollideBlocks(world *PhysicsWorld) {
 				for x := int(a.X / tileSize); x <= int((a.X+float64(a.Width)-1)/tileSize); x++ {
 					if IsSolid(blocks, x, bottomEdge, a.GridOffsetX, a.GridOffsetY) {
 						a.Y = float64(bottomEdge)*tileSize - float64(a.Height)
+						a.Impact = a.VY
 						a.VY = 0
 						a.OnGround = true
 						break
@@ -0,0 +1,130 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// HazardConfig sets how much the environment hurts an entity
+type HazardConfig struct {
+	SafeFallSpeed      float64 // Landing speed that still deals no damage
+	FallDamagePerSpeed float64 // Damage per pixel per tick of landing speed above SafeFallSpeed
+	MaxBreath          int     // Ticks the head can stay under liquid before drowning; 0 never drowns
+	BreathRecovery     int     // Breath regained per tick with the head out of liquid
+	DrownDamage        int     // Damage of each drowning hit
+	DrownInterval      int     // Ticks between drowning hits
+	BurnDamage         int     // Damage of each burn; 0 never burns
+	BurnInterval       int     // Ticks between burns
+	BurnTicks          int     // Ticks the entity keeps burning after it stops touching a burning block
+}
+
+// DefaultHazards returns the player's hazard settings
+func DefaultHazards() HazardConfig {
+	return HazardConfig{
+		SafeFallSpeed:      settings.SafeFallSpeed,
+		FallDamagePerSpeed: settings.FallDamagePerSpeed,
+		MaxBreath:          settings.MaxBreath,
+		BreathRecovery:     settings.BreathRecovery,
+		DrownDamage:        settings.DrownDamage,
+		DrownInterval:      settings.DrownInterval,
+		BurnDamage:         settings.BurnDamage,
+		BurnInterval:       settings.BurnInterval,
+		BurnTicks:          settings.BurnTicks,
+	}
+}
+
+// HazardState is an entity's breath and burning between ticks. The zero value is
+// an entity with full breath that isn't on fire.
+type HazardState struct {
+	BreathUsed int // Ticks of breath used up with the head under liquid
+	Burning    int // Ticks left on fire
+	drownTimer int // Ticks until the next drowning hit
+	burnTimer  int // Ticks until the next burn
+}
+
+// Breath returns the ticks of breath left
+func (h *HazardState) Breath(config HazardConfig) int {
+	return max(config.MaxBreath-h.BreathUsed, 0)
+}
+
+// ApplyHazards works out the damage the entity takes this tick from a hard landing,
+// drowning and burning. Call it after CollideBlocks, which sets Impact, and
+// ApplyLiquids, which sets Submerged.
+func (a *AABB) ApplyHazards(world *PhysicsWorld, state *HazardState, config HazardConfig) int {
+	damage := 0
+
+	// Fall damage from the speed the entity hit the ground with; liquid drag
+	// slows a fall into water before it lands
+	if a.Impact > config.SafeFallSpeed {
+		damage += int(math.Ceil((a.Impact - config.SafeFallSpeed) * config.FallDamagePerSpeed))
+	}
+
+	if world == nil {
+		return damage
+	}
+
+	// Breath runs out with the head under liquid, and comes back above it
+	if config.MaxBreath > 0 {
+		if a.headBlock(world).IsLiquid() {
+			state.BreathUsed = min(state.BreathUsed+1, config.MaxBreath)
+		} else {
+			state.BreathUsed = max(state.BreathUsed-config.BreathRecovery, 0)
+			state.drownTimer = 0
+		}
+		if state.BreathUsed >= config.MaxBreath {
+			if state.drownTimer <= 0 {
+				damage += config.DrownDamage
+				state.drownTimer = config.DrownInterval
+			}
+			state.drownTimer--
+		}
+	}
+
+	// Touching a burning block sets the entity on fire; other liquids put it out
+	if config.BurnDamage > 0 {
+		switch {
+		case a.touchesBurning(world):
+			state.Burning = config.BurnTicks
+		case a.Submerged > 0:
+			state.Burning = 0
+		}
+		if state.Burning > 0 {
+			state.Burning--
+			if state.burnTimer <= 0 {
+				damage += config.BurnDamage
+				state.burnTimer = config.BurnInterval
+			}
+			state.burnTimer--
+		} else {
+			state.burnTimer = 0
+		}
+	}
+
+	return damage
+}
+
+// headBlock returns the block at the top middle of the collider
+func (a *AABB) headBlock(world *PhysicsWorld) coretypes.BlockType {
+	tileSize := float64(settings.TileSize)
+	x := int(math.Floor((a.X + float64(a.Width)/2) / tileSize))
+	y := int(math.Floor((a.Y + 1) / tileSize))
+	return blockAt(world.Blocks, x, y, a.GridOffsetX, a.GridOffsetY)
+}
+
+// touchesBurning reports whether the collider overlaps or rests against a burning block
+func (a *AABB) touchesBurning(world *PhysicsWorld) bool {
+	tileSize := float64(settings.TileSize)
+	// One pixel wider on each side and two below, like the ground check
+	left, right := a.X-1, a.X+float64(a.Width)+1
+	top, bottom := a.Y-1, a.Y+float64(a.Height)+2
+	for y := int(math.Floor(top / tileSize)); float64(y)*tileSize < bottom; y++ {
+		for x := int(math.Floor(left / tileSize)); float64(x)*tileSize < right; x++ {
+			if blockAt(world.Blocks, x, y, a.GridOffsetX, a.GridOffsetY).Burns() {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// testHazards uses small numbers so every hazard shows up within a few ticks
+var testHazards = HazardConfig{
+	SafeFallSpeed:      10,
+	FallDamagePerSpeed: 2,
+	MaxBreath:          5,
+	BreathRecovery:     2,
+	DrownDamage:        4,
+	DrownInterval:      3,
+	BurnDamage:         3,
+	BurnInterval:       2,
+	BurnTicks:          4,
+}
+
+// hazardWorld is an open 8x8 block area with the given blocks filled in
+func hazardWorld(fill map[[2]int]coretypes.BlockType) *PhysicsWorld {
+	blocks := make([][]int, 8)
+	for y := range blocks {
+		blocks[y] = make([]int, 8)
+	}
+	for pos, block := range fill {
+		blocks[pos[1]][pos[0]] = int(block)
+	}
+	return NewPhysicsWorld(blocks)
+}
+
+// blockBox is a collider a little smaller than one block, sitting in block x, y
+func blockBox(x, y int) *AABB {
+	size := settings.TileSize - 4
+	return &AABB{X: float64(x*settings.TileSize + 2), Y: float64(y*settings.TileSize + 2), Width: size, Height: size}
+}
+
+func TestFallDamage(t *testing.T) {
+	tests := []struct {
+		impact float64
+		want   int
+	}{
+		{0, 0},
+		{testHazards.SafeFallSpeed, 0},
+		{testHazards.SafeFallSpeed + 0.2, 1}, // Rounded up
+		{testHazards.SafeFallSpeed + 5, 10},
+	}
+	for _, tt := range tests {
+		box := blockBox(1, 1)
+		box.Impact = tt.impact
+		if got := box.ApplyHazards(nil, &HazardState{}, testHazards); got != tt.want {
+			t.Errorf("landing at %v dealt %d, want %d", tt.impact, got, tt.want)
+		}
+	}
+}
+
+func TestDrowning(t *testing.T) {
+	world := hazardWorld(map[[2]int]coretypes.BlockType{{1, 1}: coretypes.Water})
+	box := blockBox(1, 1)
+	var state HazardState
+
+	var damage []int
+	for range testHazards.MaxBreath + 2*testHazards.DrownInterval {
+		damage = append(damage, box.ApplyHazards(world, &state, testHazards))
+	}
+	// Breath lasts MaxBreath ticks, then a hit lands every DrownInterval ticks
+	want := []int{0, 0, 0, 0, 4, 0, 0, 4, 0, 0, 4}
+	for i := range want {
+		if damage[i] != want[i] {
+			t.Fatalf("underwater damage by tick %v, want %v", damage, want)
+		}
+	}
+	if state.Breath(testHazards) != 0 {
+		t.Fatalf("breath left %d after drowning", state.Breath(testHazards))
+	}
+
+	// Surfacing brings breath back BreathRecovery at a time
+	box = blockBox(3, 1)
+	box.ApplyHazards(world, &state, testHazards)
+	if got := state.Breath(testHazards); got != testHazards.BreathRecovery {
+		t.Fatalf("breath %d after one tick in air, want %d", got, testHazards.BreathRecovery)
+	}
+}
+
+func TestBurning(t *testing.T) {
+	world := hazardWorld(map[[2]int]coretypes.BlockType{{2, 1}: coretypes.Lava, {5, 1}: coretypes.Water})
+	var state HazardState
+	nextToLava := blockBox(1, 1)
+	nextToLava.X += 2 // Right edge against the lava block
+
+	// Standing next to lava sets the box on fire and burns every BurnInterval ticks
+	if got := nextToLava.ApplyHazards(world, &state, testHazards); got != testHazards.BurnDamage || state.Burning == 0 {
+		t.Fatalf("touching lava dealt %d and left burning %d", got, state.Burning)
+	}
+	if got := nextToLava.ApplyHazards(world, &state, testHazards); got != 0 {
+		t.Fatalf("burned again %d ticks early", testHazards.BurnInterval-1)
+	}
+
+	// Away from lava the fire burns out after BurnTicks
+	away := 0
+	for range testHazards.BurnTicks + 2 {
+		away += blockBox(4, 4).ApplyHazards(world, &state, testHazards)
+	}
+	if state.Burning != 0 || away == 0 {
+		t.Fatalf("fire away from lava dealt %d and has %d ticks left", away, state.Burning)
+	}
+
+	// Water puts the fire out at once
+	nextToLava.ApplyHazards(world, &state, testHazards)
+	box := blockBox(5, 1)
+	box.Submerged = 1
+	box.ApplyHazards(world, &state, testHazards)
+	if state.Burning != 0 {
+		t.Fatalf("still burning %d ticks in water", state.Burning)
+	}
+}
@@ -1,5 +1,7 @@
 package physics
 
+import "github.com/KdntNinja/webcraft/coretypes"
+
 // Helper function for absolute value
 func abs(x float64) float64 {
 	if x < 0 {
@@ -8,12 +10,12 @@ func abs(x float64) float64 {
 	return x
 }
 
-// IsSolid checks if a block at grid coordinates is solid, using grid offset
+// IsSolid checks if a block at grid coordinates is solid (per the block registry), using grid offset
 func IsSolid(blocks [][]int, x, y int, offsetX, offsetY int) bool {
 	x -= offsetX
 	y -= offsetY
 	if y < 0 || x < 0 || y >= len(blocks) || x >= len(blocks[0]) {
 		return false
 	}
-	return blocks[y][x] > 0
+	return coretypes.BlockType(blocks[y][x]).IsSolid()
 }
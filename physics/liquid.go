@@ -0,0 +1,54 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// ApplyLiquids measures how much of the collider is inside liquid cells and applies
+// buoyancy and the liquid's drag. Liquid cells count as full; the collision grid
+// doesn't carry fill levels.
+func (a *AABB) ApplyLiquids(world *PhysicsWorld) {
+	a.Submerged = 0
+	if world == nil || a.Width <= 0 || a.Height <= 0 {
+		return
+	}
+
+	tileSize := float64(settings.TileSize)
+	left, right := a.X, a.X+float64(a.Width)
+	top, bottom := a.Y, a.Y+float64(a.Height)
+	area := float64(a.Width * a.Height)
+
+	drag := 0.0
+	for y := int(math.Floor(top / tileSize)); float64(y)*tileSize < bottom; y++ {
+		for x := int(math.Floor(left / tileSize)); float64(x)*tileSize < right; x++ {
+			block := blockAt(world.Blocks, x, y, a.GridOffsetX, a.GridOffsetY)
+			if !block.IsLiquid() {
+				continue
+			}
+			overlapX := math.Min(right, float64(x+1)*tileSize) - math.Max(left, float64(x)*tileSize)
+			overlapY := math.Min(bottom, float64(y+1)*tileSize) - math.Max(top, float64(y)*tileSize)
+			a.Submerged += overlapX * overlapY / area
+			drag = math.Max(drag, block.Drag())
+		}
+	}
+	if a.Submerged == 0 {
+		return
+	}
+
+	a.VY -= settings.LiquidBuoyancy * a.Submerged
+	a.VX *= 1 - drag
+	a.VY *= 1 - drag
+}
+
+// blockAt returns the block at grid coordinates, using grid offset; outside the grid is air
+func blockAt(blocks [][]int, x, y int, offsetX, offsetY int) coretypes.BlockType {
+	x -= offsetX
+	y -= offsetY
+	if y < 0 || x < 0 || y >= len(blocks) || x >= len(blocks[0]) {
+		return coretypes.Air
+	}
+	return coretypes.BlockType(blocks[y][x])
+}
@@ -39,6 +39,16 @@ func (a *AABB) ApplyVerticalMovement(gravity, maxFallSpeed float64) {
 	}
 }
 
+// Knockback replaces the velocity with an impulse, lifting the entity off the
+// ground when it pushes upwards
+func (a *AABB) Knockback(vx, vy float64) {
+	a.VX = vx
+	a.VY = vy
+	if vy < 0 {
+		a.OnGround = false
+	}
+}
+
 // Jump applies jump velocity if conditions are met
 func (a *AABB) Jump(jumpSpeed float64) bool {
 	if a.OnGround {
@@ -0,0 +1,79 @@
+package physics
+
+import (
+	"math"
+	"sync"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// SpatialGrid buckets entities into cells so nearby ones can be found without
+// checking every entity. Each simulation owns its own grid.
+type SpatialGrid struct {
+	cells    map[int]map[int][]coretypes.Entity
+	mutex    sync.RWMutex
+	cellSize int
+}
+
+// NewSpatialGrid creates an empty grid with cells 4x4 tiles across
+func NewSpatialGrid() *SpatialGrid {
+	return &SpatialGrid{
+		cells:    make(map[int]map[int][]coretypes.Entity),
+		cellSize: settings.TileSize * 4,
+	}
+}
+
+// Update replaces the grid's contents with the given entities
+func (g *SpatialGrid) Update(entities []coretypes.Entity) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.cells = make(map[int]map[int][]coretypes.Entity)
+	for _, e := range entities {
+		cellX, cellY := g.cellOf(e.GetPosition())
+
+		if g.cells[cellX] == nil {
+			g.cells[cellX] = make(map[int][]coretypes.Entity)
+		}
+		g.cells[cellX][cellY] = append(g.cells[cellX][cellY], e)
+	}
+}
+
+// GetEntitiesInRadius returns entities within a radius of a position
+func (g *SpatialGrid) GetEntitiesInRadius(x, y, radius float64) []coretypes.Entity {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	var results []coretypes.Entity
+	cellRadius := int(radius)/g.cellSize + 1
+	centerCellX, centerCellY := g.cellOf(x, y)
+
+	for dx := -cellRadius; dx <= cellRadius; dx++ {
+		for dy := -cellRadius; dy <= cellRadius; dy++ {
+			cellX := centerCellX + dx
+			cellY := centerCellY + dy
+
+			if cells, exists := g.cells[cellX]; exists {
+				if entities, exists := cells[cellY]; exists {
+					for _, entity := range entities {
+						ex, ey := entity.GetPosition()
+						distance := (ex-x)*(ex-x) + (ey-y)*(ey-y)
+						if distance <= radius*radius {
+							results = append(results, entity)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// cellOf returns the cell containing a position, rounding down so negative
+// coordinates get their own cells
+func (g *SpatialGrid) cellOf(x, y float64) (int, int) {
+	size := float64(g.cellSize)
+	return int(math.Floor(x / size)), int(math.Floor(y / size))
+}
@@ -0,0 +1,65 @@
+package rendering
+
+import (
+	"image"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/KdntNinja/webcraft/gameplay"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// breakStages holds the crack overlays from breaking.png, lightest first, scaled to
+// the tile size. The file is a row of square frames.
+var breakStages []*ebiten.Image
+
+// loadBreakStages reads the crack overlays the first time they are needed
+func loadBreakStages() []*ebiten.Image {
+	if breakStages != nil {
+		return breakStages
+	}
+	breakStages = []*ebiten.Image{}
+
+	file, err := imageFiles.Open("assets/breaking.png")
+	if err != nil {
+		log.Printf("Warning: Could not load break overlay: %v", err)
+		return breakStages
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		log.Printf("Warning: Could not decode break overlay: %v", err)
+		return breakStages
+	}
+
+	sheet := ebiten.NewImageFromImage(img)
+	frame := sheet.Bounds().Dy()
+	scale := float64(settings.TileSize) / float64(frame)
+	for x := 0; x+frame <= sheet.Bounds().Dx(); x += frame {
+		stage := ebiten.NewImage(settings.TileSize, settings.TileSize)
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(scale, scale)
+		stage.DrawImage(sheet.SubImage(image.Rect(x, 0, x+frame, frame)).(*ebiten.Image), op)
+		breakStages = append(breakStages, stage)
+	}
+	return breakStages
+}
+
+// DrawBreakingOverlay draws cracks over the block the player is mining, one stage
+// per step of progress
+func DrawBreakingOverlay(screen *ebiten.Image, p *gameplay.Player, cameraX, cameraY float64) {
+	blockX, blockY, progress, ok := p.MiningProgress()
+	if !ok {
+		return
+	}
+	stages := loadBreakStages()
+	if len(stages) == 0 {
+		return
+	}
+	stage := min(int(progress*float64(len(stages))), len(stages)-1)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(blockX*settings.TileSize)-cameraX, float64(blockY*settings.TileSize)-cameraY)
+	screen.DrawImage(stages[stage], op)
+}
@@ -10,8 +10,8 @@ import (
 	"github.com/KdntNinja/webcraft/settings"
 )
 
-// DrawCrosshair draws a targeting reticle and highlights the block under the cursor
-func DrawCrosshair(screen *ebiten.Image, world *world.World, cameraX, cameraY float64) {
+// DrawCrosshair draws a targeting reticle at the aim cursor and highlights the block under it
+func DrawCrosshair(screen *ebiten.Image, world *world.World, cameraX, cameraY float64, mouseX, mouseY int) {
 	if len(world.Entities) == 0 {
 		return
 	}
@@ -21,8 +21,6 @@ func DrawCrosshair(screen *ebiten.Image, world *world.World, cameraX, cameraY fl
 		return
 	}
 
-	mouseX, mouseY := ebiten.CursorPosition()
-
 	// Convert screen coordinates to world coordinates
 	worldX := float64(mouseX) + cameraX
 	worldY := float64(mouseY) + cameraY
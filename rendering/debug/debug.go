@@ -135,6 +135,7 @@ func InitDebugUI() error {
 		"Left Click: Break Block",
 		"Right Click: Place Block",
 		"1-9,0: Select Block",
+		"E: Inventory",
 		"ESC: Pause Game",
 		"F11: Toggle Fullscreen",
 	}
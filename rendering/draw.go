@@ -1,19 +1,19 @@
 package rendering
 
 import (
-	"image/color"
-
 	"github.com/hajimehoshi/ebiten/v2"
 
 	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/lighting"
 	"github.com/KdntNinja/webcraft/settings"
 )
 
-func Draw(chunks map[coretypes.ChunkCoord]*coretypes.Chunk, screen *ebiten.Image, cameraX, cameraY float64, gridOffsetX, gridOffsetY int) {
+// Draw renders the visible blocks over the sky already on screen, each darkened by
+// the light level at its position. daylight scales sunlight for the time of day.
+func Draw(chunks map[coretypes.ChunkCoord]*coretypes.Chunk, light *lighting.Engine, daylight float64, screen *ebiten.Image, cameraX, cameraY float64, gridOffsetX, gridOffsetY int) {
 	if tileImages == nil {
 		initTileImages()
 	}
-	screen.Fill(color.RGBA{135, 206, 250, 255}) // Sky
 
 	screenWidth, screenHeight := screen.Bounds().Dx(), screen.Bounds().Dy()
 	tileSize := settings.TileSize
@@ -50,9 +50,30 @@ func Draw(chunks map[coretypes.ChunkCoord]*coretypes.Chunk, screen *ebiten.Image
 					continue
 				}
 				drawOpts.GeoM.Reset()
-				drawOpts.GeoM.Translate(px, py)
+				tileY := py
+				if blockType.IsLiquid() {
+					// Partly filled liquid cells are drawn from the bottom up
+					if level := chunk.LiquidLevel(x, y); level < coretypes.MaxLiquidLevel {
+						fill := float64(level) / coretypes.MaxLiquidLevel
+						drawOpts.GeoM.Scale(1, fill)
+						tileY += float64(tileSize) * (1 - fill)
+					}
+				}
+				drawOpts.GeoM.Translate(px, tileY)
+				brightness := lightBrightness(light, daylight, globalTileX, globalTileY)
+				drawOpts.ColorScale.Reset()
+				drawOpts.ColorScale.Scale(brightness, brightness, brightness, 1)
 				screen.DrawImage(tile, drawOpts)
 			}
 		}
 	}
 }
+
+// lightBrightness converts a block's light level to a colour multiplier. Blocks whose
+// chunk hasn't been lit yet are drawn fully bright rather than flashing black.
+func lightBrightness(light *lighting.Engine, daylight float64, blockX, blockY int) float32 {
+	if light == nil || !light.Lit(blockX, blockY) {
+		return float32(daylight)
+	}
+	return float32(settings.LightAmbient + (1-settings.LightAmbient)*light.Brightness(blockX, blockY, daylight))
+}
@@ -1,14 +1,31 @@
 package rendering
 
 import (
+	"image"
+	"image/color"
+
 	"github.com/hajimehoshi/ebiten/v2"
 
 	"github.com/KdntNinja/webcraft/coretypes"
-	"github.com/KdntNinja/webcraft/gameplay"
 	"github.com/KdntNinja/webcraft/settings"
 )
 
-// DrawEntities draws all entities (e.g. players) near the camera.
+// playerEntity matches entities drawn with the player sprite around a player-sized collider
+type playerEntity interface {
+	GetColliderWidth() float64
+	GetColliderHeight() float64
+}
+
+// spriteKey identifies one generated sprite frame
+type spriteKey struct {
+	name  string
+	frame int
+}
+
+// spriteFrames caches generated sprite frames
+var spriteFrames = make(map[spriteKey]*ebiten.Image)
+
+// DrawEntities draws all entities (players, mobs and dropped items) near the camera.
 func DrawEntities(entities coretypes.Entities, screen *ebiten.Image, cameraX, cameraY float64, lastScreenW, lastScreenH int, playerImage *ebiten.Image) {
 	for _, entity := range entities {
 		// Get entity position (this is the collider position)
@@ -20,26 +37,114 @@ func DrawEntities(entities coretypes.Entities, screen *ebiten.Image, cameraX, ca
 
 		// Only draw entities that are visible on screen (with some margin)
 		margin := 100.0
-		if screenX > -margin && screenX < float64(lastScreenW)+margin &&
-			screenY > -margin && screenY < float64(lastScreenH)+margin {
-
-			// Adjust sprite position based on entity type
-			var spriteX, spriteY float64
-		   if _, isPlayer := entity.(*gameplay.Player); isPlayer {
-				// For players, the collider is centered horizontally and bottom-aligned
-				// We need to draw the sprite at its original position
-				spriteX = screenX - float64(settings.PlayerSpriteWidth-settings.PlayerColliderWidth)/2
-				spriteY = screenY - float64(settings.PlayerSpriteHeight-settings.PlayerColliderHeight)
-			} else {
-				// For other entities, use the entity position directly
-				spriteX = screenX
-				spriteY = screenY
+		if screenX < -margin || screenX > float64(lastScreenW)+margin ||
+			screenY < -margin || screenY > float64(lastScreenH)+margin {
+			continue
+		}
+
+		op := &ebiten.DrawImageOptions{}
+		if hurt, ok := entity.(interface{ Hurting() bool }); ok && hurt.Hurting() {
+			op.ColorScale.Scale(1, 0.45, 0.45, 1) // Flash red after a hit
+		} else if fire, ok := entity.(interface{ OnFire() bool }); ok && fire.OnFire() {
+			op.ColorScale.Scale(1, 0.7, 0.35, 1) // Glow orange while burning
+		}
+
+		// Dropped items are drawn as their icon, shrunk to fit their collider
+		if dropped, ok := entity.(interface{ DroppedStack() coretypes.ItemStack }); ok {
+			if icon := getItemImage(dropped.DroppedStack().Item); icon != nil {
+				scale := float64(settings.ItemEntitySize) / float64(icon.Bounds().Dx())
+				op.GeoM.Scale(scale, scale)
+				op.GeoM.Translate(screenX, screenY)
+				screen.DrawImage(icon, op)
+			}
+			continue
+		}
+
+		// Entities with their own sprite fill their collider
+		if drawable, ok := entity.(coretypes.Drawable); ok {
+			sprite := drawable.Sprite()
+			if sprite.FlipX {
+				op.GeoM.Scale(-1, 1)
+				op.GeoM.Translate(float64(sprite.Width), 0)
 			}
+			op.GeoM.Translate(screenX, screenY)
+			screen.DrawImage(spriteFrame(sprite), op)
+			continue
+		}
+
+		// Adjust sprite position based on entity type
+		spriteX, spriteY := screenX, screenY
+		if _, isPlayer := entity.(playerEntity); isPlayer {
+			// For players (local or remote), the collider is centered horizontally and bottom-aligned
+			// We need to draw the sprite at its original position
+			spriteX = screenX - float64(settings.PlayerSpriteWidth-settings.PlayerColliderWidth)/2
+			spriteY = screenY - float64(settings.PlayerSpriteHeight-settings.PlayerColliderHeight)
+		}
 
-			// Draw the entity image at the calculated screen position
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Translate(spriteX, spriteY)
-			screen.DrawImage(playerImage, op)
+		// Draw the entity image at the calculated screen position
+		op.GeoM.Translate(spriteX, spriteY)
+		screen.DrawImage(playerImage, op)
+
+		if swing, ok := entity.(interface{ SwingSide() int }); ok && swing.SwingSide() != 0 {
+			drawSwing(screen, entity, screenX, screenY, swing.SwingSide())
 		}
 	}
 }
+
+// drawSwing draws a melee swing as a short blade beside the entity's collider
+func drawSwing(screen *ebiten.Image, entity coretypes.Entity, screenX, screenY float64, side int) {
+	w, h := float64(settings.PlayerColliderWidth), float64(settings.PlayerColliderHeight)
+	if living, ok := entity.(coretypes.Living); ok {
+		w, h = living.ColliderSize()
+	}
+	length := settings.PlayerAttackReach - int(w)/2
+	x := int(screenX + w)
+	if side < 0 {
+		x = int(screenX) - length
+	}
+	fillRect(screen, x, int(screenY+h/3), length, 4, color.RGBA{220, 220, 230, 220})
+}
+
+// spriteFrame returns a sprite's current frame, generating it the first time it is drawn
+func spriteFrame(sprite coretypes.Sprite) *ebiten.Image {
+	key := spriteKey{sprite.Name, sprite.Frame}
+	if img, ok := spriteFrames[key]; ok {
+		return img
+	}
+	img := generateSpriteFrame(sprite)
+	spriteFrames[key] = img
+	return img
+}
+
+// generateSpriteFrame draws a simple creature facing right: a body in the sprite's
+// colour with a darker outline, an eye, and two feet that swap over between frames
+func generateSpriteFrame(sprite coretypes.Sprite) *ebiten.Image {
+	w, h := max(sprite.Width, 4), max(sprite.Height, 4)
+	img := ebiten.NewImage(w, h)
+
+	body := color.RGBA{sprite.Color[0], sprite.Color[1], sprite.Color[2], 255}
+	dark := color.RGBA{body.R / 2, body.G / 2, body.B / 2, 255}
+	fill := func(x0, y0, x1, y1 int, c color.Color) {
+		img.SubImage(image.Rect(x0, y0, x1, y1)).(*ebiten.Image).Fill(c)
+	}
+
+	// Body above the feet
+	feet := max(h/5, 2)
+	fill(0, 0, w, h-feet, dark)
+	fill(1, 1, w-1, h-feet-1, body)
+
+	// Eye near the front
+	eye := max(h/6, 2)
+	fill(w-eye*3, h/4, w-eye*2, h/4+eye, color.White)
+	fill(w-eye*3+eye/2, h/4+eye/2, w-eye*2, h/4+eye, color.Black)
+
+	// Feet alternate between a wide and a narrow stance
+	foot := max(w/6, 2)
+	step := 0
+	if sprite.Frame%2 == 1 {
+		step = foot / 2
+	}
+	fill(foot+step, h-feet, foot*2+step, h, dark)
+	fill(w-foot*2-step, h-feet, w-foot-step, h, dark)
+	return img
+}
@@ -27,26 +27,23 @@ type AtlasCoord struct {
 type BlockTextureConfig struct {
 	Filename string
 	Coord    AtlasCoord // Coordinates within that specific texture file
+	Tint     []float64  // Optional RGB multiplier
 }
 
-// BlockTextureConfigs maps block types to their texture file and coordinates
-var BlockTextureConfigs = map[coretypes.BlockType]BlockTextureConfig{
-	coretypes.Dirt:      {Filename: "assets/dirt.png", Coord: AtlasCoord{X: 0, Y: 0}},
-	coretypes.Grass:     {Filename: "assets/grass.png", Coord: AtlasCoord{X: 2, Y: 0.3}},
-	coretypes.Clay:      {Filename: "assets/clay.png", Coord: AtlasCoord{X: 0, Y: 0}},
-	coretypes.Leaves:    {Filename: "assets/leaves.png", Coord: AtlasCoord{X: 0, Y: 0}},
-	coretypes.Wood:      {Filename: "assets/wood.png", Coord: AtlasCoord{X: 0, Y: 0}},
-	coretypes.GoldOre:   {Filename: "assets/goldore.png", Coord: AtlasCoord{X: 0, Y: 0}},
-	coretypes.CopperOre: {Filename: "assets/copperore.png", Coord: AtlasCoord{X: 0, Y: 0}},
-	coretypes.IronOre:   {Filename: "assets/ironore.png", Coord: AtlasCoord{X: 0, Y: 0}},
-	coretypes.Stone:     {Filename: "assets/stone.png", Coord: AtlasCoord{X: 0, Y: 0}},
-	coretypes.Water:     {Filename: "assets/water.png", Coord: AtlasCoord{X: 1, Y: 1}},
-	coretypes.Ash:       {Filename: "assets/clay.png", Coord: AtlasCoord{X: 0, Y: 1}},
-	coretypes.Hellstone: {Filename: "assets/goldore.png", Coord: AtlasCoord{X: 0, Y: 0}},
-	coretypes.Granite:   {Filename: "assets/stone.png", Coord: AtlasCoord{X: 0, Y: 0}}, // Use stone.png, different tint
-	coretypes.Andesite:  {Filename: "assets/stone.png", Coord: AtlasCoord{X: 0, Y: 0}}, // Use stone.png, different tint
-	coretypes.Diorite:   {Filename: "assets/stone.png", Coord: AtlasCoord{X: 0, Y: 0}}, // Use stone.png, different tint
-	coretypes.Slate:     {Filename: "assets/stone.png", Coord: AtlasCoord{X: 0, Y: 0}}, // Use stone.png, different tint
+// BlockTextureConfigs returns the texture of every registered block that has one
+func BlockTextureConfigs() map[coretypes.BlockType]BlockTextureConfig {
+	configs := make(map[coretypes.BlockType]BlockTextureConfig)
+	for _, def := range coretypes.Blocks().Defs() {
+		if def.Texture == "" {
+			continue
+		}
+		configs[def.ID] = BlockTextureConfig{
+			Filename: "assets/" + def.Texture,
+			Coord:    AtlasCoord{X: def.Atlas[0], Y: def.Atlas[1]},
+			Tint:     def.Tint,
+		}
+	}
+	return configs
 }
 
 // LoadTextures loads all block textures from their individual atlas files
@@ -59,7 +56,7 @@ func LoadTextures(tileSize int) error {
 
 	var wg sync.WaitGroup
 	var mu sync.Mutex
-	for blockType, config := range BlockTextureConfigs {
+	for blockType, config := range BlockTextureConfigs() {
 		wg.Add(1)
 		go func(blockType coretypes.BlockType, config BlockTextureConfig) {
 			defer wg.Done()
@@ -68,18 +65,8 @@ func LoadTextures(tileSize int) error {
 				log.Printf("Warning: Could not load texture %s for block %v: %v", config.Filename, blockType, err)
 				return
 			}
-			// Tint stone variants and gold ore
-			switch blockType {
-			case coretypes.Granite:
-				texture = tintImage(texture, 1.15, 0.95, 0.85) // Warm pinkish
-			case coretypes.Andesite:
-				texture = tintImage(texture, 0.85, 0.9, 1.1) // Cool bluish
-			case coretypes.Diorite:
-				texture = tintImage(texture, 1.2, 1.2, 1.2) // Bright white
-			case coretypes.Slate:
-				texture = tintImage(texture, 0.6, 0.6, 0.7) // Dark gray
-			case coretypes.GoldOre:
-				texture = tintImage(texture, 2.0, 2.0, 0.3) // Strong yellow tint
+			if len(config.Tint) == 3 {
+				texture = tintImage(texture, config.Tint[0], config.Tint[1], config.Tint[2])
 			}
 			mu.Lock()
 			BlockTextures[blockType] = texture
@@ -0,0 +1,85 @@
+package rendering
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/KdntNinja/webcraft/gameplay"
+)
+
+// healthPerHeart is how much health one heart in the HUD stands for
+const healthPerHeart = 10
+
+// breathBubbles is how many bubbles full breath is drawn as
+const breathBubbles = 10
+
+// uiPixel is a white pixel scaled and tinted to draw flat rectangles
+var uiPixel *ebiten.Image
+
+// DrawHealthUI draws the player's health as a row of hearts under the hotbar.
+// The hearts flash while the player is invulnerable after a hit.
+func DrawHealthUI(screen *ebiten.Image, p *gameplay.Player) {
+	heartSize := 14  // Heart size (pixels)
+	spacing := 4     // Gap between hearts
+	x0, y0 := 18, 70 // Top left corner, just below the hotbar
+
+	hearts := (p.MaxHealth + healthPerHeart - 1) / healthPerHeart
+	flash := p.Invulnerable > 0 && p.Invulnerable/6%2 == 0
+
+	// Faint background behind the hearts and the number
+	width := hearts*(heartSize+spacing) + 70
+	fillRect(screen, x0-8, y0-5, width, heartSize+10, color.RGBA{20, 20, 20, 180})
+
+	for i := 0; i < hearts; i++ {
+		x := x0 + i*(heartSize+spacing)
+		fillRect(screen, x+1, y0+2, heartSize, heartSize, color.RGBA{0, 0, 0, 60}) // Shadow
+		fillRect(screen, x, y0, heartSize, heartSize, color.RGBA{70, 20, 20, 230})
+
+		// Fill the part of the heart the player's health covers
+		filled := min(max(p.Health-i*healthPerHeart, 0), healthPerHeart) * (heartSize - 4) / healthPerHeart
+		if filled > 0 {
+			fill := color.RGBA{220, 40, 50, 255}
+			if flash {
+				fill = color.RGBA{255, 200, 200, 255}
+			}
+			fillRect(screen, x+2, y0+2, filled, heartSize-4, fill)
+		}
+	}
+
+	text := fmt.Sprintf("%d/%d", p.Health, p.MaxHealth)
+	DrawUITextOutline(screen, text, x0+hearts*(heartSize+spacing)+4, y0, color.Black, color.White)
+
+	drawBreath(screen, p, x0, y0+heartSize+10)
+}
+
+// drawBreath draws the player's breath as a row of bubbles while it isn't full,
+// one bubble popping for each tenth used up
+func drawBreath(screen *ebiten.Image, p *gameplay.Player, x0, y0 int) {
+	breath, maxBreath := p.Breath()
+	if maxBreath <= 0 || breath >= maxBreath {
+		return
+	}
+	size, spacing := 10, 4
+	left := (breath*breathBubbles + maxBreath - 1) / maxBreath
+	fillRect(screen, x0-8, y0-4, breathBubbles*(size+spacing)+12, size+8, color.RGBA{20, 20, 20, 180})
+	for i := 0; i < left; i++ {
+		x := x0 + i*(size+spacing)
+		fillRect(screen, x, y0, size, size, color.RGBA{60, 140, 230, 230})
+		fillRect(screen, x+2, y0+2, 3, 3, color.RGBA{220, 240, 255, 255}) // Highlight
+	}
+}
+
+// fillRect draws a flat rectangle
+func fillRect(screen *ebiten.Image, x, y, w, h int, clr color.RGBA) {
+	if uiPixel == nil {
+		uiPixel = ebiten.NewImage(1, 1)
+		uiPixel.Fill(color.White)
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(w), float64(h))
+	op.GeoM.Translate(float64(x), float64(y))
+	op.ColorScale.ScaleWithColor(clr)
+	screen.DrawImage(uiPixel, op)
+}
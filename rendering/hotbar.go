@@ -9,6 +9,7 @@ import (
 
 	"github.com/KdntNinja/webcraft/coretypes"
 	"github.com/KdntNinja/webcraft/gameplay"
+	"github.com/KdntNinja/webcraft/settings"
 )
 
 // DrawHotbarUI draws a Minecraft-style hotbar in the top left of the screen
@@ -27,15 +28,13 @@ func DrawHotbarUI(screen *ebiten.Image, p *gameplay.Player) {
 	hotbarBgOpts.GeoM.Translate(float64(x0-8), float64(y0-6))
 	screen.DrawImage(hotbarBg, hotbarBgOpts)
 
-	for i := 0; i < len(p.Hotbar); i++ {
+	for i, stack := range p.Hotbar() {
 		x := x0 + i*(tileSize+padding)
-		blockType := p.Hotbar[i]
-		count := p.Inventory[blockType]
 
 		// Draw slot with rounded corners and shadow
 		slotImg := ebiten.NewImage(tileSize, tileSize)
 		slotImg.Fill(color.RGBA{60, 60, 60, 220})
-		if blockType == p.SelectedBlock {
+		if i == p.SelectedSlot {
 			// Thicker, vibrant border for selected
 			slotImg.Fill(color.RGBA{255, 215, 0, 220})
 		}
@@ -51,42 +50,59 @@ func DrawHotbarUI(screen *ebiten.Image, p *gameplay.Player) {
 		slotOpts.GeoM.Translate(float64(x), float64(y0))
 		screen.DrawImage(slotImg, slotOpts)
 
-		// Draw block icon centered
-		if blockType != coretypes.Air {
-			tile := getBlockTileImage(blockType)
-			if tile != nil {
-				iconOpts := &ebiten.DrawImageOptions{}
-				iconOpts.GeoM.Translate(float64(x)+4, float64(y0)+4)
-				screen.DrawImage(tile, iconOpts)
-			}
+		if stack.IsEmpty() {
+			continue
 		}
+		DrawItemStack(screen, stack, x, y0, tileSize)
+	}
+}
 
-		// Draw border (rounded effect)
-		borderImg := ebiten.NewImage(tileSize, tileSize)
-		borderImg.Fill(color.RGBA{120, 120, 120, 255})
-		borderOpts := &ebiten.DrawImageOptions{}
-		borderOpts.GeoM.Translate(float64(x), float64(y0))
-		borderOpts.CompositeMode = ebiten.CompositeModeSourceOver
-		// Simulate rounded border by overlaying a smaller dark rect
-		borderImg.DrawImage(ebiten.NewImage(tileSize-8, tileSize-8), &ebiten.DrawImageOptions{})
-
-		// Draw block count with black outline for readability (bottom left)
-		if count > 0 {
-			countStr := fmt.Sprintf("%d", count)
-			textX := x + 8
-			textY := y0 + tileSize - 12
-			DrawUITextOutline(screen, countStr, textX, textY, color.Black, color.White)
-		}
+// DrawItemStack draws a stack's icon in a slot, with its count and, for worn tools,
+// how much use is left
+func DrawItemStack(screen *ebiten.Image, stack coretypes.ItemStack, x, y, slotSize int) {
+	// Draw item icon centered
+	if icon := getItemImage(stack.Item); icon != nil {
+		iconOpts := &ebiten.DrawImageOptions{}
+		iconOpts.GeoM.Translate(float64(x)+4, float64(y)+4)
+		screen.DrawImage(icon, iconOpts)
+	}
+
+	// Durability bar along the bottom once a tool has been used
+	if durability := stack.Item.Durability(); durability > 0 && stack.Damage > 0 {
+		width := slotSize - 8
+		left := width * (durability - stack.Damage) / durability
+		fillRect(screen, x+4, y+slotSize-6, width, 3, color.RGBA{0, 0, 0, 200})
+		fillRect(screen, x+4, y+slotSize-6, left, 3, color.RGBA{80, 220, 80, 255})
+	}
 
+	// Draw item count with black outline for readability (bottom left)
+	if stack.Item.MaxStack() > 1 {
+		countStr := fmt.Sprintf("%d", stack.Count)
+		DrawUITextOutline(screen, countStr, x+8, y+slotSize-12, color.Black, color.White)
 	}
 }
 
+// getItemImage returns an item's icon: the block's tile for block items, or a
+// generated icon for the rest
+func getItemImage(item coretypes.ItemID) *ebiten.Image {
+	if block := item.Block(); block != coretypes.Air {
+		return getBlockTileImage(block)
+	}
+	if img, ok := itemIcons[item]; ok {
+		return img
+	}
+	def := item.Def()
+	if def == nil {
+		return nil
+	}
+	img := generateItemIcon(def, settings.TileSize)
+	itemIcons[item] = img
+	return img
+}
+
 // getBlockTileImage returns the block's tile image for the hotbar
 func getBlockTileImage(blockType coretypes.BlockType) *ebiten.Image {
-	if int(blockType) < len(tileImages) {
-		return tileImages[blockType]
-	}
-	return nil
+	return tileImages[blockType]
 }
 
 // DrawUIText draws simple text (for block count)
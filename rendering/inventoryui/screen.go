@@ -0,0 +1,291 @@
+package inventoryui
+
+import (
+	"bytes"
+	"image/color"
+	"slices"
+
+	"github.com/ebitenui/ebitenui"
+	eimage "github.com/ebitenui/ebitenui/image"
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/crafting"
+	"github.com/KdntNinja/webcraft/gameplay"
+	"github.com/KdntNinja/webcraft/rendering"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+const slotSize = 36 // Slot size in pixels, matching the hotbar
+
+// Screen is the inventory screen: the main inventory grid above the hotbar row,
+// beside a list of the recipes the player can craft. It never changes the
+// inventory itself; clicks become InventoryOps that go into the next ticks'
+// input frames, so the simulation, replays and the server all see them.
+type Screen struct {
+	UI *ebitenui.UI
+
+	open    bool
+	slots   []*ebiten.Image         // Drawn each frame from the player's inventory
+	hovered int                     // Slot under the mouse, or -1
+	pressed int                     // Slot the left button went down on, or -1
+	ops     []coretypes.InventoryOp // Actions waiting for a tick, oldest first
+	cursor  coretypes.ItemStack     // Stack the player is carrying, drawn at the mouse
+
+	font    text.Face
+	recipes *widget.Container // One button per craftable recipe
+	listed  []int             // IDs of the recipes in the list, to rebuild it only when they change
+}
+
+// New builds the inventory screen, closed
+func New() (*Screen, error) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
+	if err != nil {
+		return nil, err
+	}
+	font := &text.GoTextFace{Source: src, Size: 14}
+
+	s := &Screen{hovered: -1, pressed: -1, font: font}
+	root := widget.NewContainer(widget.ContainerOpts.Layout(widget.NewAnchorLayout()))
+	frame := widget.NewContainer(
+		widget.ContainerOpts.BackgroundImage(eimage.NewNineSliceColor(color.NRGBA{20, 20, 20, 220})),
+		widget.ContainerOpts.WidgetOpts(
+			widget.WidgetOpts.LayoutData(widget.AnchorLayoutData{
+				HorizontalPosition: widget.AnchorLayoutPositionCenter,
+				VerticalPosition:   widget.AnchorLayoutPositionCenter,
+			}),
+		),
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Spacing(16),
+			widget.RowLayoutOpts.Padding(widget.NewInsetsSimple(12)),
+		)),
+	)
+	panel := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Direction(widget.DirectionVertical),
+			widget.RowLayoutOpts.Spacing(8),
+		)),
+	)
+
+	// Title and sort button
+	header := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewRowLayout(widget.RowLayoutOpts.Spacing(12))),
+	)
+	header.AddChild(widget.NewText(
+		widget.TextOpts.Text("Inventory", font, color.White),
+		widget.TextOpts.Position(widget.TextPositionStart, widget.TextPositionCenter),
+		widget.TextOpts.WidgetOpts(widget.WidgetOpts.MinSize(9*slotSize-60, 24)),
+	))
+	header.AddChild(widget.NewButton(
+		widget.ButtonOpts.Image(&widget.ButtonImage{
+			Idle:    eimage.NewNineSliceColor(color.NRGBA{70, 70, 70, 255}),
+			Hover:   eimage.NewNineSliceColor(color.NRGBA{100, 100, 100, 255}),
+			Pressed: eimage.NewNineSliceColor(color.NRGBA{50, 50, 50, 255}),
+		}),
+		widget.ButtonOpts.Text("Sort", font, &widget.ButtonTextColor{Idle: color.White}),
+		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.MinSize(48, 24)),
+		widget.ButtonOpts.ClickedHandler(func(*widget.ButtonClickedEventArgs) {
+			s.queue(coretypes.InventorySort, 0)
+		}),
+	))
+	panel.AddChild(header)
+
+	// Main inventory above, hotbar below, like the hotbar drawn in the corner
+	s.slots = make([]*ebiten.Image, settings.InventorySize)
+	main := s.slotGrid(settings.HotbarSize, settings.InventorySize)
+	hotbar := s.slotGrid(0, settings.HotbarSize)
+	panel.AddChild(main)
+	panel.AddChild(hotbar)
+	frame.AddChild(panel)
+
+	// Recipe browser
+	browser := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Direction(widget.DirectionVertical),
+			widget.RowLayoutOpts.Spacing(8),
+		)),
+	)
+	browser.AddChild(widget.NewText(
+		widget.TextOpts.Text("Crafting", font, color.White),
+		widget.TextOpts.WidgetOpts(widget.WidgetOpts.MinSize(0, 24)),
+	))
+	s.recipes = widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Direction(widget.DirectionVertical),
+			widget.RowLayoutOpts.Spacing(4),
+		)),
+	)
+	browser.AddChild(s.recipes)
+	frame.AddChild(browser)
+
+	root.AddChild(frame)
+	s.UI = &ebitenui.UI{Container: root}
+	return s, nil
+}
+
+// slotGrid builds a grid of the slots from first up to (not including) last
+func (s *Screen) slotGrid(first, last int) *widget.Container {
+	grid := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(settings.HotbarSize),
+			widget.GridLayoutOpts.Spacing(4, 4),
+		)),
+	)
+	for i := first; i < last; i++ {
+		slot := i
+		s.slots[slot] = ebiten.NewImage(slotSize, slotSize)
+		grid.AddChild(widget.NewGraphic(
+			widget.GraphicOpts.Image(s.slots[slot]),
+			widget.GraphicOpts.WidgetOpts(
+				widget.WidgetOpts.MinSize(slotSize, slotSize),
+				widget.WidgetOpts.CursorEnterHandler(func(*widget.WidgetCursorEnterEventArgs) {
+					s.hovered = slot
+				}),
+				widget.WidgetOpts.CursorExitHandler(func(*widget.WidgetCursorExitEventArgs) {
+					if s.hovered == slot {
+						s.hovered = -1
+					}
+				}),
+				widget.WidgetOpts.MouseButtonPressedHandler(func(args *widget.WidgetMouseButtonPressedEventArgs) {
+					s.press(slot, args.Button)
+				}),
+			),
+		))
+	}
+	return grid
+}
+
+// listRecipes fills the recipe browser with a button for each recipe, unless it
+// already lists exactly those recipes
+func (s *Screen) listRecipes(recipes []*crafting.Recipe) {
+	ids := make([]int, len(recipes))
+	for i, recipe := range recipes {
+		ids[i] = recipe.ID
+	}
+	if slices.Equal(ids, s.listed) && s.listed != nil {
+		return
+	}
+	s.listed = ids
+
+	s.recipes.RemoveChildren()
+	if len(recipes) == 0 {
+		s.recipes.AddChild(widget.NewText(
+			widget.TextOpts.Text("Nothing to craft", s.font, color.NRGBA{160, 160, 160, 255}),
+		))
+		return
+	}
+	for _, recipe := range recipes {
+		id := recipe.ID
+		s.recipes.AddChild(widget.NewButton(
+			widget.ButtonOpts.Image(&widget.ButtonImage{
+				Idle:    eimage.NewNineSliceColor(color.NRGBA{70, 70, 70, 255}),
+				Hover:   eimage.NewNineSliceColor(color.NRGBA{100, 100, 100, 255}),
+				Pressed: eimage.NewNineSliceColor(color.NRGBA{50, 50, 50, 255}),
+			}),
+			widget.ButtonOpts.Text(recipe.String(), s.font, &widget.ButtonTextColor{Idle: color.White}),
+			widget.ButtonOpts.TextPosition(widget.TextPositionStart, widget.TextPositionCenter),
+			widget.ButtonOpts.TextPadding(widget.Insets{Left: 6, Right: 6}),
+			widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.MinSize(220, 24)),
+			widget.ButtonOpts.ClickedHandler(func(*widget.ButtonClickedEventArgs) {
+				s.queue(coretypes.InventoryCraft, id)
+			}),
+		))
+	}
+}
+
+// press turns a mouse button going down on a slot into an action: shift-click
+// quick-moves, left click picks up or puts down, right click splits
+func (s *Screen) press(slot int, button ebiten.MouseButton) {
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	switch {
+	case button == ebiten.MouseButtonLeft && shift:
+		s.queue(coretypes.InventoryQuickMove, slot)
+	case button == ebiten.MouseButtonLeft:
+		s.queue(coretypes.InventoryClick, slot)
+		s.pressed = slot
+	case button == ebiten.MouseButtonRight:
+		s.queue(coretypes.InventorySplit, slot)
+	}
+}
+
+// queue adds an action to send with a coming tick
+func (s *Screen) queue(action coretypes.InventoryAction, slot int) {
+	s.ops = append(s.ops, coretypes.InventoryOp{Action: action, Slot: slot})
+}
+
+// IsOpen reports whether the screen is showing
+func (s *Screen) IsOpen() bool {
+	return s.open
+}
+
+// Toggle opens or closes the screen. Closing puts whatever the player is carrying
+// back into the inventory.
+func (s *Screen) Toggle() {
+	s.open = !s.open
+	s.hovered, s.pressed = -1, -1
+	if !s.open {
+		s.queue(coretypes.InventoryReturn, 0)
+	}
+}
+
+// Update handles the mouse and redraws the slots from the player's inventory.
+// Releasing the left button over another slot than the one it was pressed on
+// drops the carried stack there, so stacks can be dragged as well as clicked.
+func (s *Screen) Update(p *gameplay.Player) {
+	if !s.open || p == nil {
+		return
+	}
+	s.listRecipes(p.CraftableRecipes())
+	s.UI.Update()
+
+	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+		carrying := !p.Cursor.IsEmpty() || len(s.ops) > 0
+		if s.pressed >= 0 && s.hovered >= 0 && s.hovered != s.pressed && carrying {
+			s.queue(coretypes.InventoryClick, s.hovered)
+		}
+		s.pressed = -1
+	}
+
+	for i, img := range s.slots {
+		img.Fill(color.RGBA{60, 60, 60, 220})
+		if i == s.hovered {
+			img.Fill(color.RGBA{110, 110, 110, 220})
+		} else if i == p.SelectedSlot {
+			img.Fill(color.RGBA{255, 215, 0, 220})
+		}
+		if stack := p.Inventory.Slot(i); !stack.IsEmpty() {
+			rendering.DrawItemStack(img, stack, 0, 0, slotSize)
+		}
+	}
+	s.cursor = p.Cursor
+}
+
+// Input adjusts a tick's input frame: while the screen is open the mouse belongs to
+// it rather than to breaking, placing and attacking, and queued actions go out one
+// per tick
+func (s *Screen) Input(frame coretypes.InputFrame) coretypes.InputFrame {
+	if s.open {
+		frame.Break, frame.Place, frame.Attack = false, false, false
+	}
+	if len(s.ops) > 0 {
+		frame.Inventory = s.ops[0]
+		s.ops = s.ops[1:]
+	}
+	return frame
+}
+
+// Draw draws the screen and the stack being carried at the mouse
+func (s *Screen) Draw(screen *ebiten.Image) {
+	if !s.open {
+		return
+	}
+	s.UI.Draw(screen)
+	if !s.cursor.IsEmpty() {
+		x, y := ebiten.CursorPosition()
+		rendering.DrawItemStack(screen, s.cursor, x-slotSize/2, y-slotSize/2, slotSize)
+	}
+}
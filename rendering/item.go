@@ -0,0 +1,63 @@
+package rendering
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+// itemIcons caches generated icons for items that aren't blocks
+var itemIcons = make(map[coretypes.ItemID]*ebiten.Image)
+
+// generateItemIcon draws an icon for an item that has no block tile, in the item's
+// colour with a darker outline. Items without a known icon shape are drawn as a gem.
+func generateItemIcon(def *coretypes.ItemDef, size int) *ebiten.Image {
+	img := ebiten.NewImage(size, size)
+	head := color.RGBA{def.Color[0], def.Color[1], def.Color[2], 255}
+	dark := color.RGBA{head.R / 2, head.G / 2, head.B / 2, 255}
+	fill := func(x0, y0, x1, y1 int, c color.Color) {
+		img.SubImage(image.Rect(x0, y0, x1, y1)).(*ebiten.Image).Fill(c)
+	}
+	u := max(size/8, 1) // One icon pixel
+
+	switch def.Icon {
+	case "pickaxe":
+		// Wooden handle running diagonally from the bottom left
+		handle := color.RGBA{140, 96, 52, 255}
+		for i := 1; i < 7; i++ {
+			fill(i*u, (7-i)*u, (i+1)*u, (8-i)*u, handle)
+		}
+		// Curved head across the top right
+		fill(2*u, 0, 7*u, u, dark)
+		fill(3*u, 0, 6*u, u, head)
+		fill(6*u, u, 8*u, 2*u, dark)
+		fill(6*u, 2*u, 7*u, 4*u, dark)
+		fill(6*u, u, 7*u, 3*u, head)
+		fill(u, u, 3*u, 2*u, dark)
+	case "stick":
+		for i := 1; i < 7; i++ {
+			fill(i*u, (7-i)*u, (i+1)*u, (8-i)*u, dark)
+			fill(i*u, (7-i)*u, (i+1)*u-u/2, (8-i)*u-u/2, head)
+		}
+	case "bar":
+		// An ingot seen from the front, lighter on top
+		fill(u, 3*u, 7*u, 6*u, dark)
+		fill(2*u, 3*u, 6*u, 5*u, head)
+		light := func(c uint8) uint8 { return uint8(min(int(c)+40, 255)) }
+		fill(2*u, 3*u, 6*u, 4*u, color.RGBA{light(head.R), light(head.G), light(head.B), 255})
+	case "ore":
+		// A rough lump with a few darker flecks
+		fill(2*u, 2*u, 6*u, 7*u, dark)
+		fill(u, 3*u, 7*u, 6*u, dark)
+		fill(2*u, 3*u, 6*u, 6*u, head)
+		fill(3*u, 4*u, 4*u, 5*u, dark)
+		fill(5*u, 3*u, 6*u, 4*u, dark)
+	default:
+		fill(2*u, 2*u, 6*u, 6*u, dark)
+		fill(3*u, 3*u, 5*u, 5*u, head)
+	}
+	return img
+}
@@ -26,6 +26,7 @@ func getDrawOptions() *ebiten.DrawImageOptions {
 	}
 	options := drawOptionsPool[poolIndex]
 	options.GeoM.Reset() // Reset transform
+	options.ColorScale.Reset()
 	poolIndex++
 	return options
 }
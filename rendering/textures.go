@@ -24,11 +24,12 @@ func initTextureImages() {
 		return
 	}
 
-	tileImages = make(map[coretypes.BlockType]*ebiten.Image, coretypes.NumBlockTypes)
+	tileImages = make(map[coretypes.BlockType]*ebiten.Image, coretypes.NumBlockTypes())
 	batchRenderer = &ebiten.DrawImageOptions{}
 
 	// Copy textures from graphics package to render package
-	for blockType := coretypes.Air; blockType <= coretypes.Hellstone; blockType++ {
+	for _, def := range coretypes.Blocks().Defs() {
+		blockType := def.ID
 		if blockType == coretypes.Air {
 			continue // Skip air blocks
 		}
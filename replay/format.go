@@ -0,0 +1,397 @@
+package replay
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+// Replay file layout (little-endian):
+//
+//	magic    [4]byte "WCRP"
+//	version  uint16  formatVersion
+//	segments until the end of the file, each a uint32 length and a zlib-compressed body
+//
+// The first segment holds the world and the rest hold the input recorded since the
+// segment before, so a recording can be appended to as it goes:
+//
+//	seed     int64
+//	settings uvarint length + JSON object of settings.Snapshot()
+//
+//	frames   uvarint run count, then per run: uvarint repeat + encoded frame
+//	checks   uvarint count, then per checksum: uvarint tick + uint64 player + uint64 chunks
+//
+// Consecutive identical frames are stored once with a repeat count, so idle or
+// steady movement costs a few bytes per run rather than per tick. A last segment
+// cut short, e.g. by a crash mid-write, is dropped when reading. Before version 4
+// the file was a single zlib body holding all four fields.
+const (
+	replayMagic   = "WCRP"
+	formatVersion = 4 // Version 2 added inventory screen actions, version 3 full block IDs in chunk checksums, version 4 segments
+
+	segmentHeaderSize = 4
+)
+
+// Frame flag bits
+const (
+	flagJump = 1 << iota
+	flagSneak
+	flagSprint
+	flagBreak
+	flagPlace
+	flagHotbar
+	flagAttack
+	flagInventory
+)
+
+// Checksum is the expected simulation state after a tick
+type Checksum struct {
+	Tick   uint64
+	Player uint64 // sim.Simulation.PlayerChecksum
+	Chunks uint64 // sim.Simulation.ModifiedChunksChecksum
+}
+
+// Replay is a recorded session: enough to rebuild the world and feed the same input
+type Replay struct {
+	Version   uint16 // Format version the replay was read from; 0 for one recorded in this process
+	Seed      int64
+	Settings  map[string]string
+	Frames    []coretypes.InputFrame
+	Checksums []Checksum
+}
+
+// Quantize rounds a frame to the precision the file stores. Frames must be
+// quantized before the simulation sees them so playback is bit-identical.
+func Quantize(frame coretypes.InputFrame) coretypes.InputFrame {
+	frame.MoveX = float64(int8(math.Round(math.Max(-1, math.Min(1, frame.MoveX))*127))) / 127
+	frame.AimX = math.Round(frame.AimX)
+	frame.AimY = math.Round(frame.AimY)
+	if frame.HotbarSlot < 0 || frame.HotbarSlot > 255 {
+		frame.HotbarSlot = coretypes.NoHotbarChange
+	}
+	if frame.Inventory.Action == coretypes.NoInventoryAction || frame.Inventory.Slot < 0 || frame.Inventory.Slot > 255 {
+		frame.Inventory = coretypes.InventoryOp{}
+	}
+	return frame
+}
+
+// Encode serializes the replay
+func (r *Replay) Encode() ([]byte, error) {
+	data, err := encodeHeader(r.Seed, r.Settings)
+	if err != nil {
+		return nil, err
+	}
+	input, err := encodeSegment(r.Frames, r.Checksums)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, input...), nil
+}
+
+// encodeHeader returns the start of a replay file: the magic, the version and the
+// segment describing the world
+func encodeHeader(seed int64, settings map[string]string) ([]byte, error) {
+	var body bytes.Buffer
+	binary.Write(&body, binary.LittleEndian, seed)
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	body.Write(binary.AppendUvarint(nil, uint64(len(settingsJSON))))
+	body.Write(settingsJSON)
+
+	world, err := compressSegment(body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	data := binary.LittleEndian.AppendUint16([]byte(replayMagic), formatVersion)
+	return append(data, world...), nil
+}
+
+// encodeSegment returns a segment holding frames and checksums, ready to be appended
+// to a replay file
+func encodeSegment(frames []coretypes.InputFrame, checksums []Checksum) ([]byte, error) {
+	var body bytes.Buffer
+	writeFrames(&body, frames)
+	writeChecksums(&body, checksums)
+	return compressSegment(body.Bytes())
+}
+
+// compressSegment compresses a segment body and prefixes it with its length
+func compressSegment(body []byte) ([]byte, error) {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	data := binary.LittleEndian.AppendUint32(nil, uint32(compressed.Len()))
+	return append(data, compressed.Bytes()...), nil
+}
+
+// writeFrames encodes frames as runs of identical frames
+func writeFrames(body *bytes.Buffer, frames []coretypes.InputFrame) {
+	var scratch [binary.MaxVarintLen64]byte
+	uvarint := func(v uint64) {
+		n := binary.PutUvarint(scratch[:], v)
+		body.Write(scratch[:n])
+	}
+	varint := func(v int64) {
+		n := binary.PutVarint(scratch[:], v)
+		body.Write(scratch[:n])
+	}
+
+	// Group identical consecutive frames into runs
+	type run struct {
+		frame  coretypes.InputFrame
+		repeat uint64
+	}
+	var runs []run
+	for _, frame := range frames {
+		frame = Quantize(frame)
+		if len(runs) > 0 && runs[len(runs)-1].frame == frame {
+			runs[len(runs)-1].repeat++
+			continue
+		}
+		runs = append(runs, run{frame: frame, repeat: 1})
+	}
+
+	uvarint(uint64(len(runs)))
+	var lastAimX, lastAimY int64
+	for _, rn := range runs {
+		uvarint(rn.repeat)
+		f := rn.frame
+		var flags byte
+		if f.Jump {
+			flags |= flagJump
+		}
+		if f.Sneak {
+			flags |= flagSneak
+		}
+		if f.Sprint {
+			flags |= flagSprint
+		}
+		if f.Break {
+			flags |= flagBreak
+		}
+		if f.Place {
+			flags |= flagPlace
+		}
+		if f.HotbarSlot != coretypes.NoHotbarChange {
+			flags |= flagHotbar
+		}
+		if f.Attack {
+			flags |= flagAttack
+		}
+		if f.Inventory.Action != coretypes.NoInventoryAction {
+			flags |= flagInventory
+		}
+		body.WriteByte(flags)
+		body.WriteByte(byte(int8(math.Round(f.MoveX * 127))))
+		if flags&flagHotbar != 0 {
+			body.WriteByte(byte(f.HotbarSlot))
+		}
+		if flags&flagInventory != 0 {
+			body.WriteByte(byte(f.Inventory.Action))
+			body.WriteByte(byte(f.Inventory.Slot))
+		}
+		// Aim is delta-encoded against the previous run
+		aimX, aimY := int64(f.AimX), int64(f.AimY)
+		varint(aimX - lastAimX)
+		varint(aimY - lastAimY)
+		lastAimX, lastAimY = aimX, aimY
+	}
+}
+
+// writeChecksums encodes checksums
+func writeChecksums(body *bytes.Buffer, checksums []Checksum) {
+	body.Write(binary.AppendUvarint(nil, uint64(len(checksums))))
+	for _, c := range checksums {
+		body.Write(binary.AppendUvarint(nil, c.Tick))
+		binary.Write(body, binary.LittleEndian, c.Player)
+		binary.Write(body, binary.LittleEndian, c.Chunks)
+	}
+}
+
+// Decode parses a replay written by Encode or by a Recorder streaming to a file
+func Decode(data []byte) (*Replay, error) {
+	if len(data) < 6 || string(data[:4]) != replayMagic {
+		return nil, errors.New("not a replay file")
+	}
+	version := binary.LittleEndian.Uint16(data[4:])
+	if version > formatVersion {
+		return nil, fmt.Errorf("replay version %d is newer than supported version %d", version, formatVersion)
+	}
+	r := &Replay{Version: version}
+
+	if version < 4 {
+		body, err := decompress(data[6:])
+		if err != nil {
+			return nil, err
+		}
+		if err := r.readWorld(body); err != nil {
+			return nil, err
+		}
+		if err := r.readInput(body); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+
+	rest := data[6:]
+	for segment := 0; len(rest) > 0; segment++ {
+		if len(rest) < segmentHeaderSize || uint64(binary.LittleEndian.Uint32(rest)) > uint64(len(rest)-segmentHeaderSize) {
+			if segment == 0 {
+				return nil, errors.New("replay header truncated")
+			}
+			break // Cut short while it was being written
+		}
+		size := int(binary.LittleEndian.Uint32(rest))
+		body, err := decompress(rest[segmentHeaderSize : segmentHeaderSize+size])
+		if err != nil {
+			return nil, fmt.Errorf("segment %d: %w", segment, err)
+		}
+		rest = rest[segmentHeaderSize+size:]
+
+		if segment == 0 {
+			err = r.readWorld(body)
+		} else {
+			err = r.readInput(body)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("segment %d: %w", segment, err)
+		}
+	}
+	if r.Settings == nil {
+		return nil, errors.New("replay header missing")
+	}
+	return r, nil
+}
+
+// decompress inflates a zlib body
+func decompress(data []byte) (*bytes.Reader, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := io.ReadAll(zr)
+	zr.Close()
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(raw), nil
+}
+
+// readWorld reads the seed and settings snapshot
+func (r *Replay) readWorld(body *bytes.Reader) error {
+	if err := binary.Read(body, binary.LittleEndian, &r.Seed); err != nil {
+		return fmt.Errorf("reading seed: %w", err)
+	}
+	settingsLen, err := binary.ReadUvarint(body)
+	if err != nil {
+		return fmt.Errorf("reading settings: %w", err)
+	}
+	if settingsLen > uint64(body.Len()) {
+		return errors.New("settings snapshot truncated")
+	}
+	settingsJSON := make([]byte, settingsLen)
+	if _, err := io.ReadFull(body, settingsJSON); err != nil {
+		return fmt.Errorf("reading settings: %w", err)
+	}
+	if err := json.Unmarshal(settingsJSON, &r.Settings); err != nil {
+		return fmt.Errorf("parsing settings: %w", err)
+	}
+	if r.Settings == nil {
+		r.Settings = map[string]string{}
+	}
+	return nil
+}
+
+// readInput reads a block of frame runs and checksums, appending them to the replay
+func (r *Replay) readInput(body *bytes.Reader) error {
+	runCount, err := binary.ReadUvarint(body)
+	if err != nil {
+		return fmt.Errorf("reading frames: %w", err)
+	}
+	var lastAimX, lastAimY int64
+	for i := uint64(0); i < runCount; i++ {
+		repeat, err := binary.ReadUvarint(body)
+		if err != nil {
+			return fmt.Errorf("reading frame run %d: %w", i, err)
+		}
+		flags, err := body.ReadByte()
+		if err != nil {
+			return fmt.Errorf("reading frame run %d: %w", i, err)
+		}
+		move, err := body.ReadByte()
+		if err != nil {
+			return fmt.Errorf("reading frame run %d: %w", i, err)
+		}
+		frame := coretypes.EmptyInputFrame()
+		frame.Jump = flags&flagJump != 0
+		frame.Sneak = flags&flagSneak != 0
+		frame.Sprint = flags&flagSprint != 0
+		frame.Break = flags&flagBreak != 0
+		frame.Place = flags&flagPlace != 0
+		frame.Attack = flags&flagAttack != 0
+		frame.MoveX = float64(int8(move)) / 127
+		if flags&flagHotbar != 0 {
+			slot, err := body.ReadByte()
+			if err != nil {
+				return fmt.Errorf("reading frame run %d: %w", i, err)
+			}
+			frame.HotbarSlot = int(slot)
+		}
+		if flags&flagInventory != 0 {
+			var op [2]byte
+			if _, err := io.ReadFull(body, op[:]); err != nil {
+				return fmt.Errorf("reading frame run %d: %w", i, err)
+			}
+			frame.Inventory = coretypes.InventoryOp{Action: coretypes.InventoryAction(op[0]), Slot: int(op[1])}
+		}
+		dx, err := binary.ReadVarint(body)
+		if err != nil {
+			return fmt.Errorf("reading frame run %d: %w", i, err)
+		}
+		dy, err := binary.ReadVarint(body)
+		if err != nil {
+			return fmt.Errorf("reading frame run %d: %w", i, err)
+		}
+		lastAimX += dx
+		lastAimY += dy
+		frame.AimX, frame.AimY = float64(lastAimX), float64(lastAimY)
+
+		for j := uint64(0); j < repeat; j++ {
+			r.Frames = append(r.Frames, frame)
+		}
+	}
+
+	checkCount, err := binary.ReadUvarint(body)
+	if err != nil {
+		return fmt.Errorf("reading checksums: %w", err)
+	}
+	for i := uint64(0); i < checkCount; i++ {
+		var c Checksum
+		if c.Tick, err = binary.ReadUvarint(body); err != nil {
+			return fmt.Errorf("reading checksum %d: %w", i, err)
+		}
+		if err := binary.Read(body, binary.LittleEndian, &c.Player); err != nil {
+			return fmt.Errorf("reading checksum %d: %w", i, err)
+		}
+		if err := binary.Read(body, binary.LittleEndian, &c.Chunks); err != nil {
+			return fmt.Errorf("reading checksum %d: %w", i, err)
+		}
+		r.Checksums = append(r.Checksums, c)
+	}
+
+	return nil
+}
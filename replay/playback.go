@@ -0,0 +1,114 @@
+package replay
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/input"
+	"github.com/KdntNinja/webcraft/settings"
+	"github.com/KdntNinja/webcraft/sim"
+)
+
+// Divergence describes the first checksum a playback failed to match
+type Divergence struct {
+	Tick           uint64
+	PlayerMismatch bool
+	ChunksMismatch bool
+	Expected       Checksum
+	Actual         Checksum
+}
+
+func (d *Divergence) Error() string {
+	what := "player state"
+	switch {
+	case d.PlayerMismatch && d.ChunksMismatch:
+		what = "player state and modified chunks"
+	case d.ChunksMismatch:
+		what = "modified chunks"
+	}
+	return fmt.Sprintf("replay diverged at tick %d: %s did not match (player %016x != %016x, chunks %016x != %016x)",
+		d.Tick, what, d.Actual.Player, d.Expected.Player, d.Actual.Chunks, d.Expected.Chunks)
+}
+
+// Player feeds a replay's frames back through the input path. It implements
+// input.InputSource, so the engine can play a replay exactly like live input.
+type Player struct {
+	Replay *Replay
+	next   int // Index of the next frame to return
+	check  int // Index of the next checksum to verify
+}
+
+// NewPlayer creates a playback source for a replay
+func NewPlayer(r *Replay) *Player {
+	return &Player{Replay: r}
+}
+
+// Poll implements input.InputSource
+func (p *Player) Poll(view input.View) coretypes.InputFrame {
+	if p.next >= len(p.Replay.Frames) {
+		return coretypes.EmptyInputFrame()
+	}
+	frame := p.Replay.Frames[p.next]
+	p.next++
+	return frame
+}
+
+// Done reports whether every recorded frame has been played
+func (p *Player) Done() bool {
+	return p.next >= len(p.Replay.Frames)
+}
+
+// Verify compares the simulation against the recorded checksum for its tick, if
+// there is one. It returns nil when the state matches or nothing was recorded.
+func (p *Player) Verify(s *sim.Simulation) *Divergence {
+	for p.check < len(p.Replay.Checksums) && p.Replay.Checksums[p.check].Tick < s.Tick {
+		p.check++
+	}
+	if p.check >= len(p.Replay.Checksums) || p.Replay.Checksums[p.check].Tick != s.Tick {
+		return nil
+	}
+
+	expected := p.Replay.Checksums[p.check]
+	p.check++
+	actual := Checksum{Tick: s.Tick, Player: s.PlayerChecksum(), Chunks: s.ModifiedChunksChecksum()}
+	if p.Replay.Version != 0 && p.Replay.Version < 3 {
+		actual.Chunks = expected.Chunks // Older chunk checksums only hashed the low byte of each block
+	}
+	if actual == expected {
+		return nil
+	}
+	return &Divergence{
+		Tick:           s.Tick,
+		PlayerMismatch: actual.Player != expected.Player,
+		ChunksMismatch: actual.Chunks != expected.Chunks,
+		Expected:       expected,
+		Actual:         actual,
+	}
+}
+
+// SettingsDiff lists the settings whose values differ between the replay and this build
+func (r *Replay) SettingsDiff() []string {
+	current := settings.Snapshot()
+	var diffs []string
+	for name, recorded := range r.Settings {
+		if now, ok := current[name]; !ok || now != recorded {
+			diffs = append(diffs, fmt.Sprintf("%s: recorded %s, now %s", name, recorded, now))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
+
+// Run plays a replay headlessly from a fresh world and returns the first divergence, if any
+func Run(r *Replay) *Divergence {
+	s := sim.NewHeadless(r.Seed)
+	player := NewPlayer(r)
+	for !player.Done() {
+		s.Step(player.Poll(input.View{}))
+		if d := player.Verify(s); d != nil {
+			return d
+		}
+	}
+	return nil
+}
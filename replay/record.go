@@ -0,0 +1,107 @@
+package replay
+
+import (
+	"fmt"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+	"github.com/KdntNinja/webcraft/sim"
+	"github.com/KdntNinja/webcraft/storage"
+)
+
+// Recorder captures the input of a session along with periodic checksums
+type Recorder struct {
+	Replay   *Replay // Frames and checksums not yet written to the file, or all of them without one
+	Interval uint64  // Ticks between checksums
+	Ticks    uint64  // Frames recorded so far, including ones already written out
+
+	backend storage.Backend // Where the replay file is streamed to, if anywhere
+	name    string
+	err     error // Set once writing fails; recording stops there
+}
+
+// NewRecorder starts recording a world created from seed, keeping everything in memory
+func NewRecorder(seed int64) *Recorder {
+	return &Recorder{
+		Replay: &Replay{
+			Seed:     seed,
+			Settings: settings.Snapshot(),
+		},
+		Interval: settings.ReplayChecksumInterval,
+	}
+}
+
+// NewFileRecorder starts recording a world created from seed into a replay file,
+// replacing any replay already there. Input is appended to the file every
+// settings.ReplayFlushTicks ticks, so a long session isn't held in memory.
+func NewFileRecorder(seed int64, backend storage.Backend, name string) (*Recorder, error) {
+	r := NewRecorder(seed)
+	header, err := encodeHeader(seed, r.Replay.Settings)
+	if err != nil {
+		return nil, err
+	}
+	if err := backend.WriteFile(name, header); err != nil {
+		return nil, err
+	}
+	r.backend, r.name = backend, name
+	return r, nil
+}
+
+// Record stores the frame for the next tick and returns the quantized frame the
+// simulation must be stepped with
+func (r *Recorder) Record(frame coretypes.InputFrame) coretypes.InputFrame {
+	frame = Quantize(frame)
+	r.Ticks++
+	if r.err != nil {
+		return frame
+	}
+	r.Replay.Frames = append(r.Replay.Frames, frame)
+	if r.backend != nil && len(r.Replay.Frames) >= settings.ReplayFlushTicks {
+		if err := r.Flush(); err != nil {
+			fmt.Printf("REPLAY: Recording stopped: %v\n", err)
+		}
+	}
+	return frame
+}
+
+// Checkpoint stores a checksum if the simulation is on a checksum tick
+func (r *Recorder) Checkpoint(s *sim.Simulation) {
+	if r.err != nil || r.Interval == 0 || s.Tick%r.Interval != 0 {
+		return
+	}
+	r.Replay.Checksums = append(r.Replay.Checksums, Checksum{
+		Tick:   s.Tick,
+		Player: s.PlayerChecksum(),
+		Chunks: s.ModifiedChunksChecksum(),
+	})
+}
+
+// Flush appends the frames and checksums recorded since the last flush to the
+// replay file. Recorders without a file keep everything and have nothing to flush.
+// If writing fails, recording stops so the file keeps a playable prefix.
+func (r *Recorder) Flush() error {
+	if r.backend == nil || r.err != nil {
+		return r.err
+	}
+	if len(r.Replay.Frames) == 0 && len(r.Replay.Checksums) == 0 {
+		return nil
+	}
+	data, err := encodeSegment(r.Replay.Frames, r.Replay.Checksums)
+	if err == nil {
+		err = r.backend.AppendFile(r.name, data)
+	}
+	if err != nil {
+		r.err = fmt.Errorf("writing %s: %w", r.name, err)
+		r.Replay.Frames, r.Replay.Checksums = nil, nil
+		return r.err
+	}
+	r.Replay.Frames = r.Replay.Frames[:0]
+	r.Replay.Checksums = r.Replay.Checksums[:0]
+	return nil
+}
+
+// Step records a frame, steps the simulation with it and checkpoints
+func (r *Recorder) Step(s *sim.Simulation, frame coretypes.InputFrame) {
+	s.Step(r.Record(frame))
+	r.Checkpoint(s)
+}
@@ -0,0 +1,87 @@
+package replay
+
+import (
+	"testing"
+
+	"github.com/KdntNinja/webcraft/input"
+	"github.com/KdntNinja/webcraft/settings"
+	"github.com/KdntNinja/webcraft/sim"
+	"github.com/KdntNinja/webcraft/storage"
+)
+
+func TestFileRecorderStreamsInput(t *testing.T) {
+	backend := storage.NewDirBackend(t.TempDir())
+	rec, err := NewFileRecorder(7, backend, "test.wcrp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := sim.NewHeadless(7)
+	src := input.NewScriptedSourceFromSteps(
+		input.ScriptStep{Ticks: settings.ReplayFlushTicks, Hold: []input.Action{input.MoveRight}},
+		input.ScriptStep{Ticks: settings.ReplayFlushTicks / 2, Hold: []input.Action{input.Jump, input.MoveLeft}},
+	)
+	for !src.Done() {
+		rec.Step(s, src.Poll(input.View{}))
+		if len(rec.Replay.Frames) >= settings.ReplayFlushTicks {
+			t.Fatalf("recorder holds %d frames, more than it should before flushing", len(rec.Replay.Frames))
+		}
+	}
+	if err := rec.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := backend.ReadFile("test.wcrp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Seed != 7 || uint64(len(r.Frames)) != rec.Ticks {
+		t.Fatalf("read seed %d and %d frames, want seed 7 and %d frames", r.Seed, len(r.Frames), rec.Ticks)
+	}
+	if d := Run(r); d != nil {
+		t.Fatal(d)
+	}
+
+	// A segment cut short mid-write is dropped; the rest still plays
+	truncated, err := Decode(data[:len(data)-3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(truncated.Frames) != settings.ReplayFlushTicks {
+		t.Fatalf("truncated replay has %d frames, want the %d from the first segment", len(truncated.Frames), settings.ReplayFlushTicks)
+	}
+	if d := Run(truncated); d != nil {
+		t.Fatal(d)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	rec := NewRecorder(3)
+	s := sim.NewHeadless(3)
+	src := input.NewScriptedSourceFromSteps(
+		input.ScriptStep{Ticks: 30, Hold: []input.Action{input.MoveRight}},
+		input.ScriptStep{Ticks: 20, Hold: []input.Action{input.Break}, AimX: 40, AimY: 60},
+	)
+	for !src.Done() {
+		rec.Step(s, src.Poll(input.View{}))
+	}
+	data, err := rec.Replay.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Frames) != 50 || len(r.Checksums) != len(rec.Replay.Checksums) {
+		t.Fatalf("decoded %d frames and %d checksums", len(r.Frames), len(r.Checksums))
+	}
+	for i := range r.Frames {
+		if r.Frames[i] != rec.Replay.Frames[i] {
+			t.Fatalf("frame %d changed: %+v != %+v", i, r.Frames[i], rec.Replay.Frames[i])
+		}
+	}
+}
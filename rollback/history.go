@@ -0,0 +1,58 @@
+package rollback
+
+import (
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/gameplay"
+)
+
+// History keeps the input and resulting player state for the most recent ticks
+// in a ring buffer indexed by tick
+type History struct {
+	entries []historyEntry
+}
+
+type historyEntry struct {
+	tick  uint64
+	valid bool
+	input coretypes.InputFrame
+	state gameplay.PlayerSnapshot // Player state after the tick ran
+}
+
+// NewHistory creates a history holding the last size ticks
+func NewHistory(size int) *History {
+	return &History{entries: make([]historyEntry, size)}
+}
+
+func (h *History) slot(tick uint64) *historyEntry {
+	return &h.entries[tick%uint64(len(h.entries))]
+}
+
+// Record stores the input a tick ran with and the player state it produced
+func (h *History) Record(tick uint64, input coretypes.InputFrame, state gameplay.PlayerSnapshot) {
+	*h.slot(tick) = historyEntry{tick: tick, valid: true, input: input, state: state}
+}
+
+// Input returns the input recorded for a tick, if it is still in the history
+func (h *History) Input(tick uint64) (coretypes.InputFrame, bool) {
+	e := h.slot(tick)
+	if !e.valid || e.tick != tick {
+		return coretypes.InputFrame{}, false
+	}
+	return e.input, true
+}
+
+// State returns the player state recorded after a tick, if it is still in the history
+func (h *History) State(tick uint64) (gameplay.PlayerSnapshot, bool) {
+	e := h.slot(tick)
+	if !e.valid || e.tick != tick {
+		return gameplay.PlayerSnapshot{}, false
+	}
+	return e.state, true
+}
+
+// setState replaces the state recorded for a tick during a replay
+func (h *History) setState(tick uint64, state gameplay.PlayerSnapshot) {
+	if e := h.slot(tick); e.valid && e.tick == tick {
+		e.state = state
+	}
+}
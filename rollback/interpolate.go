@@ -0,0 +1,65 @@
+package rollback
+
+import "github.com/KdntNinja/webcraft/settings"
+
+// Sample is one received state of a remote entity
+type Sample struct {
+	Tick   float64 // Local tick the sample belongs to
+	X, Y   float64
+	VX, VY float64
+}
+
+// Interpolator smooths a remote entity between received samples. Positions are
+// shown settings.InterpolationDelayTicks behind the newest sample, so there is
+// usually a sample on each side to blend between even with jittery delivery.
+type Interpolator struct {
+	samples []Sample // Ordered by tick
+}
+
+// Add inserts a sample, keeping samples ordered and dropping ones too old to matter
+func (in *Interpolator) Add(s Sample) {
+	i := len(in.samples)
+	for i > 0 && in.samples[i-1].Tick > s.Tick {
+		i--
+	}
+	in.samples = append(in.samples, Sample{})
+	copy(in.samples[i+1:], in.samples[i:])
+	in.samples[i] = s
+
+	// Keep one sample older than the delayed render point for blending
+	cutoff := in.samples[len(in.samples)-1].Tick - 2*settings.InterpolationDelayTicks
+	drop := 0
+	for drop < len(in.samples)-2 && in.samples[drop+1].Tick < cutoff {
+		drop++
+	}
+	in.samples = in.samples[drop:]
+}
+
+// At returns the interpolated position for a local tick, rendered with the
+// interpolation delay applied. ok is false until the first sample arrives.
+func (in *Interpolator) At(tick float64) (x, y float64, ok bool) {
+	if len(in.samples) == 0 {
+		return 0, 0, false
+	}
+	t := tick - settings.InterpolationDelayTicks
+
+	first := in.samples[0]
+	if t <= first.Tick {
+		return first.X, first.Y, true
+	}
+	for i := 1; i < len(in.samples); i++ {
+		a, b := in.samples[i-1], in.samples[i]
+		if t <= b.Tick {
+			f := (t - a.Tick) / (b.Tick - a.Tick)
+			return a.X + (b.X-a.X)*f, a.Y + (b.Y-a.Y)*f, true
+		}
+	}
+
+	// Past the newest sample: keep moving on its velocity for a little while
+	last := in.samples[len(in.samples)-1]
+	ahead := t - last.Tick
+	if ahead > settings.InterpolationMaxExtrapolation {
+		ahead = settings.InterpolationMaxExtrapolation
+	}
+	return last.X + last.VX*ahead, last.Y + last.VY*ahead, true
+}
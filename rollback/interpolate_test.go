@@ -0,0 +1,54 @@
+package rollback
+
+import (
+	"math"
+	"testing"
+
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+func TestInterpolatorAt(t *testing.T) {
+	var in Interpolator
+	if _, _, ok := in.At(100); ok {
+		t.Fatal("positioned an entity with no samples")
+	}
+
+	// Samples arrive out of order; the entity moves 2 pixels per tick along x
+	in.Add(Sample{Tick: 20, X: 40, Y: 10, VX: 2})
+	in.Add(Sample{Tick: 10, X: 20, Y: 10, VX: 2})
+	in.Add(Sample{Tick: 30, X: 60, Y: 30, VX: 2, VY: 2})
+
+	delay := float64(settings.InterpolationDelayTicks)
+	tests := []struct {
+		name string
+		tick float64
+		x, y float64
+	}{
+		{"before the oldest sample holds it", 10, 20, 10},
+		{"on a sample, after the delay", 20 + delay, 40, 10},
+		{"halfway between samples", 15 + delay, 30, 10},
+		{"blends both axes", 25 + delay, 50, 20},
+		{"extrapolates on the last velocity", 34 + delay, 68, 38},
+		{"stops extrapolating at the cap", 1000, 60 + 2*settings.InterpolationMaxExtrapolation, 30 + 2*settings.InterpolationMaxExtrapolation},
+	}
+	for _, tt := range tests {
+		x, y, ok := in.At(tt.tick)
+		if !ok || math.Abs(x-tt.x) > 1e-9 || math.Abs(y-tt.y) > 1e-9 {
+			t.Errorf("%s: At(%g) = (%g, %g, %v), want (%g, %g)", tt.name, tt.tick, x, y, ok, tt.x, tt.y)
+		}
+	}
+}
+
+func TestInterpolatorDropsOldSamples(t *testing.T) {
+	var in Interpolator
+	for tick := 0; tick <= 100; tick++ {
+		in.Add(Sample{Tick: float64(tick), X: float64(tick)})
+	}
+	if len(in.samples) > 2*settings.InterpolationDelayTicks+2 {
+		t.Fatalf("kept %d samples", len(in.samples))
+	}
+	// The delayed render point still has a sample on each side
+	if x, _, _ := in.At(100); x != 100-settings.InterpolationDelayTicks {
+		t.Fatalf("rendered at x=%g after dropping samples, want %d", x, 100-settings.InterpolationDelayTicks)
+	}
+}
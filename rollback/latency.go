@@ -0,0 +1,73 @@
+package rollback
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// LinkSimulator delays messages like a network link so prediction and
+// interpolation can be exercised offline. Delays are measured in ticks.
+type LinkSimulator[T any] struct {
+	Latency int     // Base one-way delay
+	Jitter  int     // Extra delay picked uniformly from 0..Jitter; can reorder messages
+	Loss    float64 // Fraction of messages dropped
+	rng     *rand.Rand
+	queue   []delayed[T]
+	seq     int
+}
+
+type delayed[T any] struct {
+	deliverAt uint64
+	seq       int // Send order, to keep delivery stable for equal times
+	msg       T
+}
+
+// NewLinkSimulator creates a link with the given delay, jitter and loss. The seed
+// makes the jitter and loss reproducible.
+func NewLinkSimulator[T any](latency, jitter int, loss float64, seed int64) *LinkSimulator[T] {
+	return &LinkSimulator[T]{
+		Latency: latency,
+		Jitter:  jitter,
+		Loss:    loss,
+		rng:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Send queues a message sent at tick now
+func (l *LinkSimulator[T]) Send(now uint64, msg T) {
+	if l.Loss > 0 && l.rng.Float64() < l.Loss {
+		return
+	}
+	delay := l.Latency
+	if l.Jitter > 0 {
+		delay += l.rng.Intn(l.Jitter + 1)
+	}
+	l.seq++
+	l.queue = append(l.queue, delayed[T]{deliverAt: now + uint64(delay), seq: l.seq, msg: msg})
+}
+
+// Receive returns the messages that have arrived by tick now, in arrival order
+func (l *LinkSimulator[T]) Receive(now uint64) []T {
+	sort.SliceStable(l.queue, func(i, j int) bool {
+		if l.queue[i].deliverAt != l.queue[j].deliverAt {
+			return l.queue[i].deliverAt < l.queue[j].deliverAt
+		}
+		return l.queue[i].seq < l.queue[j].seq
+	})
+
+	n := 0
+	for n < len(l.queue) && l.queue[n].deliverAt <= now {
+		n++
+	}
+	out := make([]T, n)
+	for i := range out {
+		out[i] = l.queue[i].msg
+	}
+	l.queue = l.queue[n:]
+	return out
+}
+
+// Pending returns the number of messages still in flight
+func (l *LinkSimulator[T]) Pending() int {
+	return len(l.queue)
+}
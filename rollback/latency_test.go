@@ -0,0 +1,84 @@
+package rollback
+
+import (
+	"slices"
+	"testing"
+)
+
+// deliveries sends one message per tick over a link and returns when each arrived,
+// -1 for ones that were lost
+func deliveries(link *LinkSimulator[int], ticks int) []int {
+	arrived := make([]int, ticks)
+	for i := range arrived {
+		arrived[i] = -1
+	}
+	for now := 0; now < ticks+100; now++ {
+		if now < ticks {
+			link.Send(uint64(now), now)
+		}
+		for _, msg := range link.Receive(uint64(now)) {
+			arrived[msg] = now
+		}
+	}
+	return arrived
+}
+
+func TestLinkSimulatorLatency(t *testing.T) {
+	link := NewLinkSimulator[int](5, 0, 0, 1)
+	for sent, at := range deliveries(link, 50) {
+		if at != sent+5 {
+			t.Fatalf("message sent at %d arrived at %d, want %d", sent, at, sent+5)
+		}
+	}
+	if link.Pending() != 0 {
+		t.Fatalf("%d messages never arrived", link.Pending())
+	}
+}
+
+func TestLinkSimulatorJitterAndLoss(t *testing.T) {
+	const ticks = 2000
+	arrived := deliveries(NewLinkSimulator[int](4, 6, 0.25, 9), ticks)
+
+	lost, reordered := 0, 0
+	last := -1
+	for sent, at := range arrived {
+		if at < 0 {
+			lost++
+			continue
+		}
+		if delay := at - sent; delay < 4 || delay > 10 {
+			t.Fatalf("message sent at %d took %d ticks, outside 4..10", sent, delay)
+		}
+		if at < last {
+			reordered++
+		}
+		last = max(last, at)
+	}
+	if lost < ticks/5 || lost > ticks*3/10 {
+		t.Errorf("lost %d of %d messages, want about a quarter", lost, ticks)
+	}
+	if reordered == 0 {
+		t.Error("jitter never reordered messages")
+	}
+
+	// The same seed gives the same link; another seed doesn't
+	if again := deliveries(NewLinkSimulator[int](4, 6, 0.25, 9), ticks); !slices.Equal(arrived, again) {
+		t.Error("the same seed delivered differently")
+	}
+	if other := deliveries(NewLinkSimulator[int](4, 6, 0.25, 10), ticks); slices.Equal(arrived, other) {
+		t.Error("a different seed delivered identically")
+	}
+}
+
+func TestLinkSimulatorKeepsSendOrderForEqualTimes(t *testing.T) {
+	link := NewLinkSimulator[int](3, 0, 0, 1)
+	for i := range 5 {
+		link.Send(10, i)
+	}
+	if got := link.Receive(12); len(got) != 0 {
+		t.Fatalf("received %v before the latency passed", got)
+	}
+	if got := link.Receive(13); !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Fatalf("received %v, want the send order", got)
+	}
+}
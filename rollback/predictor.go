@@ -0,0 +1,112 @@
+package rollback
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/gameplay"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// StepFunc runs one tick of movement for a player with the given input
+type StepFunc func(p *gameplay.Player, frame coretypes.InputFrame)
+
+// Predictor runs the local player ahead of the authoritative state. Every tick's
+// input and result is kept; when a correction for a past tick arrives, the player
+// is rewound to the corrected state and the later inputs are replayed on top, so
+// coyote time, jump buffering and held jumps survive the correction.
+type Predictor struct {
+	Player  *gameplay.Player
+	Step    StepFunc
+	Tick    uint64 // Latest recorded tick
+	history *History
+}
+
+// NewPredictor creates a predictor for a player. step is used to replay ticks.
+func NewPredictor(player *gameplay.Player, step StepFunc) *Predictor {
+	return &Predictor{
+		Player:  player,
+		Step:    step,
+		history: NewHistory(settings.RollbackHistoryTicks),
+	}
+}
+
+// Advance steps the player one tick with the input and records it
+func (p *Predictor) Advance(frame coretypes.InputFrame) {
+	p.Step(p.Player, frame)
+	p.Record(p.Tick+1, frame)
+}
+
+// Record stores a tick that was stepped elsewhere (for example by the full simulation)
+func (p *Predictor) Record(tick uint64, frame coretypes.InputFrame) {
+	p.Tick = tick
+	p.history.Record(tick, frame, p.Player.Snapshot())
+}
+
+// StateAt returns the predicted player state after a tick, if it is still in the history
+func (p *Predictor) StateAt(tick uint64) (gameplay.PlayerSnapshot, bool) {
+	return p.history.State(tick)
+}
+
+// Reconcile applies the authoritative movement for a past tick. If it differs from
+// what was predicted, the movement is rewound to it and every later tick is
+// replayed with its recorded input. Only position, velocity and jump state are
+// taken from the correction and replayed; inventory, health, mining and the rest
+// of the player stay as they are. Corrections older than the history snap the
+// player straight to the given position. Returns true if the player was changed.
+func (p *Predictor) Reconcile(tick uint64, state gameplay.PlayerSnapshot) bool {
+	if tick > p.Tick {
+		return false // From the future; nothing predicted to compare against yet
+	}
+
+	predicted, ok := p.history.State(tick)
+	if ok && closeEnough(predicted, state) {
+		return false
+	}
+	if !ok {
+		fmt.Printf("ROLLBACK: Correction for tick %d is older than the history, snapping\n", tick)
+		p.Player.SetMovement(state.Movement())
+		return true
+	}
+
+	// Replay on a copy that starts from the predicted state of that tick, so
+	// timers and everything else the step touches aren't advanced twice
+	ghost := *p.Player
+	ghost.Restore(predicted)
+	ghost.SetMovement(state.Movement())
+	p.history.setState(tick, predicted.WithMovement(state.Movement()))
+	for t := tick + 1; t <= p.Tick; t++ {
+		frame, ok := p.history.Input(t)
+		if !ok {
+			break
+		}
+		p.Step(&ghost, frame)
+		recorded, _ := p.history.State(t)
+		p.history.setState(t, recorded.WithMovement(ghost.Movement()))
+	}
+	p.Player.SetMovement(ghost.Movement())
+	return true
+}
+
+// CorrectPosition reconciles with an authoritative position and velocity for a past
+// tick, keeping the rest of the predicted movement for that tick
+func (p *Predictor) CorrectPosition(tick uint64, x, y, vx, vy float64) bool {
+	state, ok := p.history.State(tick)
+	if !ok {
+		state = p.Player.Snapshot()
+	}
+	state.AABB.X, state.AABB.Y = x, y
+	state.AABB.VX, state.AABB.VY = vx, vy
+	return p.Reconcile(tick, state)
+}
+
+// closeEnough reports whether a prediction matches the authoritative state within tolerance
+func closeEnough(predicted, actual gameplay.PlayerSnapshot) bool {
+	tolerance := settings.RollbackPositionTolerance
+	return math.Abs(predicted.AABB.X-actual.AABB.X) <= tolerance &&
+		math.Abs(predicted.AABB.Y-actual.AABB.Y) <= tolerance &&
+		math.Abs(predicted.AABB.VX-actual.AABB.VX) <= tolerance &&
+		math.Abs(predicted.AABB.VY-actual.AABB.VY) <= tolerance &&
+		predicted.AABB.OnGround == actual.AABB.OnGround
+}
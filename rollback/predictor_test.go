@@ -0,0 +1,37 @@
+package rollback
+
+import (
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/sim"
+)
+
+func TestReconcileRewindsOnlyMovement(t *testing.T) {
+	s := sim.NewHeadless(11)
+	player := s.Player()
+	pred := NewPredictor(player, s.MovePlayer)
+	frame := coretypes.EmptyInputFrame()
+	frame.MoveX = 1
+	for range 20 {
+		pred.Advance(frame)
+	}
+
+	player.AddToInventory(coretypes.Stone.Item(), 5)
+	player.Health = 40
+	player.Invulnerable = 7
+	before := player.Snapshot()
+
+	state, _ := pred.StateAt(5)
+	state.AABB.X += 48
+	if !pred.Reconcile(5, state) {
+		t.Fatal("a correction 48 pixels away was ignored")
+	}
+	if player.AABB.X == before.AABB.X {
+		t.Fatal("player wasn't moved by the correction")
+	}
+	if player.Health != 40 || player.Invulnerable != 7 || player.InventoryCount(coretypes.Stone.Item()) != 5 {
+		t.Fatalf("correction rewound more than movement: health %d, invulnerable %d, stone %d",
+			player.Health, player.Invulnerable, player.InventoryCount(coretypes.Stone.Item()))
+	}
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"math"
+	"time"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/gameplay"
+	"github.com/KdntNinja/webcraft/loot"
+	"github.com/KdntNinja/webcraft/netproto"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// handle applies one message from a client
+func (s *Server) handle(c *client, msg netproto.Message) {
+	if m, ok := msg.(*netproto.ChunkRequest); ok {
+		s.handleChunkRequest(c, m) // Takes the lock itself, around generation
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch m := msg.(type) {
+	case *netproto.BreakBlock:
+		s.handleBreakBlock(c, m)
+	case *netproto.PlaceBlock:
+		s.handlePlaceBlock(c, m)
+	case *netproto.PlayerState:
+		s.handlePlayerState(c, m)
+	case *netproto.InventoryAction:
+		s.handleInventoryAction(c, m)
+	}
+}
+
+// handleChunkRequest sends the requested chunks that are near the client's player.
+// Chunks are loaded or generated without holding the server mutex, so a slow chunk
+// doesn't hold up other clients; the chunk manager has its own locks. The mutex is
+// only taken to read the player's position and to encode each chunk, so no block
+// change lands halfway through one.
+func (s *Server) handleChunkRequest(c *client, m *netproto.ChunkRequest) {
+	s.mutex.Lock()
+	playerChunk := coretypes.ChunkCoordOfPixel(c.player.X, c.player.Y)
+	s.mutex.Unlock()
+
+	for _, coord := range m.Coords {
+		if abs(coord.X-playerChunk.X) > settings.ServerChunkRadius || abs(coord.Y-playerChunk.Y) > settings.ServerChunkRadius {
+			continue
+		}
+		chunk := s.chunks.GetChunk(coord.X, coord.Y)
+		if chunk == nil {
+			continue
+		}
+		s.mutex.Lock()
+		s.send(c, &netproto.ChunkData{Coord: coord, Chunk: chunk})
+		s.mutex.Unlock()
+	}
+}
+
+// handleBreakBlock breaks a block if it is in reach and the player has spent long
+// enough mining it with the pickaxe in hand, as counted from their PlayerState
+// reports, giving its loot to the player if their pickaxe can harvest it. There are
+// no item entities in multiplayer yet, so the loot goes straight into the inventory.
+func (s *Server) handleBreakBlock(c *client, m *netproto.BreakBlock) {
+	s.loadBlockChunk(m.X, m.Y)
+	blockType := s.World.GetBlockAt(m.X, m.Y)
+	// Blocks that break within the leeway may be gone before any report shows them being mined
+	needed := c.player.MiningTicks(blockType)
+	mining := c.player.Mining
+	mined := needed <= settings.ServerMiningLeeway ||
+		mining.Progress+settings.ServerMiningLeeway >= needed && mining.X == m.X && mining.Y == m.Y && mining.Block == blockType
+	if !mined || !c.player.InReach(m.X, m.Y) || !s.World.BreakBlock(m.X, m.Y) {
+		s.rejectBlockChange(c, m.X, m.Y)
+		return
+	}
+	c.player.StopMining()
+
+	rng := loot.Rand(s.World.Seed, m.X, m.Y, s.tick)
+	for _, stack := range c.player.Harvest(blockType, rng) {
+		c.player.AddStack(stack)
+	}
+	s.fluids.Wake(m.X, m.Y)
+	s.broadcast(&netproto.BlockDelta{X: m.X, Y: m.Y, Block: coretypes.Air}, nil)
+	s.sendInventory(c)
+}
+
+// handlePlaceBlock places a block from the player's inventory if it is in reach and the target is empty
+func (s *Server) handlePlaceBlock(c *client, m *netproto.PlaceBlock) {
+	s.loadBlockChunk(m.X, m.Y)
+	valid := c.player.InReach(m.X, m.Y) && c.player.InventoryCount(m.Block.Item()) > 0
+	if !valid || !s.World.PlaceBlock(m.X, m.Y, m.Block) {
+		s.rejectBlockChange(c, m.X, m.Y)
+		return
+	}
+
+	c.player.RemoveFromInventory(m.Block.Item(), 1)
+	s.fluids.Wake(m.X, m.Y)
+	s.broadcast(&netproto.BlockDelta{X: m.X, Y: m.Y, Block: m.Block}, nil)
+	s.sendInventory(c)
+}
+
+// handleInventoryAction applies an inventory screen action and sends back the result
+func (s *Server) handleInventoryAction(c *client, m *netproto.InventoryAction) {
+	c.player.ApplyInventoryOp(coretypes.InventoryOp{Action: m.Action, Slot: m.Slot})
+	s.sendInventory(c)
+}
+
+// rejectBlockChange sends the real block and inventory back so the client can undo its prediction
+func (s *Server) rejectBlockChange(c *client, blockX, blockY int) {
+	s.send(c, &netproto.BlockDelta{
+		X:     blockX,
+		Y:     blockY,
+		Block: s.World.GetBlockAt(blockX, blockY),
+		Level: wireLevel(s.World.LiquidLevel(blockX, blockY)),
+	})
+	s.sendInventory(c)
+}
+
+// handlePlayerState accepts the client's reported position unless it moved faster than
+// a player can or reports a velocity no player can have, in which case the client is
+// sent back to its last accepted position. The time a report may claim comes from the
+// server's clock: the first report pins the client's tick to it, and later ticks may
+// not run more than ServerTickLeeway ahead.
+func (s *Server) handlePlayerState(c *client, m *netproto.PlayerState) {
+	ticks := uint32(1)
+	if c.clockStart.IsZero() {
+		c.clockStart, c.clockTick = time.Now(), m.Tick
+	} else {
+		if m.Tick <= c.tick {
+			return // Stale, duplicate or going backwards
+		}
+		elapsed := uint64(time.Since(c.clockStart) * settings.TicksPerSecond / time.Second)
+		if uint64(m.Tick-c.clockTick) > elapsed+settings.ServerTickLeeway {
+			s.correctPlayer(c, m.Tick) // Claims more time than has passed
+			return
+		}
+		ticks = min(m.Tick-c.tick, settings.ServerMaxStateTicks)
+	}
+	s.trackMining(c, m, ticks)
+
+	dx, dy := m.X-c.player.X, m.Y-c.player.Y
+	maxDistance := settings.ServerMaxMoveSpeed * float64(ticks)
+	validVelocity := math.Abs(m.VX) <= settings.ServerMaxMoveSpeed && math.Abs(m.VY) <= settings.ServerMaxMoveSpeed
+	if !finite(m.X, m.Y, m.VX, m.VY) || !validVelocity || dx*dx+dy*dy > maxDistance*maxDistance {
+		s.correctPlayer(c, m.Tick)
+		c.tick = m.Tick
+		return
+	}
+
+	c.player.X, c.player.Y = m.X, m.Y
+	c.player.VX, c.player.VY = m.VX, m.VY
+	c.tick = m.Tick
+	c.moved = true
+}
+
+// trackMining counts the ticks a client reports spending on the block it is mining,
+// and takes the hotbar slot it reports as the one in hand. A new target only counts
+// from its first report, so skipping reports doesn't buy mining time.
+func (s *Server) trackMining(c *client, m *netproto.PlayerState, ticks uint32) {
+	if int(m.Slot) < settings.HotbarSize {
+		c.player.SelectedSlot = int(m.Slot)
+	}
+	if !m.Mining {
+		c.player.StopMining()
+		return
+	}
+	block := s.World.GetBlockAt(m.MineX, m.MineY)
+	mining := &c.player.Mining
+	if mining.Progress == 0 || mining.X != m.MineX || mining.Y != m.MineY || mining.Block != block {
+		*mining = gameplay.MiningState{X: m.MineX, Y: m.MineY, Block: block, Progress: 1}
+		return
+	}
+	mining.Progress += int(ticks)
+}
+
+// finite reports whether none of the values are NaN or infinite
+func finite(values ...float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// correctPlayer sends a client its last accepted position for a tick it reported
+func (s *Server) correctPlayer(c *client, tick uint32) {
+	correction := entityDelta(c)
+	correction.Tick = tick
+	s.send(c, correction)
+}
+
+// loadBlockChunk makes sure the chunk holding a block is loaded before it is read or changed
+func (s *Server) loadBlockChunk(blockX, blockY int) {
+	coord := coretypes.ChunkCoordOfBlock(blockX, blockY)
+	s.World.ChunkManager.GetChunk(coord.X, coord.Y)
+}
+
+// wireLevel converts a liquid fill level to its protocol form, where 0 means full
+func wireLevel(level int) uint8 {
+	if level >= coretypes.MaxLiquidLevel {
+		return 0
+	}
+	return uint8(level)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
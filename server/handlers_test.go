@@ -0,0 +1,146 @@
+package server
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/gameplay"
+	"github.com/KdntNinja/webcraft/netproto"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// testServer opens a server with a fresh world in a temporary save slot
+func testServer(t *testing.T) *Server {
+	s, err := open(filepath.Join(t.TempDir(), "server"), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// join adds a client the way the handshake does, without a connection
+func join(s *Server) *client {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.nextID++
+	c := &client{
+		id:     s.nextID,
+		player: gameplay.NewPlayer(s.spawn.X, s.spawn.Y, s.World),
+		send:   make(chan []byte, settings.ServerSendBuffer),
+		cancel: func() {},
+	}
+	s.clients[c.id] = c
+	s.World.Entities = append(s.World.Entities, c.player)
+	return c
+}
+
+// report sends the client's current position at a tick, mining the given block if mine is set
+func report(s *Server, c *client, tick uint32, slot uint8, mine bool, x, y int) {
+	s.handle(c, &netproto.PlayerState{Tick: tick, X: c.player.X, Y: c.player.Y, Slot: slot, Mining: mine, MineX: x, MineY: y})
+}
+
+func TestBreakBlockNeedsMiningTime(t *testing.T) {
+	s := testServer(t)
+	c := join(s)
+	pickaxe, _ := coretypes.ItemByName("Gold Pickaxe")
+	c.player.Inventory.Set(1, coretypes.ItemStack{Item: pickaxe, Count: 1})
+	stone, _ := coretypes.BlockTypeByName("Stone")
+	obsidian, _ := coretypes.BlockTypeByName("Obsidian")
+
+	// Two blocks right next to the player
+	cx, cy := c.player.Center()
+	x, y := int(cx/settings.TileSize)+1, int(cy/settings.TileSize)
+	s.loadBlockChunk(x, y)
+	s.World.SetBlockAt(x, y, stone)
+	s.World.SetBlockAt(x, y+1, obsidian)
+
+	breaks := func(x, y int) bool {
+		s.handle(c, &netproto.BreakBlock{X: x, Y: y})
+		return s.World.GetBlockAt(x, y) == coretypes.Air
+	}
+	if breaks(x, y) {
+		t.Fatal("broke stone without mining it")
+	}
+
+	// Mining with the pickaxe in slot 1: the break may arrive ServerMiningLeeway ticks early
+	c.player.SelectedSlot = 1
+	needed := c.player.MiningTicks(stone)
+	tick := uint32(1)
+	for ; tick < uint32(needed-settings.ServerMiningLeeway); tick++ {
+		report(s, c, tick, 1, true, x, y)
+	}
+	if breaks(x, y) {
+		t.Fatalf("broke stone after %d of %d ticks", tick-1, needed)
+	}
+	report(s, c, tick, 1, true, x, y)
+	if !breaks(x, y) {
+		t.Fatalf("didn't break stone after %d of %d ticks", tick, needed)
+	}
+	if c.player.Mining.Progress != 0 {
+		t.Fatal("mining progress kept after the block broke")
+	}
+
+	// Obsidian takes far longer than a few reports, and the slot comes from the client
+	for i := 0; i < 5; i++ {
+		tick++
+		report(s, c, tick, 0, true, x, y+1)
+	}
+	if c.player.SelectedSlot != 0 {
+		t.Fatalf("selected slot %d, want the reported slot 0", c.player.SelectedSlot)
+	}
+	if breaks(x, y+1) {
+		t.Fatal("broke obsidian after a few ticks")
+	}
+}
+
+func TestPlayerStateRejectsImpossibleVelocity(t *testing.T) {
+	s := testServer(t)
+	c := join(s)
+	tick := uint32(1)
+	report(s, c, tick, 0, false, 0, 0)
+
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1), settings.ServerMaxMoveSpeed * 2, -settings.ServerMaxMoveSpeed * 2} {
+		tick++
+		x := c.player.X
+		s.handle(c, &netproto.PlayerState{Tick: tick, X: x + 1, Y: c.player.Y, VX: v})
+		if c.player.X != x || c.player.VX != 0 {
+			t.Errorf("accepted velocity %v", v)
+		}
+		tick++
+		s.handle(c, &netproto.PlayerState{Tick: tick, X: x + 1, Y: c.player.Y, VY: v})
+		if c.player.X != x || c.player.VY != 0 {
+			t.Errorf("accepted vertical velocity %v", v)
+		}
+	}
+
+	tick++
+	x := c.player.X
+	s.handle(c, &netproto.PlayerState{Tick: tick, X: x + 1, Y: c.player.Y, VX: 1})
+	if c.player.X != x+1 || c.player.VX != 1 {
+		t.Fatal("refused an ordinary move")
+	}
+}
+
+func TestChunksUnloadWhenNoPlayerIsNear(t *testing.T) {
+	s := testServer(t)
+	c := join(s)
+	home := coretypes.ChunkCoordOfPixel(c.player.X, c.player.Y)
+	s.handle(c, &netproto.ChunkRequest{Coords: []coretypes.ChunkCoord{home, {X: home.X + 1, Y: home.Y}}})
+	if !s.chunks.IsChunkLoaded(home.X+1, home.Y) {
+		t.Fatal("requested chunk wasn't loaded")
+	}
+
+	s.unloadChunks()
+	if !s.chunks.IsChunkLoaded(home.X+1, home.Y) {
+		t.Fatal("unloaded a chunk next to a player")
+	}
+	s.mutex.Lock()
+	s.removeClient(c)
+	s.mutex.Unlock()
+	s.unloadChunks()
+	if n := s.chunks.GetLoadedChunkCount(); n != 0 {
+		t.Fatalf("%d chunks still loaded with nobody connected", n)
+	}
+}
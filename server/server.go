@@ -0,0 +1,408 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/fluid"
+	"github.com/KdntNinja/webcraft/gameplay"
+	"github.com/KdntNinja/webcraft/gameplay/world"
+	"github.com/KdntNinja/webcraft/generation"
+	"github.com/KdntNinja/webcraft/netproto"
+	"github.com/KdntNinja/webcraft/settings"
+	"github.com/KdntNinja/webcraft/storage"
+	"github.com/KdntNinja/webcraft/worldgen"
+)
+
+// Conn is a message-oriented connection to one client
+type Conn interface {
+	Read(ctx context.Context) ([]byte, error)
+	Write(ctx context.Context, data []byte) error
+	Close(reason string) error
+}
+
+// Server owns the authoritative world. Clients simulate their own player and
+// report its position; the server validates every block change and broadcasts
+// block and entity deltas to everyone else.
+type Server struct {
+	World *world.World
+	Seed  int64
+
+	spawn   worldgen.SpawnPoint
+	backend storage.Backend
+	chunks  *generation.ChunkManager // The world's chunk manager, for unloading
+
+	mutex   sync.Mutex // Guards World, fluids and clients
+	clients map[uint32]*client
+	nextID  uint32
+	fluids  *fluid.Simulator
+	tick    uint64 // Liquid simulation ticks run so far
+}
+
+// client is one connected player
+type client struct {
+	id         uint32
+	name       string
+	version    uint16
+	player     *gameplay.Player
+	tick       uint32    // Tick of the last accepted PlayerState
+	clockStart time.Time // Server time of the first PlayerState
+	clockTick  uint32    // Tick the first PlayerState reported
+	moved      bool      // Position changed since the last broadcast
+	send       chan []byte
+	cancel     context.CancelFunc
+}
+
+// New loads the server world from its save slot, or creates one with the given seed
+func New(seed int64) (*Server, error) {
+	loadBlockDefinitions()
+	return open(filepath.Join(settings.SaveDirectory, settings.ServerSaveDirectory), seed)
+}
+
+// open loads the server world saved at savePath, or creates one with the given seed
+func open(savePath string, seed int64) (*Server, error) {
+	backend := storage.NewDefaultBackend(savePath)
+	manifest, err := storage.LoadManifest(backend)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if manifest != nil {
+		seed = manifest.Seed
+	} else {
+		// Chunks left in the slot without a manifest belong to some other world
+		backup, err := storage.SetAsideWorld(savePath)
+		if err != nil {
+			return nil, err
+		}
+		if backup != "" {
+			fmt.Printf("SERVER: Moved leftover save files to %s\n", backup)
+		}
+	}
+
+	generator := generation.NewGenerator(seed, generation.DefaultConfig())
+	spawn := worldgen.FindSpawnPoint(generator)
+	if manifest != nil {
+		spawn.X, spawn.Y = manifest.Spawn.X, manifest.Spawn.Y
+	}
+
+	// Chunks are generated on demand as clients request them
+	chunkManager := generation.NewSyncChunkManager(generator, settings.ChunkViewDistance)
+	chunkManager.SetChunkStore(storage.NewRegionStore(backend, seed))
+	w := world.NewWorld(generator, chunkManager, spawn)
+	// The world starts with a local player; players are added as clients join instead
+	w.Entities = coretypes.Entities{}
+	if manifest != nil && manifest.DayLength > 0 {
+		w.Clock.Ticks, w.Clock.DayLength = manifest.Time, manifest.DayLength
+	}
+
+	fmt.Printf("SERVER: World ready with seed %d, spawn (%.1f, %.1f)\n", seed, spawn.X, spawn.Y)
+	return &Server{
+		World:   w,
+		Seed:    seed,
+		spawn:   spawn,
+		backend: backend,
+		chunks:  chunkManager,
+		clients: make(map[uint32]*client),
+		fluids:  fluid.New(w),
+	}, nil
+}
+
+// Run advances the clock and flows liquids at settings.TicksPerSecond, broadcasts entity deltas and
+// unloads chunks at settings.ServerTickRate and autosaves until ctx is done
+func (s *Server) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second / settings.ServerTickRate)
+	defer ticker.Stop()
+	worldTicker := time.NewTicker(time.Second / settings.TicksPerSecond)
+	defer worldTicker.Stop()
+	saveEvery := time.Duration(settings.AutosaveInterval/settings.TicksPerSecond) * time.Second
+	lastSave := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Save(); err != nil {
+				fmt.Printf("SERVER: Save failed: %v\n", err)
+			}
+			return
+		case <-worldTicker.C:
+			s.stepWorld()
+		case <-ticker.C:
+			s.broadcastMovement()
+			s.unloadChunks()
+			if time.Since(lastSave) >= saveEvery {
+				if err := s.Save(); err != nil {
+					fmt.Printf("SERVER: Save failed: %v\n", err)
+				}
+				lastSave = time.Now()
+			}
+		}
+	}
+}
+
+// stepWorld advances the clock and liquid flow around the connected players, and
+// broadcasts the changed cells
+func (s *Server) stepWorld() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tick++
+	s.World.Clock.Advance()
+	centers := make([]coretypes.ChunkCoord, 0, len(s.clients))
+	for _, c := range s.clients {
+		centers = append(centers, coretypes.ChunkCoordOfPixel(c.player.X, c.player.Y))
+	}
+	changes := s.fluids.Step(s.tick, centers)
+	if len(changes) == 0 {
+		return
+	}
+	batch := &netproto.BlockBatch{Deltas: make([]netproto.BlockDelta, len(changes))}
+	for i, change := range changes {
+		batch.Deltas[i] = netproto.BlockDelta{X: change.X, Y: change.Y, Block: change.Block, Level: wireLevel(change.Level)}
+	}
+	s.broadcast(batch, nil)
+}
+
+// unloadChunks unloads the chunks no connected player is near, saving the modified
+// ones first. Chunks are kept up to ServerChunkUnloadMargin past the radius clients
+// are sent chunks in.
+func (s *Server) unloadChunks() {
+	s.mutex.Lock()
+	centers := make([]coretypes.ChunkCoord, 0, len(s.clients))
+	for _, c := range s.clients {
+		centers = append(centers, coretypes.ChunkCoordOfPixel(c.player.X, c.player.Y))
+	}
+	s.mutex.Unlock()
+
+	// The chunk manager has its own locks; saving doesn't hold up client messages
+	if err := s.chunks.UnloadChunksOutside(centers, settings.ServerChunkRadius+settings.ServerChunkUnloadMargin); err != nil {
+		fmt.Printf("SERVER: Saving unloaded chunks failed: %v\n", err)
+	}
+}
+
+// Save writes modified chunks and the world manifest
+func (s *Server) Save() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.World.ChunkManager.SaveDirtyChunks(); err != nil {
+		return err
+	}
+	return storage.SaveManifest(s.backend, &storage.WorldManifest{
+		Seed:             s.Seed,
+		GeneratorVersion: generation.GeneratorVersion,
+		Spawn:            storage.Position{X: s.spawn.X, Y: s.spawn.Y},
+		Time:             s.World.Clock.Ticks,
+		DayLength:        s.World.Clock.DayLength,
+	})
+}
+
+// Serve runs one client connection until it closes or ctx is done
+func (s *Server) Serve(ctx context.Context, conn Conn) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c, err := s.handshake(ctx, conn, cancel)
+	if err != nil {
+		conn.Close(err.Error())
+		return err
+	}
+	defer s.leave(c)
+	fmt.Printf("SERVER: %s joined as player %d (protocol v%d)\n", c.name, c.id, c.version)
+
+	go s.writeLoop(ctx, conn, c)
+
+	for {
+		data, err := conn.Read(ctx)
+		if err != nil {
+			return err
+		}
+		msg, err := netproto.DecodeFromClient(data)
+		if err != nil {
+			conn.Close("malformed message")
+			return err
+		}
+		s.handle(c, msg)
+	}
+}
+
+// handshake negotiates the protocol version and adds the client's player to the world
+func (s *Server) handshake(ctx context.Context, conn Conn, cancel context.CancelFunc) (*client, error) {
+	helloCtx, helloCancel := context.WithTimeout(ctx, settings.ServerHandshakeTimeout*time.Second)
+	defer helloCancel()
+
+	data, err := conn.Read(helloCtx)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := netproto.DecodeFromClient(data)
+	if err != nil {
+		return nil, err
+	}
+	hello, ok := msg.(*netproto.Hello)
+	if !ok {
+		return nil, fmt.Errorf("expected hello, got message type %d", msg.Type())
+	}
+
+	version, err := netproto.Negotiate(hello.MinVersion, hello.MaxVersion)
+	if err != nil {
+		reject(ctx, conn, err.Error())
+		return nil, err
+	}
+	if hello.Blocks != coretypes.Blocks().Checksum() {
+		reject(ctx, conn, "block definitions differ from the server's")
+		return nil, errors.New("block registry mismatch")
+	}
+
+	s.mutex.Lock()
+	if len(s.clients) >= settings.ServerMaxPlayers {
+		s.mutex.Unlock()
+		reject(ctx, conn, "server is full")
+		return nil, errors.New("server is full")
+	}
+	s.nextID++
+	c := &client{
+		id:      s.nextID,
+		name:    hello.Name,
+		version: version,
+		player:  gameplay.NewPlayer(s.spawn.X, s.spawn.Y, s.World),
+		send:    make(chan []byte, settings.ServerSendBuffer),
+		cancel:  cancel,
+	}
+	s.clients[c.id] = c
+	s.World.Entities = append(s.World.Entities, c.player)
+	// Queued until the write loop starts, after the welcome below
+	s.sendInventory(c)
+	for _, other := range s.clients {
+		if other != c {
+			s.send(c, entityDelta(other))
+		}
+	}
+	worldClock := *s.World.Clock
+	s.mutex.Unlock()
+
+	welcome := &netproto.Welcome{
+		Version:   version,
+		PlayerID:  c.id,
+		Seed:      s.Seed,
+		SpawnX:    s.spawn.X,
+		SpawnY:    s.spawn.Y,
+		Time:      worldClock.Ticks,
+		DayLength: worldClock.DayLength,
+	}
+	if err := conn.Write(ctx, netproto.Encode(welcome)); err != nil {
+		s.mutex.Lock()
+		s.removeClient(c)
+		s.mutex.Unlock()
+		return nil, err
+	}
+	return c, nil
+}
+
+// reject tells a client why it can't join
+func reject(ctx context.Context, conn Conn, reason string) {
+	conn.Write(ctx, netproto.Encode(&netproto.Reject{Reason: reason}))
+}
+
+// writeLoop sends queued messages to the client in order
+func (s *Server) writeLoop(ctx context.Context, conn Conn, c *client) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case data := <-c.send:
+			if err := conn.Write(ctx, data); err != nil {
+				c.cancel()
+				return
+			}
+		}
+	}
+}
+
+// leave removes a disconnected client and tells everyone else
+func (s *Server) leave(c *client) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.removeClient(c)
+	s.broadcast(&netproto.EntityRemove{ID: c.id}, c)
+	fmt.Printf("SERVER: %s (player %d) left\n", c.name, c.id)
+}
+
+// removeClient drops a client and its player entity. Must be called with the mutex held.
+func (s *Server) removeClient(c *client) {
+	delete(s.clients, c.id)
+	for i, e := range s.World.Entities {
+		if e == coretypes.Entity(c.player) {
+			s.World.Entities = append(s.World.Entities[:i], s.World.Entities[i+1:]...)
+			break
+		}
+	}
+}
+
+// send queues a message for one client, disconnecting clients that fall too far
+// behind. Must be called with the mutex held.
+func (s *Server) send(c *client, m netproto.Message) {
+	select {
+	case c.send <- netproto.Encode(m):
+	default:
+		fmt.Printf("SERVER: Dropping %s (player %d): send buffer full\n", c.name, c.id)
+		c.cancel()
+	}
+}
+
+// broadcast queues a message for every client except skip. Must be called with the mutex held.
+func (s *Server) broadcast(m netproto.Message, skip *client) {
+	for _, c := range s.clients {
+		if c != skip {
+			s.send(c, m)
+		}
+	}
+}
+
+// broadcastMovement sends the position of every player that moved since the last tick
+func (s *Server) broadcastMovement() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, c := range s.clients {
+		if c.moved {
+			s.broadcast(entityDelta(c), c)
+			c.moved = false
+		}
+	}
+}
+
+// entityDelta describes a client's player for other clients
+func entityDelta(c *client) *netproto.EntityDelta {
+	return &netproto.EntityDelta{
+		ID:   c.id,
+		Tick: c.tick,
+		X:    c.player.X,
+		Y:    c.player.Y,
+		VX:   c.player.VX,
+		VY:   c.player.VY,
+	}
+}
+
+// sendInventory sends a client its authoritative inventory. Must be called with the mutex held.
+func (s *Server) sendInventory(c *client) {
+	s.send(c, &netproto.Inventory{Slots: c.player.Inventory.Slots, Cursor: c.player.Cursor})
+}
+
+// loadBlockDefinitions installs the block definitions file if there is one; clients
+// need the same file to join
+func loadBlockDefinitions() {
+	registry, err := coretypes.LoadBlockRegistryFile(settings.BlocksFile)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			fmt.Printf("SERVER: Ignoring %s: %v\n", settings.BlocksFile, err)
+		}
+		return
+	}
+	coretypes.SetBlockRegistry(registry)
+	fmt.Printf("SERVER: Loaded %d block definitions from %s\n", registry.Len(), settings.BlocksFile)
+}
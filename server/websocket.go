@@ -0,0 +1,56 @@
+//go:build !js || !wasm
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/coder/websocket"
+)
+
+// wsConn adapts a WebSocket connection to Conn. Messages are binary frames.
+type wsConn struct {
+	conn *websocket.Conn
+}
+
+func (c wsConn) Read(ctx context.Context) ([]byte, error) {
+	typ, data, err := c.conn.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if typ != websocket.MessageBinary {
+		return nil, errors.New("text frames are not part of the protocol")
+	}
+	return data, nil
+}
+
+func (c wsConn) Write(ctx context.Context, data []byte) error {
+	return c.conn.Write(ctx, websocket.MessageBinary, data)
+}
+
+func (c wsConn) Close(reason string) error {
+	return c.conn.Close(websocket.StatusPolicyViolation, reason)
+}
+
+// Handler accepts WebSocket clients and serves each on its own goroutine
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+			// The WASM client may be served from another origin during development
+			InsecureSkipVerify: true,
+		})
+		if err != nil {
+			fmt.Printf("SERVER: WebSocket upgrade from %s failed: %v\n", r.RemoteAddr, err)
+			return
+		}
+		defer conn.CloseNow()
+
+		err = s.Serve(r.Context(), wsConn{conn: conn})
+		if status := websocket.CloseStatus(err); status != websocket.StatusNormalClosure && status != websocket.StatusGoingAway {
+			fmt.Printf("SERVER: Connection from %s closed: %v\n", r.RemoteAddr, err)
+		}
+	})
+}
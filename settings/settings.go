@@ -4,11 +4,12 @@ import "runtime"
 
 // --- Biome/Surface Generation ---
 const (
-	BiomeCount         = 5    // Number of biome types
-	BiomeBlendDistance = 16   // Biome blend width in blocks
-	SurfaceBaseHeight  = 64   // Average surface Y
-	SurfaceHeightVar   = 60   // Surface height variation
-	TreeChance         = 0.08 // Probability of tree spawn per block
+	BiomeCount         = 5     // Number of biome types
+	BiomeBlendDistance = 16    // Biome blend width in blocks
+	BiomeScale         = 500.0 // Blocks across which temperature and humidity change
+	SurfaceBaseHeight  = 64    // Average surface Y
+	SurfaceHeightVar   = 60    // Surface height variation
+	TreeChance         = 0.08  // Probability of tree spawn per forest surface block
 )
 
 // --- Cave Generation Parameters ---
@@ -84,6 +85,13 @@ const (
 	OreVeinChance = 0.08 // Base chance for an ore vein to generate at a position
 )
 
+// --- Underground Structures ---
+const (
+	StructureAttempts = 2    // Places per chunk an underground structure may be tried
+	StructureChance   = 0.25 // Chance of each attempt going ahead, before the template's placement rules
+	StructureCaveOpen = 3    // Open cells a "near" template needs in or around its footprint
+)
+
 // --- Multithreading/Performance/Rendering ---
 const (
 	// --- Multithreading Configuration ---
@@ -212,6 +220,72 @@ const (
 	ChunkViewDistance = 2   // Chunks to keep loaded around the player (reduced from 3)
 )
 
+// --- Save/Persistence ---
+const (
+	SaveDirectory    = "saves"  // Root directory for world saves (native builds)
+	RegionSize       = 8        // Region files hold RegionSize x RegionSize chunks
+	RegionDirectory  = "region" // Subdirectory of a world save holding region files
+	DefaultWorldName = "world"  // Save slot loaded on startup and written by quicksave
+	AutosaveInterval = 3600     // Frames between automatic saves (60 seconds at 60 TPS)
+)
+
+// --- Input ---
+const (
+	BindingsFile = "bindings.json" // Optional control bindings overriding the defaults (native builds)
+)
+
+// --- Blocks ---
+const (
+	BlocksFile = "blocks.json" // Optional block definitions replacing the built-in registry (native builds)
+)
+
+// --- Liquids ---
+const (
+	FluidChunkRadius  = 2    // Liquids only flow in chunks this close to a player
+	FluidUpdateBudget = 2048 // Liquid cells updated per tick; the rest wait for the next tick
+	LiquidBuoyancy    = 0.9  // Upward acceleration when fully submerged (gravity is PlayerGravity)
+	SwimSpeed         = 3.0  // Upward speed while holding jump in a liquid
+)
+
+// --- Simulation ---
+const (
+	TicksPerSecond = 60 // Fixed simulation rate; every Update call advances the world one tick
+)
+
+// --- Replays ---
+const (
+	ReplayDirectory        = "replays"           // Directory replays are written to
+	ReplayFileName         = "last.wcrp"         // Replay of the current session, appended to as it is played
+	ReplayChecksumInterval = 60                  // Ticks between recorded state checksums
+	ReplayFlushTicks       = 10 * TicksPerSecond // Ticks of input kept in memory before being appended to the replay file
+)
+
+// --- Multiplayer ---
+const (
+	ServerWebSocketPath      = "/ws"    // HTTP path the game server accepts WebSocket clients on
+	ServerSaveDirectory      = "server" // Save slot (under SaveDirectory) holding the server's world
+	ServerTickRate           = 20       // Entity broadcasts per second
+	ServerMaxPlayers         = 16       // Connections beyond this are rejected
+	ServerMaxMoveSpeed       = 24.0     // Max pixels per tick a reported player may move before being corrected
+	ServerMaxStateTicks      = 10       // Most ticks of movement a single PlayerState may claim
+	ServerTickLeeway         = 30       // Ticks a client's reported tick may run ahead of the server's clock
+	ServerMiningLeeway       = 2        // Ticks short of a block's mining time a break may arrive; it is sent before the tick's PlayerState
+	ServerChunkRadius        = 3        // Chunks further than this from a player are not sent to them
+	ServerChunkUnloadMargin  = 2        // Chunks kept loaded past ServerChunkRadius, so walking along a chunk edge doesn't reload them
+	ServerSendBuffer         = 512      // Outgoing messages queued per client before it is disconnected
+	ServerHandshakeTimeout   = 10       // Seconds a client has to send its Hello
+	ClientConnectTimeout     = 10       // Seconds to wait for the server's Welcome
+	ClientInitialLoadTimeout = 15       // Seconds to wait for the chunks around spawn
+)
+
+// --- Rollback ---
+const (
+	RollbackHistoryTicks          = 120 // Ticks of input and player state kept for rewinding
+	RollbackPositionTolerance     = 0.5 // Pixels a correction may differ by before it triggers a rewind
+	InterpolationDelayTicks       = 6   // Remote entities are shown this many ticks behind their newest snapshot
+	InterpolationMaxExtrapolation = 10  // Ticks remote entities keep moving on their last velocity when snapshots stop
+)
+
 // --- Performance optimization flags ---
 var (
 	// These can be modified at runtime to tune performance
@@ -249,3 +323,97 @@ func GetOptimalWorkerCount(taskType string) int {
 	}
 	return cores
 }
+
+// --- Lighting ---
+const (
+	LightSolidFalloff  = 4    // Light lost entering a solid block
+	LightLiquidFalloff = 2    // Light lost entering a liquid
+	LightAmbient       = 0.04 // Brightness of completely unlit tiles (0 = black)
+)
+
+// --- Day/Night ---
+const (
+	DayLength          = 24000 // Ticks in a full day (about 6.7 minutes at 60 TPS)
+	StartTimeOfDay     = 0.1   // Time of day new worlds start at (0 = sunrise, 0.5 = sunset)
+	TwilightLength     = 0.08  // Fraction of the day that dawn and dusk each last
+	NightLightNewMoon  = 0.08  // Daylight strength on a moonless night
+	NightLightFullMoon = 0.25  // Daylight strength under a full moon
+)
+
+// --- Mobs ---
+const (
+	MobGroundFriction = 0.6  // Horizontal speed kept per tick by a walking mob that stops
+	MobAirResistance  = 0.95 // Horizontal speed kept per tick by a mob in the air
+	MobSteering       = 0.15 // How quickly flying and swimming mobs turn towards where they want to go
+	MobWanderMinTicks = 40   // Shortest time a wandering mob keeps going one way (or stands still)
+	MobWanderMaxTicks = 160  // Longest time a wandering mob keeps going one way
+	MobIdleChance     = 0.35 // Chance a wandering mob stands still instead of walking
+
+	MobCap              = 24 // Most mobs alive at once; chunks loading past it spawn none
+	MobChunkCap         = 3  // Most mobs in one chunk after it has spawned its own
+	MobSpawnAttempts    = 8  // Columns tried for a mob in each chunk as it loads
+	MobSpawnMinDistance = 16 // Hostile mobs never spawn within this many blocks of a player
+)
+
+// --- Combat ---
+const (
+	PlayerAttackDamage       = 6   // Damage of one melee swing
+	PlayerAttackReach        = 72  // Pixels from the player's centre to the edge of what a swing hits
+	PlayerAttackCooldown     = 20  // Ticks between swings
+	PlayerKnockback          = 6.0 // Horizontal speed a swing gives what it hits
+	MobKnockback             = 5.0 // Horizontal speed a hostile mob's hit gives a player
+	KnockbackLift            = 4.0 // Upward speed every hit gives, so knockback isn't eaten by ground friction
+	MobAttackCooldown        = 40  // Ticks between a hostile mob's hits
+	PlayerInvulnerableTicks  = 40  // Ticks a player ignores damage after being hurt
+	MobInvulnerableTicks     = 10  // Ticks a mob ignores damage after being hurt
+	RespawnInvulnerableTicks = 120 // Ticks a respawned player ignores damage
+)
+
+// --- Hazards ---
+const (
+	SafeFallSpeed      = 12.0 // Landing speed (pixels per tick) that still deals no damage, about a 3.5 block drop
+	FallDamagePerSpeed = 10.0 // Damage per pixel per tick of landing speed above SafeFallSpeed
+	MaxBreath          = 300  // Ticks a player can keep their head under liquid before drowning
+	BreathRecovery     = 5    // Breath regained per tick with the head out of liquid
+	DrownDamage        = 4    // Damage of each drowning hit once out of breath
+	DrownInterval      = 20   // Ticks between drowning hits
+	BurnDamage         = 3    // Damage of each burn while on fire
+	BurnInterval       = 15   // Ticks between burns
+	BurnTicks          = 90   // Ticks an entity keeps burning after it stops touching a burning block
+)
+
+// --- Items ---
+const (
+	MaxStackSize  = 64 // Most items in one inventory slot, unless the item says otherwise
+	HotbarSize    = 9  // Inventory slots on the hotbar; they come first in the inventory
+	InventorySize = 36 // Inventory slots, hotbar included
+)
+
+// --- Dropped items ---
+const (
+	ItemEntitySize     = TileSize / 2            // Collider and icon size of an item lying in the world, in pixels
+	ItemPickupDelay    = 10                      // Ticks after being dropped before an item can be picked up
+	ItemMagnetRadius   = TileSize * 3            // Pixels from a player's centre within which items are pulled in
+	ItemMagnetSpeed    = 6.0                     // Speed items are pulled towards a player at, in pixels per tick
+	ItemPickupRadius   = TileSize * 3 / 4        // Pixels from a player's centre at which an item is picked up
+	ItemMergeRadius    = TileSize                // Pixels between matching items that merge into one stack
+	ItemDespawnTicks   = 5 * 60 * TicksPerSecond // Ticks an item lies in the world before it disappears
+	ItemGroundFriction = 0.7                     // Horizontal speed kept per tick by an item on the ground
+	ItemAirResistance  = 0.98                    // Horizontal speed kept per tick by an item in the air
+	ItemScatterSpeed   = 2.0                     // Largest sideways speed an item is flung out at when dropped
+	ItemScatterLift    = 3.0                     // Upward speed an item is flung out at when dropped
+)
+
+// --- Crafting ---
+const (
+	CraftingStationRange = 4 // Blocks from the player a crafting station can be and still be used
+)
+
+// --- Mining ---
+const (
+	MiningTicksPerHardness      = 30  // Ticks to break a block of hardness 1 by hand
+	MiningUnharvestableSlowdown = 3.0 // Mining takes this many times longer when the pickaxe can't harvest the block
+	MiningSpeedCopper           = 2.0 // Mining speed of a copper pickaxe, relative to bare hands
+	MiningSpeedIron             = 3.0 // Mining speed of an iron pickaxe
+	MiningSpeedGold             = 4.0 // Mining speed of a gold pickaxe
+)
@@ -0,0 +1,50 @@
+package settings
+
+import "fmt"
+
+// Snapshot returns the settings that affect the simulation, formatted as strings.
+// Replays store it so playback on a build with different tuning can be flagged.
+func Snapshot() map[string]string {
+	values := map[string]interface{}{
+		"TicksPerSecond":         TicksPerSecond,
+		"TileSize":               TileSize,
+		"ChunkWidth":             ChunkWidth,
+		"ChunkHeight":            ChunkHeight,
+		"ChunkViewDistance":      ChunkViewDistance,
+		"MaxChunksPerFrame":      MaxChunksPerFrame,
+		"PhysicsUpdateInterval":  PhysicsUpdateInterval,
+		"PlayerColliderWidth":    PlayerColliderWidth,
+		"PlayerColliderHeight":   PlayerColliderHeight,
+		"PlayerMoveSpeed":        PlayerMoveSpeed,
+		"PlayerJumpSpeed":        PlayerJumpSpeed,
+		"PlayerGravity":          PlayerGravity,
+		"PlayerMaxFallSpeed":     PlayerMaxFallSpeed,
+		"PlayerWalkAccel":        PlayerWalkAccel,
+		"PlayerAirAccel":         PlayerAirAccel,
+		"PlayerGroundFriction":   PlayerGroundFriction,
+		"PlayerAirFriction":      PlayerAirFriction,
+		"PlayerSneakSpeed":       PlayerSneakSpeed,
+		"PlayerSneakAccel":       PlayerSneakAccel,
+		"PlayerSprintSpeed":      PlayerSprintSpeed,
+		"PlayerSprintAccel":      PlayerSprintAccel,
+		"PlayerCoyoteFrames":     PlayerCoyoteFrames,
+		"PlayerJumpBufferFrames": PlayerJumpBufferFrames,
+		"PlayerJumpHoldMax":      PlayerJumpHoldMax,
+		"PlayerJumpHoldForce":    PlayerJumpHoldForce,
+		"SurfaceBaseHeight":      SurfaceBaseHeight,
+		"SurfaceHeightVar":       SurfaceHeightVar,
+		"TreeChance":             TreeChance,
+		"PerlinAlpha":            PerlinAlpha,
+		"PerlinBeta":             PerlinBeta,
+		"PerlinOctaves":          PerlinOctaves,
+		"StructureAttempts":      StructureAttempts,
+		"StructureChance":        StructureChance,
+		"StructureCaveOpen":      StructureCaveOpen,
+	}
+
+	snapshot := make(map[string]string, len(values))
+	for name, value := range values {
+		snapshot[name] = fmt.Sprint(value)
+	}
+	return snapshot
+}
@@ -0,0 +1,121 @@
+package sim
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"sort"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+// hasher wraps FNV-64a with helpers for the value types the simulation hashes
+type hasher struct {
+	h   hash.Hash64
+	buf [8]byte
+}
+
+func newHasher() *hasher {
+	return &hasher{h: fnv.New64a()}
+}
+
+func (h *hasher) uint(v uint64) {
+	binary.LittleEndian.PutUint64(h.buf[:], v)
+	h.h.Write(h.buf[:])
+}
+
+func (h *hasher) float(v float64) {
+	h.uint(math.Float64bits(v))
+}
+
+func (h *hasher) chunk(coord coretypes.ChunkCoord, chunk *coretypes.Chunk) {
+	h.uint(uint64(int64(coord.X)))
+	h.uint(uint64(int64(coord.Y)))
+	if chunk == nil {
+		return
+	}
+	// Block IDs come from the data-driven registry, so hash the whole value; a
+	// byte would let blocks 256 IDs apart hash the same
+	var buf [4]byte
+	for _, row := range chunk.Blocks {
+		for _, b := range row {
+			binary.LittleEndian.PutUint32(buf[:], uint32(b))
+			h.h.Write(buf[:])
+		}
+	}
+}
+
+// sortedCoords returns chunk coordinates in a stable order for hashing
+func sortedCoords(coords []coretypes.ChunkCoord) []coretypes.ChunkCoord {
+	sort.Slice(coords, func(i, j int) bool {
+		if coords[i].X != coords[j].X {
+			return coords[i].X < coords[j].X
+		}
+		return coords[i].Y < coords[j].Y
+	})
+	return coords
+}
+
+// PlayerChecksum hashes the local player's position, velocity, health and inventory
+func (s *Simulation) PlayerChecksum() uint64 {
+	h := newHasher()
+	if p := s.Player(); p != nil {
+		h.float(p.X)
+		h.float(p.Y)
+		h.float(p.VX)
+		h.float(p.VY)
+		h.uint(uint64(p.Health))
+		h.uint(uint64(p.SelectedSlot))
+		for _, s := range p.Inventory.Slots {
+			h.uint(uint64(s.Item))
+			h.uint(uint64(s.Count))
+			h.uint(uint64(s.Damage))
+		}
+		h.uint(uint64(p.Cursor.Item))
+		h.uint(uint64(p.Cursor.Count))
+	}
+	return h.h.Sum64()
+}
+
+// ModifiedChunksChecksum hashes every chunk changed by block interactions so far.
+// Chunks that have since been unloaded contribute only their coordinates.
+func (s *Simulation) ModifiedChunksChecksum() uint64 {
+	h := newHasher()
+	coords := make([]coretypes.ChunkCoord, 0, len(s.modifiedChunks))
+	for coord := range s.modifiedChunks {
+		coords = append(coords, coord)
+	}
+	loaded := s.World.ChunkManager.GetAllChunks()
+	for _, coord := range sortedCoords(coords) {
+		h.chunk(coord, loaded[coord])
+	}
+	return h.h.Sum64()
+}
+
+// StateHash returns a checksum of the tick counter, every entity's position and the
+// player's velocity, health and inventory, plus all loaded chunks. Two simulations
+// that received the same seed and inputs always report the same hash.
+func (s *Simulation) StateHash() uint64 {
+	h := newHasher()
+	h.uint(s.Tick)
+
+	for _, e := range s.World.Entities {
+		x, y := e.GetPosition()
+		h.float(x)
+		h.float(y)
+	}
+	h.uint(s.PlayerChecksum())
+
+	// Map iteration order is random, so hash chunks in coordinate order
+	chunks := s.World.ChunkManager.GetAllChunks()
+	coords := make([]coretypes.ChunkCoord, 0, len(chunks))
+	for coord := range chunks {
+		coords = append(coords, coord)
+	}
+	for _, coord := range sortedCoords(coords) {
+		h.chunk(coord, chunks[coord])
+	}
+
+	return h.h.Sum64()
+}
@@ -0,0 +1,366 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/fluid"
+	"github.com/KdntNinja/webcraft/gameplay"
+	"github.com/KdntNinja/webcraft/gameplay/drop"
+	"github.com/KdntNinja/webcraft/gameplay/mob"
+	"github.com/KdntNinja/webcraft/gameplay/world"
+	"github.com/KdntNinja/webcraft/generation"
+	"github.com/KdntNinja/webcraft/loot"
+	"github.com/KdntNinja/webcraft/physics"
+	"github.com/KdntNinja/webcraft/settings"
+	"github.com/KdntNinja/webcraft/worldgen"
+)
+
+// Simulation advances the world one fixed tick at a time. Every step runs on the
+// caller's goroutine in the same order, so the same seed and input sequence always
+// produce the same world state.
+type Simulation struct {
+	World   *world.World
+	Tick    uint64           // Number of completed steps
+	Fluids  *fluid.Simulator // Nil when liquids are simulated elsewhere (multiplayer clients)
+	Spawner *mob.Spawner     // Spawns mobs as chunks load and despawns them as chunks unload
+
+	// DropItems spawns what broken blocks and dead players drop as item entities.
+	// When false, block drops go straight into the inventory and a dead player's
+	// items are lost, which is what multiplayer clients do while the server has
+	// no item entities.
+	DropItems bool
+
+	// Collision grid shared by all entities during a tick
+	physicsWorld   *physics.PhysicsWorld
+	entityGrid     *physics.SpatialGrid // Where every entity was at the start of the tick
+	gridOffsetX    int
+	gridOffsetY    int
+	lastChunkCount int
+
+	// Chunks changed by block interactions, for checksums
+	modifiedChunks map[coretypes.ChunkCoord]bool
+}
+
+// New wraps an existing world. The world should use a synchronous chunk manager
+// (generation.NewSyncChunkManager) for the simulation to be deterministic.
+func New(w *world.World) *Simulation {
+	s := &Simulation{
+		World:          w,
+		Fluids:         fluid.New(w),
+		Spawner:        mob.NewSpawner(w, w.Light, w.Generator.GetHeightAt, w.Seed),
+		DropItems:      true,
+		entityGrid:     physics.NewSpatialGrid(),
+		modifiedChunks: make(map[coretypes.ChunkCoord]bool),
+	}
+	s.Spawner.Biome = func(x int) string { return w.Generator.GetBiomeAt(x).Name }
+	w.ChunkManager.SetChunkListener(s.Spawner)
+	return s
+}
+
+// NewHeadless creates a deterministic world for the given seed without any renderer
+func NewHeadless(seed int64) *Simulation {
+	generator := generation.NewGenerator(seed, generation.DefaultConfig())
+	spawn := worldgen.FindSpawnPoint(generator)
+	chunkManager := generation.NewSyncChunkManager(generator, settings.ChunkViewDistance)
+	chunkManager.UpdatePlayerPosition(spawn.X, spawn.Y)
+	return New(world.NewWorld(generator, chunkManager, spawn))
+}
+
+// Player returns the local player (the first entity), or nil if there is none
+func (s *Simulation) Player() *gameplay.Player {
+	if len(s.World.Entities) == 0 {
+		return nil
+	}
+	player, _ := s.World.Entities[0].(*gameplay.Player)
+	return player
+}
+
+// GridOffset returns the block offset of the collision grid used in the last tick
+func (s *Simulation) GridOffset() (int, int) {
+	return s.gridOffsetX, s.gridOffsetY
+}
+
+// Step runs one tick in a fixed order: input, chunk streaming with lighting and mob
+// spawning, collision grid and spatial grid, each entity in slice order (update,
+// liquids, collide, hazards, inventory actions, block interactions and attacks),
+// merging and picking up dropped items, respawning dead players and removing dead
+// mobs and items, liquid flow, then the time of day
+func (s *Simulation) Step(frame coretypes.InputFrame) {
+	s.Tick++
+
+	// 1. Input
+	player := s.Player()
+	if player != nil {
+		player.SetInput(frame)
+	}
+
+	// 2. Chunk streaming around the player, then light new chunks so mobs can spawn in them
+	if player != nil {
+		s.World.ChunkManager.UpdatePlayerPosition(player.GetPosition())
+	}
+	s.World.Light.Sync()
+	if s.Spawner != nil {
+		s.Spawner.Update()
+	}
+
+	// 3. Collision grid, and the spatial grid mobs use to find players
+	s.rebuildGridIfNeeded()
+	s.entityGrid.Update(s.World.Entities)
+
+	// 4. Entities
+	for _, e := range s.World.Entities {
+		s.stepEntity(e)
+	}
+	s.collectItems()
+	s.respawnDead()
+	s.removeDead()
+
+	// 5. Liquids near the players
+	s.stepFluids()
+
+	// 6. Time of day
+	s.World.Clock.Advance()
+}
+
+// stepFluids advances liquid flow around every player
+func (s *Simulation) stepFluids() {
+	if s.Fluids == nil {
+		return
+	}
+	var centers []coretypes.ChunkCoord
+	for _, e := range s.World.Entities {
+		if p, ok := e.(*gameplay.Player); ok {
+			centers = append(centers, coretypes.ChunkCoordOfPixel(p.X, p.Y))
+		}
+	}
+	for _, change := range s.Fluids.Step(s.Tick, centers) {
+		s.markModified(change.X, change.Y)
+	}
+}
+
+// rebuildGridIfNeeded regenerates the collision grid when chunks changed or periodically
+func (s *Simulation) rebuildGridIfNeeded() {
+	chunkCount := s.World.ChunkManager.GetLoadedChunkCount()
+	if s.physicsWorld != nil && !s.World.IsGridDirty() && chunkCount == s.lastChunkCount &&
+		s.Tick%settings.PhysicsUpdateInterval != 0 {
+		return
+	}
+
+	var grid [][]int
+	grid, s.gridOffsetX, s.gridOffsetY = s.World.ToIntGrid()
+	s.physicsWorld = physics.NewPhysicsWorld(grid)
+	s.lastChunkCount = chunkCount
+}
+
+// stepEntity updates a single entity and resolves its collisions, hazards and interactions
+func (s *Simulation) stepEntity(e coretypes.Entity) {
+	s.moveEntity(e)
+
+	if h, ok := e.(interface{ ApplyHazards(*physics.PhysicsWorld) }); ok && s.physicsWorld != nil {
+		h.ApplyHazards(s.physicsWorld)
+	}
+
+	if p, ok := e.(*gameplay.Player); ok {
+		p.ApplyInventoryOp(p.Input.Inventory)
+		s.throwCursor(p)
+		s.applyBlockInteraction(p)
+		s.applyAttack(p)
+	}
+}
+
+// moveEntity updates an entity and resolves its collisions against the current grid
+func (s *Simulation) moveEntity(e coretypes.Entity) {
+	if g, ok := e.(interface{ SetGridOffset(x, y int) }); ok {
+		g.SetGridOffset(s.gridOffsetX, s.gridOffsetY)
+	}
+	if g, ok := e.(interface{ SetSpatialGrid(*physics.SpatialGrid) }); ok {
+		g.SetSpatialGrid(s.entityGrid)
+	}
+
+	e.Update()
+
+	if l, ok := e.(interface{ ApplyLiquids(*physics.PhysicsWorld) }); ok && s.physicsWorld != nil {
+		l.ApplyLiquids(s.physicsWorld)
+	}
+
+	if c, ok := e.(interface{ CollideBlocks(*physics.PhysicsWorld) }); ok && s.physicsWorld != nil {
+		c.CollideBlocks(s.physicsWorld)
+	}
+}
+
+// applyAttack swings at the mobs on the aimed side of the player, within reach
+func (s *Simulation) applyAttack(p *gameplay.Player) {
+	if !p.TryAttack() {
+		return
+	}
+	cx, cy := p.Center()
+	dir := 1.0
+	if p.Input.AimX < cx {
+		dir = -1
+	}
+
+	// The grid indexes entities by their top-left corner, so search a little wider
+	radius := float64(settings.PlayerAttackReach + settings.TileSize)
+	for _, e := range s.entityGrid.GetEntitiesInRadius(cx, cy, radius) {
+		target, ok := e.(coretypes.Living)
+		if !ok || target.EntityKind() == coretypes.KindPlayer {
+			continue
+		}
+		x, y := target.GetPosition()
+		w, h := target.ColliderSize()
+		// Nearest point of the target's collider to the player's centre
+		nx, ny := math.Max(x, math.Min(cx, x+w)), math.Max(y, math.Min(cy, y+h))
+		if (nx-cx)*dir < 0 || math.Hypot(nx-cx, ny-cy) > settings.PlayerAttackReach {
+			continue
+		}
+		target.Hurt(settings.PlayerAttackDamage, dir*settings.PlayerKnockback, -settings.KnockbackLift)
+	}
+}
+
+// respawnDead drops the inventory of every player that died this tick and brings
+// them back at the world spawn point
+func (s *Simulation) respawnDead() {
+	for _, e := range s.World.Entities {
+		p, ok := e.(*gameplay.Player)
+		if !ok || p.Health > 0 {
+			continue
+		}
+		stacks := p.DropInventory()
+		dropped := 0
+		for _, stack := range stacks {
+			dropped += stack.Count
+		}
+		fmt.Printf("SIM: Player died at (%.0f, %.0f) and dropped %d items\n", p.X, p.Y, dropped)
+		cx, cy := p.Center()
+		s.dropItems(stacks, cx, cy, s.randAt(cx, cy))
+
+		spawn := s.World.SpawnPoint
+		p.Respawn(spawn.X, spawn.Y)
+		// Make sure there is ground to land on before the chunks stream in around it
+		coord := coretypes.ChunkCoordOfPixel(spawn.X, spawn.Y)
+		s.World.ChunkManager.GetChunk(coord.X, coord.Y)
+	}
+}
+
+// collectItems merges dropped items lying together and lets players pick them up
+func (s *Simulation) collectItems() {
+	var items []*drop.Item
+	var players []*gameplay.Player
+	for _, e := range s.World.Entities {
+		switch e := e.(type) {
+		case *drop.Item:
+			items = append(items, e)
+		case *gameplay.Player:
+			players = append(players, e)
+		}
+	}
+	drop.Merge(items)
+	drop.Collect(items, players)
+}
+
+// throwCursor drops what a player was still carrying when they closed the inventory
+// screen because it didn't fit back into the inventory, so it isn't left on a cursor
+// nobody can see. Without item drops (multiplayer) it stays on the cursor, as it does
+// on the server.
+func (s *Simulation) throwCursor(p *gameplay.Player) {
+	if p.Input.Inventory.Action != coretypes.InventoryReturn || p.Cursor.IsEmpty() || !s.DropItems {
+		return
+	}
+	cx, cy := p.Center()
+	s.dropItems([]coretypes.ItemStack{p.Cursor}, cx, cy, s.randAt(cx, cy))
+	p.Cursor = coretypes.ItemStack{}
+}
+
+// dropItems spawns stacks as item entities centred on a pixel position, unless the
+// simulation doesn't drop items
+func (s *Simulation) dropItems(stacks []coretypes.ItemStack, x, y float64, rng *rand.Rand) {
+	if !s.DropItems {
+		return
+	}
+	for _, item := range drop.Scatter(stacks, x, y, rng) {
+		s.World.AddEntity(item)
+	}
+}
+
+// randAt returns the random source for things dropped at a pixel position this tick
+func (s *Simulation) randAt(x, y float64) *rand.Rand {
+	tileSize := float64(settings.TileSize)
+	return loot.Rand(s.World.Seed, int(math.Floor(x/tileSize)), int(math.Floor(y/tileSize)), s.Tick)
+}
+
+// removeDead drops entities that died this tick. Players stay; they respawn instead.
+func (s *Simulation) removeDead() {
+	alive := s.World.Entities[:0]
+	for _, e := range s.World.Entities {
+		if d, ok := e.(interface{ Dead() bool }); ok && d.Dead() {
+			continue
+		}
+		alive = append(alive, e)
+	}
+	clear(s.World.Entities[len(alive):])
+	s.World.Entities = alive
+}
+
+// MovePlayer re-simulates one tick of a player's movement with the given input,
+// without block interactions or advancing the tick. Rollback uses it to replay
+// inputs after a correction.
+func (s *Simulation) MovePlayer(p *gameplay.Player, frame coretypes.InputFrame) {
+	p.SetInput(frame)
+	s.moveEntity(p)
+}
+
+// applyBlockInteraction performs the break/place the player requested this tick
+func (s *Simulation) applyBlockInteraction(p *gameplay.Player) {
+	interaction := p.HandleBlockInteractions()
+	if interaction == nil {
+		return
+	}
+
+	switch interaction.Type {
+	case gameplay.BreakBlock:
+		// Blocks break once they have been mined for long enough
+		blockType := s.World.GetBlockAt(interaction.BlockX, interaction.BlockY)
+		if !p.Mine(interaction.BlockX, interaction.BlockY, blockType) {
+			return
+		}
+		if s.World.BreakBlock(interaction.BlockX, interaction.BlockY) {
+			tileSize := float64(settings.TileSize)
+			x, y := (float64(interaction.BlockX)+0.5)*tileSize, (float64(interaction.BlockY)+0.5)*tileSize
+			rng := s.randAt(x, y)
+			drops := p.Harvest(blockType, rng)
+			if s.DropItems {
+				s.dropItems(drops, x, y, rng)
+			} else {
+				for _, stack := range drops {
+					p.AddStack(stack)
+				}
+			}
+			s.markModified(interaction.BlockX, interaction.BlockY)
+			s.wakeFluids(interaction.BlockX, interaction.BlockY)
+		}
+	case gameplay.PlaceBlock:
+		// Only place if the item in hand is a block
+		if block := p.SelectedBlock(); block != coretypes.Air {
+			if s.World.PlaceBlock(interaction.BlockX, interaction.BlockY, block) {
+				p.RemoveFromInventory(block.Item(), 1)
+				s.markModified(interaction.BlockX, interaction.BlockY)
+				s.wakeFluids(interaction.BlockX, interaction.BlockY)
+			}
+		}
+	}
+}
+
+// wakeFluids lets liquids next to a changed block start flowing again
+func (s *Simulation) wakeFluids(blockX, blockY int) {
+	if s.Fluids != nil {
+		s.Fluids.Wake(blockX, blockY)
+	}
+}
+
+// markModified records that the chunk containing a block was changed
+func (s *Simulation) markModified(blockX, blockY int) {
+	s.modifiedChunks[coretypes.ChunkCoordOfBlock(blockX, blockY)] = true
+}
@@ -0,0 +1,83 @@
+package sim
+
+import (
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/gameplay/drop"
+)
+
+// scriptedFrame walks right, jumps now and then, and breaks and attacks the block
+// ahead every so often
+func scriptedFrame(s *Simulation, tick int) coretypes.InputFrame {
+	frame := coretypes.EmptyInputFrame()
+	frame.MoveX = 1
+	frame.Jump = tick%40 < 10
+	if p := s.Player(); p != nil && tick%50 < 20 {
+		frame.Break = true
+		frame.Attack = tick%50 == 0
+		frame.AimX, frame.AimY = p.X+40, p.Y+40
+	}
+	return frame
+}
+
+func TestSameSeedAndInputGiveSameState(t *testing.T) {
+	// Stepped in lockstep in one process, so nothing shared between them can hide
+	a, b := NewHeadless(1234), NewHeadless(1234)
+	for tick := range 600 {
+		a.Step(scriptedFrame(a, tick))
+		b.Step(scriptedFrame(b, tick))
+		if tick%60 == 59 && a.StateHash() != b.StateHash() {
+			t.Fatalf("simulations diverged by tick %d", a.Tick)
+		}
+	}
+	if a.PlayerChecksum() != b.PlayerChecksum() || a.ModifiedChunksChecksum() != b.ModifiedChunksChecksum() {
+		t.Fatal("checksums differ after the same run")
+	}
+
+	other := NewHeadless(4321)
+	for tick := range 600 {
+		other.Step(scriptedFrame(other, tick))
+	}
+	if other.StateHash() == a.StateHash() {
+		t.Fatal("a different seed produced the same state")
+	}
+}
+
+func TestClosingAFullInventoryDropsTheCursor(t *testing.T) {
+	s := NewHeadless(8)
+	p := s.Player()
+	stone, dirt := coretypes.Stone.Item(), coretypes.Dirt.Item()
+	for i := range p.Inventory.Slots {
+		p.Inventory.Set(i, coretypes.ItemStack{Item: stone, Count: stone.MaxStack()})
+	}
+	p.Cursor = coretypes.ItemStack{Item: dirt, Count: 5}
+
+	frame := coretypes.EmptyInputFrame()
+	frame.Inventory = coretypes.InventoryOp{Action: coretypes.InventoryReturn}
+	s.Step(frame)
+
+	if !p.Cursor.IsEmpty() {
+		t.Fatalf("still carrying %+v after closing the inventory", p.Cursor)
+	}
+	dropped := 0
+	for _, e := range s.World.Entities {
+		if item, ok := e.(*drop.Item); ok && item.Stack.Item == dirt {
+			dropped += item.Stack.Count
+		}
+	}
+	if dropped != 5 {
+		t.Fatalf("dropped %d dirt, want the 5 that didn't fit", dropped)
+	}
+	if p.InventoryCount(stone) != len(p.Inventory.Slots)*stone.MaxStack() {
+		t.Fatal("inventory changed")
+	}
+
+	// With room, the stack goes back into the inventory instead
+	p.Inventory.Set(3, coretypes.ItemStack{})
+	p.Cursor = coretypes.ItemStack{Item: dirt, Count: 2}
+	s.Step(frame)
+	if !p.Cursor.IsEmpty() || p.InventoryCount(dirt) != 2 {
+		t.Fatalf("carried dirt not put back: cursor %+v, %d dirt in the inventory", p.Cursor, p.InventoryCount(dirt))
+	}
+}
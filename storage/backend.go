@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// Backend is the raw byte store that save data is written to
+type Backend interface {
+	// ReadFile returns the contents of the named file, or an error wrapping os.ErrNotExist
+	ReadFile(name string) ([]byte, error)
+	// WriteFile replaces the contents of the named file
+	WriteFile(name string, data []byte) error
+	// AppendFile adds data to the end of the named file, creating it if needed
+	AppendFile(name string, data []byte) error
+	// ListFiles returns the names of the files directly inside dir, or nothing if dir doesn't exist
+	ListFiles(dir string) ([]string, error)
+	// RemoveFile deletes the named file; removing a missing file is not an error
+	RemoveFile(name string) error
+}
+
+// DirBackend stores files inside a directory on the local filesystem
+type DirBackend struct {
+	Root string
+}
+
+// NewDirBackend creates a backend rooted at the given directory
+func NewDirBackend(root string) *DirBackend {
+	return &DirBackend{Root: root}
+}
+
+// ReadFile reads a file relative to the backend root
+func (d *DirBackend) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(d.Root, filepath.FromSlash(name)))
+}
+
+// WriteFile writes a file relative to the backend root, going through a temporary
+// file so a crash mid-write never leaves a truncated save behind
+func (d *DirBackend) WriteFile(name string, data []byte) error {
+	path := filepath.Join(d.Root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// AppendFile appends to a file relative to the backend root. A crash mid-append can
+// leave a partial write at the end, so callers must be able to spot one.
+func (d *DirBackend) AppendFile(name string, data []byte) error {
+	path := filepath.Join(d.Root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ListFiles lists the regular files in a directory relative to the backend root
+func (d *DirBackend) ListFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(d.Root, filepath.FromSlash(dir)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && filepath.Ext(entry.Name()) != ".tmp" {
+			names = append(names, path.Join(dir, entry.Name()))
+		}
+	}
+	return names, nil
+}
+
+// RemoveFile deletes a file relative to the backend root
+func (d *DirBackend) RemoveFile(name string) error {
+	err := os.Remove(filepath.Join(d.Root, filepath.FromSlash(name)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// CopyWorld copies a saved world's manifest and region files from one backend to another
+func CopyWorld(src, dst Backend) error {
+	regions, err := src.ListFiles(settings.RegionDirectory)
+	if err != nil {
+		return err
+	}
+	for _, name := range append(regions, manifestFileName) {
+		data, err := src.ReadFile(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := dst.WriteFile(name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasWorld reports whether a backend holds a saved world's manifest or region files
+func HasWorld(backend Backend) (bool, error) {
+	regions, err := backend.ListFiles(settings.RegionDirectory)
+	if err != nil || len(regions) > 0 {
+		return len(regions) > 0, err
+	}
+	_, err = backend.ReadFile(manifestFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// MoveWorld moves a saved world's manifest and region files from one backend to another.
+// The source files are only removed once everything has been copied.
+func MoveWorld(src, dst Backend) error {
+	if err := CopyWorld(src, dst); err != nil {
+		return err
+	}
+	regions, err := src.ListFiles(settings.RegionDirectory)
+	if err != nil {
+		return err
+	}
+	for _, name := range append(regions, manifestFileName) {
+		if err := src.RemoveFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetAsideWorld moves a world saved at path into the first free backup slot beside it
+// ("<path>-old1", "<path>-old2", ...) so a new world can start there without losing the
+// old one, even if it couldn't be read. Returns the backup's path, or "" if nothing was
+// saved at path.
+func SetAsideWorld(path string) (string, error) {
+	backend := NewDefaultBackend(path)
+	if saved, err := HasWorld(backend); err != nil || !saved {
+		return "", err
+	}
+	for i := 1; ; i++ {
+		backupPath := fmt.Sprintf("%s-old%d", path, i)
+		backup := NewDefaultBackend(backupPath)
+		taken, err := HasWorld(backup)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return backupPath, MoveWorld(backend, backup)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+func TestSetAsideWorldKeepsOldSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "world")
+	saveWorld := func(seed int64) {
+		backend := NewDefaultBackend(path)
+		if err := NewRegionStore(backend, seed).SaveChunk(coretypes.ChunkCoord{X: 1, Y: 1}, testChunk()); err != nil {
+			t.Fatal(err)
+		}
+		if err := SaveManifest(backend, &WorldManifest{Seed: seed}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if backup, err := SetAsideWorld(path); err != nil || backup != "" {
+		t.Fatalf("empty slot set aside to %q: %v", backup, err)
+	}
+	for seed := int64(1); seed <= 2; seed++ {
+		saveWorld(seed)
+		backup, err := SetAsideWorld(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		manifest, err := LoadManifest(NewDefaultBackend(backup))
+		if err != nil || manifest.Seed != seed {
+			t.Fatalf("backup %s holds %+v, %v; want seed %d", backup, manifest, err, seed)
+		}
+		if _, ok, err := NewRegionStore(NewDefaultBackend(backup), seed).LoadChunk(coretypes.ChunkCoord{X: 1, Y: 1}); !ok || err != nil {
+			t.Fatalf("backup %s lost its chunk: ok=%v err=%v", backup, ok, err)
+		}
+	}
+
+	if saved, err := HasWorld(NewDefaultBackend(path)); saved || err != nil {
+		t.Fatalf("slot still holds a world after setting it aside: %v", err)
+	}
+	if saved, _ := HasWorld(NewDefaultBackend(path + "-old2")); !saved {
+		t.Fatal("second world wasn't given its own backup slot")
+	}
+}
+
+func TestSetAsideWorldKeepsUnreadableManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "world")
+	if err := NewDefaultBackend(path).WriteFile(manifestFileName, []byte("{not json")); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := SetAsideWorld(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := NewDefaultBackend(backup).ReadFile(manifestFileName)
+	if err != nil || string(data) != "{not json" {
+		t.Fatalf("unreadable manifest not kept in %s: %q, %v", backup, data, err)
+	}
+}
@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// EncodeChunk serializes a chunk's blocks into an uncompressed payload.
+// Blocks are stored as indices into a palette of block names so that reordering
+// or extending coretypes.BlockType never corrupts existing saves.
+func EncodeChunk(chunk *coretypes.Chunk) []byte {
+	height := len(chunk.Blocks)
+	width := 0
+	if height > 0 {
+		width = len(chunk.Blocks[0])
+	}
+
+	// Build the palette in first-seen order
+	paletteIndex := make(map[coretypes.BlockType]uint64)
+	var palette []coretypes.BlockType
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			b := chunk.Blocks[y][x]
+			if _, ok := paletteIndex[b]; !ok {
+				paletteIndex[b] = uint64(len(palette))
+				palette = append(palette, b)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	var scratch [binary.MaxVarintLen64]byte
+	writeUvarint := func(v uint64) {
+		n := binary.PutUvarint(scratch[:], v)
+		buf.Write(scratch[:n])
+	}
+
+	binary.Write(&buf, binary.LittleEndian, uint16(BlockFormatVersion))
+	binary.Write(&buf, binary.LittleEndian, uint16(width))
+	binary.Write(&buf, binary.LittleEndian, uint16(height))
+
+	writeUvarint(uint64(len(palette)))
+	for _, b := range palette {
+		name := b.String()
+		writeUvarint(uint64(len(name)))
+		buf.WriteString(name)
+	}
+
+	// Run-length encode palette indices row by row
+	var runIndex, runLength uint64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			idx := paletteIndex[chunk.Blocks[y][x]]
+			if runLength > 0 && idx == runIndex {
+				runLength++
+				continue
+			}
+			if runLength > 0 {
+				writeUvarint(runLength)
+				writeUvarint(runIndex)
+			}
+			runIndex, runLength = idx, 1
+		}
+	}
+	if runLength > 0 {
+		writeUvarint(runLength)
+		writeUvarint(runIndex)
+	}
+
+	// Liquid levels follow as a flag byte and, if set, run-length encoded levels
+	if chunk.Levels == nil {
+		buf.WriteByte(0)
+		return buf.Bytes()
+	}
+	buf.WriteByte(1)
+	var runLevel uint8
+	runLength = 0
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			level := chunk.Levels[y][x]
+			if runLength > 0 && level == runLevel {
+				runLength++
+				continue
+			}
+			if runLength > 0 {
+				writeUvarint(runLength)
+				buf.WriteByte(runLevel)
+			}
+			runLevel, runLength = level, 1
+		}
+	}
+	if runLength > 0 {
+		writeUvarint(runLength)
+		buf.WriteByte(runLevel)
+	}
+
+	return buf.Bytes()
+}
+
+// DecodeChunk restores a chunk from a payload written by EncodeChunk, running any
+// registered block migrations for payloads written by older format versions.
+// Payloads may come from the network, so sizes are checked before anything is allocated.
+func DecodeChunk(data []byte) (*coretypes.Chunk, error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+
+	var version, width, height uint16
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return nil, fmt.Errorf("reading chunk version: %w", err)
+	}
+	if version > BlockFormatVersion {
+		return nil, fmt.Errorf("chunk format version %d is newer than supported version %d", version, BlockFormatVersion)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &width); err != nil {
+		return nil, fmt.Errorf("reading chunk width: %w", err)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &height); err != nil {
+		return nil, fmt.Errorf("reading chunk height: %w", err)
+	}
+	if width != settings.ChunkWidth || height != settings.ChunkHeight {
+		return nil, fmt.Errorf("chunk is %dx%d, expected %dx%d", width, height, settings.ChunkWidth, settings.ChunkHeight)
+	}
+	total := int(width) * int(height)
+
+	paletteLen, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading palette size: %w", err)
+	}
+	if paletteLen > uint64(total) || paletteLen > uint64(len(data)) {
+		return nil, fmt.Errorf("palette size %d is larger than the chunk", paletteLen)
+	}
+	palette := make([]coretypes.BlockType, paletteLen)
+	for i := range palette {
+		nameLen, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("reading palette entry: %w", err)
+		}
+		if nameLen > uint64(len(data)) {
+			return nil, errors.New("palette entry overflows payload")
+		}
+		name := make([]byte, nameLen)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return nil, fmt.Errorf("reading palette entry: %w", err)
+		}
+		palette[i] = resolveBlockName(migrateBlockName(string(name), version))
+	}
+
+	chunk := &coretypes.Chunk{Blocks: make([][]coretypes.BlockType, height)}
+	for y := range chunk.Blocks {
+		chunk.Blocks[y] = make([]coretypes.BlockType, width)
+	}
+
+	for pos := 0; pos < total; {
+		runLength, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("reading block run: %w", err)
+		}
+		idx, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("reading block run: %w", err)
+		}
+		if idx >= uint64(len(palette)) {
+			return nil, fmt.Errorf("palette index %d out of range", idx)
+		}
+		if runLength == 0 || runLength > uint64(total-pos) {
+			return nil, errors.New("block run overflows chunk")
+		}
+		for i := 0; i < int(runLength); i++ {
+			chunk.Blocks[pos/int(width)][pos%int(width)] = palette[idx]
+			pos++
+		}
+	}
+
+	if version < 2 {
+		return chunk, nil // Liquids in older saves are full
+	}
+	hasLevels, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("reading liquid levels: %w", err)
+	}
+	if hasLevels == 0 {
+		return chunk, nil
+	}
+	for pos := 0; pos < total; {
+		runLength, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("reading liquid run: %w", err)
+		}
+		level, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("reading liquid run: %w", err)
+		}
+		if runLength == 0 || runLength > uint64(total-pos) {
+			return nil, errors.New("liquid run overflows chunk")
+		}
+		for i := 0; i < int(runLength); i++ {
+			chunk.SetLiquidLevel(pos%int(width), pos/int(width), int(level))
+			pos++
+		}
+	}
+
+	return chunk, nil
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+func TestChunkCodecRoundTrip(t *testing.T) {
+	dry := testChunk()
+	dry.Blocks[10][4], dry.Blocks[10][5] = coretypes.Air, coretypes.Air
+	dry.Levels = nil
+
+	for name, chunk := range map[string]*coretypes.Chunk{"liquid": testChunk(), "dry": dry} {
+		decoded, err := DecodeChunk(EncodeChunk(chunk))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !sameChunk(chunk, decoded) {
+			t.Fatalf("%s: chunk changed in a round trip", name)
+		}
+		if (chunk.Levels == nil) != (decoded.Levels == nil) {
+			t.Fatalf("%s: liquid levels allocated %v, want %v", name, decoded.Levels != nil, chunk.Levels != nil)
+		}
+	}
+}
+
+func TestDecodeChunkRejectsDamagedPayloads(t *testing.T) {
+	data := EncodeChunk(testChunk())
+	for _, n := range []int{0, 3, 6, len(data) / 2, len(data) - 1} {
+		if _, err := DecodeChunk(data[:n]); err == nil {
+			t.Errorf("decoded a payload cut to %d of %d bytes", n, len(data))
+		}
+	}
+
+	small := &coretypes.Chunk{Blocks: make([][]coretypes.BlockType, settings.ChunkHeight/2)}
+	for y := range small.Blocks {
+		small.Blocks[y] = make([]coretypes.BlockType, settings.ChunkWidth)
+	}
+	if _, err := DecodeChunk(EncodeChunk(small)); err == nil {
+		t.Error("decoded a chunk of the wrong size")
+	}
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// testChunk returns a standard-size chunk with a stone floor, a water pool holding
+// a partly filled cell, and some torches
+func testChunk() *coretypes.Chunk {
+	chunk := &coretypes.Chunk{Blocks: make([][]coretypes.BlockType, settings.ChunkHeight)}
+	for y := range chunk.Blocks {
+		chunk.Blocks[y] = make([]coretypes.BlockType, settings.ChunkWidth)
+		for x := range chunk.Blocks[y] {
+			if y > settings.ChunkHeight/2 {
+				chunk.Blocks[y][x] = coretypes.Stone
+			}
+		}
+	}
+	chunk.Blocks[10][3] = coretypes.Torch
+	chunk.Blocks[10][4] = coretypes.Water
+	chunk.Blocks[10][5] = coretypes.Water
+	chunk.SetLiquidLevel(5, 10, 3)
+	return chunk
+}
+
+// sameChunk reports whether two chunks hold the same blocks and liquid levels
+func sameChunk(a, b *coretypes.Chunk) bool {
+	if len(a.Blocks) != len(b.Blocks) {
+		return false
+	}
+	for y := range a.Blocks {
+		if len(a.Blocks[y]) != len(b.Blocks[y]) {
+			return false
+		}
+		for x := range a.Blocks[y] {
+			if a.Blocks[y][x] != b.Blocks[y][x] || a.LiquidLevel(x, y) != b.LiquidLevel(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
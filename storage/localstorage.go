@@ -0,0 +1,88 @@
+//go:build js && wasm
+
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"syscall/js"
+)
+
+// LocalStorageBackend stores files as base64 strings in the browser's localStorage
+type LocalStorageBackend struct {
+	Prefix string
+}
+
+// NewDefaultBackend returns the browser storage backend, namespacing keys under root
+func NewDefaultBackend(root string) Backend {
+	return &LocalStorageBackend{Prefix: "webcraft/" + root + "/"}
+}
+
+// ReadFile reads a file from localStorage
+func (l *LocalStorageBackend) ReadFile(name string) ([]byte, error) {
+	storage := js.Global().Get("localStorage")
+	if !storage.Truthy() {
+		return nil, fmt.Errorf("localStorage unavailable: %w", fs.ErrNotExist)
+	}
+	value := storage.Call("getItem", l.Prefix+name)
+	if value.IsNull() || value.IsUndefined() {
+		return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+	}
+	return base64.StdEncoding.DecodeString(value.String())
+}
+
+// WriteFile writes a file to localStorage, reporting quota errors instead of panicking
+func (l *LocalStorageBackend) WriteFile(name string, data []byte) (err error) {
+	storage := js.Global().Get("localStorage")
+	if !storage.Truthy() {
+		return fmt.Errorf("localStorage unavailable")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("writing %s to localStorage: %v", name, r)
+		}
+	}()
+	storage.Call("setItem", l.Prefix+name, base64.StdEncoding.EncodeToString(data))
+	return nil
+}
+
+// AppendFile appends to a file in localStorage; values can only be replaced, so the
+// file is read back and rewritten
+func (l *LocalStorageBackend) AppendFile(name string, data []byte) error {
+	existing, err := l.ReadFile(name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return l.WriteFile(name, append(existing, data...))
+}
+
+// ListFiles returns the keys stored directly under dir
+func (l *LocalStorageBackend) ListFiles(dir string) ([]string, error) {
+	storage := js.Global().Get("localStorage")
+	if !storage.Truthy() {
+		return nil, nil
+	}
+	prefix := l.Prefix + dir + "/"
+	var names []string
+	for i := 0; i < storage.Get("length").Int(); i++ {
+		key := storage.Call("key", i).String()
+		if rest, ok := strings.CutPrefix(key, prefix); ok && !strings.Contains(rest, "/") {
+			names = append(names, dir+"/"+rest)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// RemoveFile deletes a key from localStorage
+func (l *LocalStorageBackend) RemoveFile(name string) error {
+	storage := js.Global().Get("localStorage")
+	if storage.Truthy() {
+		storage.Call("removeItem", l.Prefix+name)
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !js || !wasm
+
+package storage
+
+// NewDefaultBackend returns a directory backend rooted at root on native builds
+func NewDefaultBackend(root string) Backend {
+	return NewDirBackend(root)
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ManifestFormatVersion is the version of the world manifest layout. Version 2
+// saves inventories as item slots rather than block counts.
+const ManifestFormatVersion = 2
+
+// manifestFileName is the backend file holding the world manifest
+const manifestFileName = "world.json"
+
+// WorldManifest describes a saved world; chunk data lives separately in region files
+type WorldManifest struct {
+	FormatVersion    int            `json:"formatVersion"`
+	Seed             int64          `json:"seed"`
+	GeneratorVersion int            `json:"generatorVersion"`
+	Spawn            Position       `json:"spawn"`
+	Time             uint64         `json:"time"`                // World clock ticks
+	DayLength        uint64         `json:"dayLength,omitempty"` // Ticks per day; 0 in saves from before the clock
+	Players          []PlayerRecord `json:"players"`
+}
+
+// Position is a point in world pixel coordinates
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// PlayerRecord is the saved state of one player. Items are stored by name, like
+// chunk palettes, so inventories survive registry reordering.
+type PlayerRecord struct {
+	Name         string       `json:"name"`
+	Position     Position     `json:"position"`
+	Velocity     Position     `json:"velocity"`
+	Health       int          `json:"health"`
+	MaxHealth    int          `json:"maxHealth"`
+	SelectedSlot int          `json:"selectedSlot"`
+	Slots        []SlotRecord `json:"slots"`
+	Cursor       *SlotRecord  `json:"cursor,omitempty"` // Stack carried on the inventory screen; Slot is unused
+
+	// Version 1 inventories: block counts by name and the hotbar's block names
+	SelectedBlock string         `json:"selectedBlock,omitempty"`
+	Inventory     map[string]int `json:"inventory,omitempty"`
+	Hotbar        []string       `json:"hotbar,omitempty"`
+}
+
+// SlotRecord is one non-empty inventory slot
+type SlotRecord struct {
+	Slot   int               `json:"slot"`
+	Item   string            `json:"item"`
+	Count  int               `json:"count"`
+	Damage int               `json:"damage,omitempty"`
+	Meta   map[string]string `json:"meta,omitempty"`
+}
+
+// SaveManifest writes the world manifest to the backend
+func SaveManifest(backend Backend, manifest *WorldManifest) error {
+	manifest.FormatVersion = ManifestFormatVersion
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return backend.WriteFile(manifestFileName, data)
+}
+
+// LoadManifest reads the world manifest from the backend. The returned error wraps
+// fs.ErrNotExist when no world has been saved there.
+func LoadManifest(backend Backend) (*WorldManifest, error) {
+	data, err := backend.ReadFile(manifestFileName)
+	if err != nil {
+		return nil, err
+	}
+	var manifest WorldManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing world manifest: %w", err)
+	}
+	if manifest.FormatVersion > ManifestFormatVersion {
+		return nil, fmt.Errorf("world manifest version %d is newer than supported version %d", manifest.FormatVersion, ManifestFormatVersion)
+	}
+	return &manifest, nil
+}
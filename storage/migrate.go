@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+)
+
+// BlockFormatVersion is the version written into every saved chunk. Bump it
+// whenever block names change meaning, and register a migration for the old version.
+// Version 2 added liquid fill levels after the block runs.
+const BlockFormatVersion = 2
+
+// BlockMigration maps a block name saved under one format version to its name in
+// the next version. Returning the name unchanged keeps the block as-is.
+type BlockMigration func(name string) string
+
+var (
+	migrations      = make(map[uint16]BlockMigration)
+	migrationsMutex sync.RWMutex
+)
+
+// RegisterBlockMigration installs the migration that upgrades chunks saved with
+// format version `from` to version `from+1`
+func RegisterBlockMigration(from uint16, migration BlockMigration) {
+	migrationsMutex.Lock()
+	defer migrationsMutex.Unlock()
+	migrations[from] = migration
+}
+
+// migrateBlockName runs a saved block name through every migration between the
+// version it was written with and the current BlockFormatVersion
+func migrateBlockName(name string, version uint16) string {
+	migrationsMutex.RLock()
+	defer migrationsMutex.RUnlock()
+	for v := version; v < BlockFormatVersion; v++ {
+		if migration, ok := migrations[v]; ok {
+			name = migration(name)
+		}
+	}
+	return name
+}
+
+// resolveBlockName turns a (migrated) block name into the current BlockType.
+// Names no longer known to the game fall back to Air.
+func resolveBlockName(name string) coretypes.BlockType {
+	if blockType, ok := coretypes.BlockTypeByName(name); ok {
+		return blockType
+	}
+	fmt.Printf("STORAGE: Unknown block %q in saved chunk, replacing with Air\n", name)
+	return coretypes.Air
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+// Region file layout (all integers little-endian):
+//
+//	magic       [4]byte  "WCRF"
+//	version     uint16   regionFormatVersion
+//	regionX     int32
+//	regionY     int32
+//	compression uint8    compressionZlib
+//	seed        int64    world seed the chunks belong to (version 2 and up)
+//	table       [RegionSize*RegionSize]{offset uint32, length uint32}
+//	payloads    compressed chunk payloads referenced by the table
+//
+// A table entry with length 0 means the chunk has never been saved.
+const (
+	regionMagic         = "WCRF"
+	regionFormatVersion = 2
+
+	compressionNone = 0
+	compressionZlib = 1
+
+	regionHeaderSizeV1 = 4 + 2 + 4 + 4 + 1
+	regionHeaderSize   = regionHeaderSizeV1 + 8
+)
+
+// region holds the (uncompressed) chunk payloads of one region file in memory
+type region struct {
+	X, Y     int32
+	Seed     int64
+	HasSeed  bool // False for version 1 files, which didn't record the seed
+	payloads [settings.RegionSize * settings.RegionSize][]byte
+}
+
+// slot returns the table index for a chunk's local coordinates within the region
+func slot(localX, localY int) int {
+	return localY*settings.RegionSize + localX
+}
+
+// encode serializes the region, compressing every stored chunk payload
+func (r *region) encode() ([]byte, error) {
+	var header bytes.Buffer
+	header.WriteString(regionMagic)
+	binary.Write(&header, binary.LittleEndian, uint16(regionFormatVersion))
+	binary.Write(&header, binary.LittleEndian, r.X)
+	binary.Write(&header, binary.LittleEndian, r.Y)
+	header.WriteByte(compressionZlib)
+	binary.Write(&header, binary.LittleEndian, r.Seed)
+
+	tableSize := len(r.payloads) * 8
+	var body bytes.Buffer
+	table := make([]byte, tableSize)
+	offset := uint32(regionHeaderSize + tableSize)
+
+	for i, payload := range r.payloads {
+		if payload == nil {
+			continue
+		}
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(payload); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		binary.LittleEndian.PutUint32(table[i*8:], offset)
+		binary.LittleEndian.PutUint32(table[i*8+4:], uint32(compressed.Len()))
+		offset += uint32(compressed.Len())
+		body.Write(compressed.Bytes())
+	}
+
+	header.Write(table)
+	header.Write(body.Bytes())
+	return header.Bytes(), nil
+}
+
+// decodeRegion parses a region file and decompresses all chunk payloads it contains
+func decodeRegion(data []byte) (*region, error) {
+	tableSize := settings.RegionSize * settings.RegionSize * 8
+	if len(data) < regionHeaderSizeV1 {
+		return nil, errors.New("region file truncated")
+	}
+	if string(data[:4]) != regionMagic {
+		return nil, errors.New("not a region file")
+	}
+	version := binary.LittleEndian.Uint16(data[4:])
+	if version > regionFormatVersion {
+		return nil, fmt.Errorf("region format version %d is newer than supported version %d", version, regionFormatVersion)
+	}
+	headerSize := regionHeaderSize
+	if version < 2 {
+		headerSize = regionHeaderSizeV1
+	}
+	if len(data) < headerSize+tableSize {
+		return nil, errors.New("region file truncated")
+	}
+
+	r := &region{
+		X: int32(binary.LittleEndian.Uint32(data[6:])),
+		Y: int32(binary.LittleEndian.Uint32(data[10:])),
+	}
+	compression := data[14]
+	if version >= 2 {
+		r.Seed, r.HasSeed = int64(binary.LittleEndian.Uint64(data[15:])), true
+	}
+	table := data[headerSize : headerSize+tableSize]
+
+	for i := range r.payloads {
+		offset := binary.LittleEndian.Uint32(table[i*8:])
+		length := binary.LittleEndian.Uint32(table[i*8+4:])
+		if length == 0 {
+			continue
+		}
+		if uint64(offset)+uint64(length) > uint64(len(data)) {
+			return nil, fmt.Errorf("chunk %d points outside region file", i)
+		}
+		raw := data[offset : offset+length]
+
+		switch compression {
+		case compressionNone:
+			r.payloads[i] = append([]byte(nil), raw...)
+		case compressionZlib:
+			zr, err := zlib.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				return nil, fmt.Errorf("chunk %d: %w", i, err)
+			}
+			payload, err := io.ReadAll(zr)
+			zr.Close()
+			if err != nil {
+				return nil, fmt.Errorf("chunk %d: %w", i, err)
+			}
+			r.payloads[i] = payload
+		default:
+			return nil, fmt.Errorf("unknown region compression %d", compression)
+		}
+	}
+
+	return r, nil
+}
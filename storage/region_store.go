@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sync"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+type regionCoord struct {
+	X, Y int
+}
+
+// RegionStore implements coretypes.ChunkStore by grouping chunks into region files
+type RegionStore struct {
+	backend Backend
+	seed    int64 // Seed of the world the chunks belong to
+	regions map[regionCoord]*region
+	mutex   sync.Mutex
+}
+
+// NewRegionStore creates a chunk store that keeps a world's region files in the given
+// backend. Region files written for a different seed are refused rather than mixed in.
+func NewRegionStore(backend Backend, seed int64) *RegionStore {
+	return &RegionStore{
+		backend: backend,
+		seed:    seed,
+		regions: make(map[regionCoord]*region),
+	}
+}
+
+// regionFor returns the region containing a chunk and the chunk's slot within it
+func regionFor(coord coretypes.ChunkCoord) (regionCoord, int) {
+	rc := regionCoord{
+		X: coretypes.FloorDiv(coord.X, settings.RegionSize),
+		Y: coretypes.FloorDiv(coord.Y, settings.RegionSize),
+	}
+	localX := coord.X - rc.X*settings.RegionSize
+	localY := coord.Y - rc.Y*settings.RegionSize
+	return rc, slot(localX, localY)
+}
+
+// regionFileName returns the backend file name used for a region
+func regionFileName(rc regionCoord) string {
+	return path.Join(settings.RegionDirectory, fmt.Sprintf("r.%d.%d.wcr", rc.X, rc.Y))
+}
+
+// loadRegion returns the cached region, reading it from the backend on first use.
+// Must be called with the mutex held.
+func (s *RegionStore) loadRegion(rc regionCoord) (*region, error) {
+	if r, ok := s.regions[rc]; ok {
+		return r, nil
+	}
+
+	data, err := s.backend.ReadFile(regionFileName(rc))
+	if errors.Is(err, fs.ErrNotExist) {
+		r := &region{X: int32(rc.X), Y: int32(rc.Y), Seed: s.seed, HasSeed: true}
+		s.regions[rc] = r
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := decodeRegion(data)
+	if err != nil {
+		return nil, fmt.Errorf("region %d,%d: %w", rc.X, rc.Y, err)
+	}
+	if r.HasSeed && r.Seed != s.seed {
+		return nil, fmt.Errorf("region %d,%d belongs to a world with seed %d, not %d", rc.X, rc.Y, r.Seed, s.seed)
+	}
+	r.Seed, r.HasSeed = s.seed, true
+	s.regions[rc] = r
+	return r, nil
+}
+
+// LoadChunk returns the saved copy of a chunk, or false if it was never saved
+func (s *RegionStore) LoadChunk(coord coretypes.ChunkCoord) (*coretypes.Chunk, bool, error) {
+	rc, idx := regionFor(coord)
+
+	s.mutex.Lock()
+	r, err := s.loadRegion(rc)
+	var payload []byte
+	if err == nil {
+		payload = r.payloads[idx]
+	}
+	s.mutex.Unlock()
+
+	if err != nil || payload == nil {
+		return nil, false, err
+	}
+
+	chunk, err := DecodeChunk(payload)
+	if err != nil {
+		return nil, false, fmt.Errorf("chunk %d,%d: %w", coord.X, coord.Y, err)
+	}
+	return chunk, true, nil
+}
+
+// SaveChunk stores a chunk and rewrites its region file
+func (s *RegionStore) SaveChunk(coord coretypes.ChunkCoord, chunk *coretypes.Chunk) error {
+	payload := EncodeChunk(chunk)
+	rc, idx := regionFor(coord)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	r, err := s.loadRegion(rc)
+	if err != nil {
+		return err
+	}
+	r.payloads[idx] = payload
+
+	data, err := r.encode()
+	if err != nil {
+		return err
+	}
+	return s.backend.WriteFile(regionFileName(rc), data)
+}
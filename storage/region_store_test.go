@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/KdntNinja/webcraft/coretypes"
+	"github.com/KdntNinja/webcraft/settings"
+)
+
+func TestRegionStoreRefusesOtherSeeds(t *testing.T) {
+	backend := NewDirBackend(t.TempDir())
+	coord := coretypes.ChunkCoord{X: -3, Y: 2}
+	if err := NewRegionStore(backend, 1).SaveChunk(coord, testChunk()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := NewRegionStore(backend, 2).LoadChunk(coord); err == nil {
+		t.Fatal("loaded a chunk saved for another seed")
+	}
+	if err := NewRegionStore(backend, 2).SaveChunk(coord, testChunk()); err == nil {
+		t.Fatal("saved a chunk into a region file of another seed")
+	}
+	if _, ok, err := NewRegionStore(backend, 1).LoadChunk(coord); err != nil || !ok {
+		t.Fatalf("chunk lost from its own world: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestRegionStoreRoundTrip(t *testing.T) {
+	backend := NewDirBackend(t.TempDir())
+	store := NewRegionStore(backend, 9)
+
+	// Spread over several region files, negative coordinates included
+	coords := []coretypes.ChunkCoord{{X: 0, Y: 0}, {X: -1, Y: 0}, {X: settings.RegionSize, Y: 1}, {X: -settings.RegionSize - 1, Y: -2}}
+	for i, coord := range coords {
+		chunk := testChunk()
+		chunk.Blocks[0][i] = coretypes.Torch
+		if err := store.SaveChunk(coord, chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A fresh store only has the files to go on
+	reopened := NewRegionStore(backend, 9)
+	for i, coord := range coords {
+		want := testChunk()
+		want.Blocks[0][i] = coretypes.Torch
+		got, ok, err := reopened.LoadChunk(coord)
+		if err != nil || !ok {
+			t.Fatalf("chunk %v: ok=%v err=%v", coord, ok, err)
+		}
+		if !sameChunk(got, want) {
+			t.Fatalf("chunk %v changed on disk", coord)
+		}
+	}
+	if _, ok, err := reopened.LoadChunk(coretypes.ChunkCoord{X: 1, Y: 0}); ok || err != nil {
+		t.Fatalf("unsaved chunk loaded: ok=%v err=%v", ok, err)
+	}
+}
@@ -1,18 +1,31 @@
+//go:build !js || !wasm
+
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
+
+	"github.com/KdntNinja/webcraft/server"
+	"github.com/KdntNinja/webcraft/settings"
 )
 
 func main() {
+	gameServer := flag.Bool("game", false, "also run the multiplayer game server on "+settings.ServerWebSocketPath)
+	seed := flag.Int64("seed", time.Now().UnixNano(), "world seed used when the server has no saved world")
+	flag.Parse()
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "3000"
@@ -27,6 +40,10 @@ func main() {
 	buildDir := "web/build"
 	fs := http.FileServer(http.Dir(buildDir))
 
+	if *gameServer {
+		startGameServer(*seed)
+	}
+
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		// Log the request with more detail
@@ -150,3 +167,22 @@ func main() {
 		break
 	}
 }
+
+// startGameServer mounts the multiplayer server next to the static files. The world
+// is saved periodically and once more on Ctrl+C or SIGTERM.
+func startGameServer(seed int64) {
+	srv, err := server.New(seed)
+	if err != nil {
+		log.Fatalf("Failed to start game server: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		srv.Run(ctx) // Saves before returning
+		stop()
+		os.Exit(0)
+	}()
+
+	http.Handle(settings.ServerWebSocketPath, srv.Handler())
+	fmt.Printf("\033[1;32mGame server\033[0m accepting players on \033[1;36m%s\033[0m (open the page with ?multiplayer)\n", settings.ServerWebSocketPath)
+}
@@ -18,12 +18,12 @@ type SpawnPoint struct {
 }
 
 // FindSpawnPoint finds a suitable spawn point near the world center (0, 0)
-func FindSpawnPoint() SpawnPoint {
+func FindSpawnPoint(generator *generation.Generator) SpawnPoint {
 	// Start looking near world center (0, 0)
 	searchBlockX := 0
 
 	// Find surface height at the center
-	surfaceY := generation.GetHeightAt(searchBlockX)
+	surfaceY := generator.GetHeightAt(searchBlockX)
 
 	// Ensure reasonable spawn height
 	spawnBlockY := surfaceY - 3 // 3 blocks above surface
@@ -58,12 +58,12 @@ func FindSpawnPoint() SpawnPoint {
 }
 
 // FindSafeSpawnPoint finds a spawn point that's guaranteed to be safe (not in a cave, etc.)
-func FindSafeSpawnPoint() SpawnPoint {
+func FindSafeSpawnPoint(generator *generation.Generator) SpawnPoint {
 	// Center the player in the middle of the world horizontally
 	centerChunkX := settings.WorldChunksX / 2
 	searchX := centerChunkX * settings.ChunkWidth
 
-	surfaceY := generation.GetHeightAt(searchX)
+	surfaceY := generator.GetHeightAt(searchX)
 
 	// Make sure it's a reasonable surface height
 	if surfaceY < 5 {